@@ -0,0 +1,580 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.15.6
+// source: api/proxy/forecast.proto
+
+package proxy
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ForecastModels struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version           string                     `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	LabelModels       map[string]*ActionModel    `protobuf:"bytes,2,rep,name=label_models,json=labelModels,proto3" json:"label_models,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	CommandModels     map[string]*ActionModel    `protobuf:"bytes,3,rep,name=command_models,json=commandModels,proto3" json:"command_models,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	DownloadLatencies map[string]*LatencyDataset `protobuf:"bytes,4,rep,name=download_latencies,json=downloadLatencies,proto3" json:"download_latencies,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *ForecastModels) Reset() {
+	*x = ForecastModels{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proxy_forecast_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForecastModels) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForecastModels) ProtoMessage() {}
+
+func (x *ForecastModels) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proxy_forecast_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForecastModels.ProtoReflect.Descriptor instead.
+func (*ForecastModels) Descriptor() ([]byte, []int) {
+	return file_api_proxy_forecast_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ForecastModels) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ForecastModels) GetLabelModels() map[string]*ActionModel {
+	if x != nil {
+		return x.LabelModels
+	}
+	return nil
+}
+
+func (x *ForecastModels) GetCommandModels() map[string]*ActionModel {
+	if x != nil {
+		return x.CommandModels
+	}
+	return nil
+}
+
+func (x *ForecastModels) GetDownloadLatencies() map[string]*LatencyDataset {
+	if x != nil {
+		return x.DownloadLatencies
+	}
+	return nil
+}
+
+type LatencyDataset struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LatenciesMs []int64                `protobuf:"varint,1,rep,packed,name=latencies_ms,json=latenciesMs,proto3" json:"latencies_ms,omitempty"`
+	LastUpdated *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+}
+
+func (x *LatencyDataset) Reset() {
+	*x = LatencyDataset{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proxy_forecast_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LatencyDataset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencyDataset) ProtoMessage() {}
+
+func (x *LatencyDataset) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proxy_forecast_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencyDataset.ProtoReflect.Descriptor instead.
+func (*LatencyDataset) Descriptor() ([]byte, []int) {
+	return file_api_proxy_forecast_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LatencyDataset) GetLatenciesMs() []int64 {
+	if x != nil {
+		return x.LatenciesMs
+	}
+	return nil
+}
+
+func (x *LatencyDataset) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+type ActionModel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CacheHitRate       float64                `protobuf:"fixed64,1,opt,name=cache_hit_rate,json=cacheHitRate,proto3" json:"cache_hit_rate,omitempty"`
+	CacheLookups       int64                  `protobuf:"varint,2,opt,name=cache_lookups,json=cacheLookups,proto3" json:"cache_lookups,omitempty"`
+	RemoteLatenciesMs  []int64                `protobuf:"varint,3,rep,packed,name=remote_latencies_ms,json=remoteLatenciesMs,proto3" json:"remote_latencies_ms,omitempty"`
+	LocalLatenciesMs   []int64                `protobuf:"varint,4,rep,packed,name=local_latencies_ms,json=localLatenciesMs,proto3" json:"local_latencies_ms,omitempty"`
+	LastUpdated        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	ExecuteLatenciesMs []int64                `protobuf:"varint,6,rep,packed,name=execute_latencies_ms,json=executeLatenciesMs,proto3" json:"execute_latencies_ms,omitempty"`
+}
+
+func (x *ActionModel) Reset() {
+	*x = ActionModel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proxy_forecast_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ActionModel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionModel) ProtoMessage() {}
+
+func (x *ActionModel) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proxy_forecast_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionModel.ProtoReflect.Descriptor instead.
+func (*ActionModel) Descriptor() ([]byte, []int) {
+	return file_api_proxy_forecast_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ActionModel) GetCacheHitRate() float64 {
+	if x != nil {
+		return x.CacheHitRate
+	}
+	return 0
+}
+
+func (x *ActionModel) GetCacheLookups() int64 {
+	if x != nil {
+		return x.CacheLookups
+	}
+	return 0
+}
+
+func (x *ActionModel) GetRemoteLatenciesMs() []int64 {
+	if x != nil {
+		return x.RemoteLatenciesMs
+	}
+	return nil
+}
+
+func (x *ActionModel) GetLocalLatenciesMs() []int64 {
+	if x != nil {
+		return x.LocalLatenciesMs
+	}
+	return nil
+}
+
+func (x *ActionModel) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+func (x *ActionModel) GetExecuteLatenciesMs() []int64 {
+	if x != nil {
+		return x.ExecuteLatenciesMs
+	}
+	return nil
+}
+
+type ResourceModels struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version     string                    `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	LabelModels map[string]*ResourceModel `protobuf:"bytes,2,rep,name=label_models,json=labelModels,proto3" json:"label_models,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *ResourceModels) Reset() {
+	*x = ResourceModels{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proxy_forecast_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResourceModels) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceModels) ProtoMessage() {}
+
+func (x *ResourceModels) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proxy_forecast_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceModels.ProtoReflect.Descriptor instead.
+func (*ResourceModels) Descriptor() ([]byte, []int) {
+	return file_api_proxy_forecast_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ResourceModels) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ResourceModels) GetLabelModels() map[string]*ResourceModel {
+	if x != nil {
+		return x.LabelModels
+	}
+	return nil
+}
+
+type ResourceModel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PeakRssMbs  []int64                `protobuf:"varint,1,rep,packed,name=peak_rss_mbs,json=peakRssMbs,proto3" json:"peak_rss_mbs,omitempty"`
+	CpuMillis   []int64                `protobuf:"varint,2,rep,packed,name=cpu_millis,json=cpuMillis,proto3" json:"cpu_millis,omitempty"`
+	LastUpdated *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+}
+
+func (x *ResourceModel) Reset() {
+	*x = ResourceModel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proxy_forecast_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResourceModel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceModel) ProtoMessage() {}
+
+func (x *ResourceModel) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proxy_forecast_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceModel.ProtoReflect.Descriptor instead.
+func (*ResourceModel) Descriptor() ([]byte, []int) {
+	return file_api_proxy_forecast_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ResourceModel) GetPeakRssMbs() []int64 {
+	if x != nil {
+		return x.PeakRssMbs
+	}
+	return nil
+}
+
+func (x *ResourceModel) GetCpuMillis() []int64 {
+	if x != nil {
+		return x.CpuMillis
+	}
+	return nil
+}
+
+func (x *ResourceModel) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+var File_api_proxy_forecast_proto protoreflect.FileDescriptor
+
+var file_api_proxy_forecast_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2f, 0x66, 0x6f, 0x72, 0x65,
+	0x63, 0x61, 0x73, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x78,
+	0x79, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0xaa, 0x04, 0x0a, 0x0e, 0x46, 0x6f, 0x72, 0x65, 0x63, 0x61, 0x73, 0x74, 0x4d,
+	0x6f, 0x64, 0x65, 0x6c, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x49, 0x0a, 0x0c, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2e, 0x46, 0x6f,
+	0x72, 0x65, 0x63, 0x61, 0x73, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x12, 0x4f, 0x0a, 0x0e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x28, 0x2e, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2e, 0x46, 0x6f, 0x72, 0x65, 0x63,
+	0x61, 0x73, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x12, 0x5b, 0x0a, 0x12, 0x64,
+	0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x69, 0x65,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2e,
+	0x46, 0x6f, 0x72, 0x65, 0x63, 0x61, 0x73, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x44,
+	0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x11, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4c,
+	0x61, 0x74, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x1a, 0x52, 0x0a, 0x10, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x28,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x70, 0x72, 0x6f, 0x78, 0x79, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65,
+	0x6c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x54, 0x0a, 0x12,
+	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2e, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x1a, 0x5b, 0x0a, 0x16, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x70, 0x72, 0x6f, 0x78, 0x79, 0x2e, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x44, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0x72, 0x0a, 0x0e, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65,
+	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x5f, 0x6d,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x03, 0x52, 0x0b, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x69,
+	0x65, 0x73, 0x4d, 0x73, 0x12, 0x3d, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x64, 0x22, 0xa7, 0x02, 0x0a, 0x0b, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x6f,
+	0x64, 0x65, 0x6c, 0x12, 0x24, 0x0a, 0x0e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x68, 0x69, 0x74,
+	0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x63, 0x61, 0x63,
+	0x68, 0x65, 0x48, 0x69, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x61, 0x63,
+	0x68, 0x65, 0x5f, 0x6c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0c, 0x63, 0x61, 0x63, 0x68, 0x65, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x73, 0x12, 0x2e,
+	0x0a, 0x13, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x69,
+	0x65, 0x73, 0x5f, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x03, 0x52, 0x11, 0x72, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x4d, 0x73, 0x12, 0x2c,
+	0x0a, 0x12, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x69, 0x65,
+	0x73, 0x5f, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x03, 0x52, 0x10, 0x6c, 0x6f, 0x63, 0x61,
+	0x6c, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x4d, 0x73, 0x12, 0x3d, 0x0a, 0x0c,
+	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b,
+	0x6c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x12, 0x30, 0x0a, 0x14, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73,
+	0x5f, 0x6d, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x03, 0x52, 0x12, 0x65, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x65, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x4d, 0x73, 0x22, 0xcb, 0x01,
+	0x0a, 0x0e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x49, 0x0a, 0x0c, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4d, 0x6f, 0x64,
+	0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x4d,
+	0x6f, 0x64, 0x65, 0x6c, 0x73, 0x1a, 0x54, 0x0a, 0x10, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x4d, 0x6f,
+	0x64, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f,
+	0x78, 0x79, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x6c,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x8f, 0x01, 0x0a, 0x0d,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x20, 0x0a,
+	0x0c, 0x70, 0x65, 0x61, 0x6b, 0x5f, 0x72, 0x73, 0x73, 0x5f, 0x6d, 0x62, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x03, 0x52, 0x0a, 0x70, 0x65, 0x61, 0x6b, 0x52, 0x73, 0x73, 0x4d, 0x62, 0x73, 0x12,
+	0x1d, 0x0a, 0x0a, 0x63, 0x70, 0x75, 0x5f, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x03, 0x52, 0x09, 0x63, 0x70, 0x75, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x12, 0x3d,
+	0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x42, 0x2a, 0x5a,
+	0x28, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x61, 0x7a, 0x65,
+	0x6c, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2f, 0x72, 0x65, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2f,
+	0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_api_proxy_forecast_proto_rawDescOnce sync.Once
+	file_api_proxy_forecast_proto_rawDescData = file_api_proxy_forecast_proto_rawDesc
+)
+
+func file_api_proxy_forecast_proto_rawDescGZIP() []byte {
+	file_api_proxy_forecast_proto_rawDescOnce.Do(func() {
+		file_api_proxy_forecast_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_proxy_forecast_proto_rawDescData)
+	})
+	return file_api_proxy_forecast_proto_rawDescData
+}
+
+var file_api_proxy_forecast_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_api_proxy_forecast_proto_goTypes = []interface{}{
+	(*ForecastModels)(nil),        // 0: proxy.ForecastModels
+	(*LatencyDataset)(nil),        // 1: proxy.LatencyDataset
+	(*ActionModel)(nil),           // 2: proxy.ActionModel
+	(*ResourceModels)(nil),        // 3: proxy.ResourceModels
+	(*ResourceModel)(nil),         // 4: proxy.ResourceModel
+	nil,                           // 5: proxy.ForecastModels.LabelModelsEntry
+	nil,                           // 6: proxy.ForecastModels.CommandModelsEntry
+	nil,                           // 7: proxy.ForecastModels.DownloadLatenciesEntry
+	nil,                           // 8: proxy.ResourceModels.LabelModelsEntry
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_api_proxy_forecast_proto_depIdxs = []int32{
+	5,  // 0: proxy.ForecastModels.label_models:type_name -> proxy.ForecastModels.LabelModelsEntry
+	6,  // 1: proxy.ForecastModels.command_models:type_name -> proxy.ForecastModels.CommandModelsEntry
+	7,  // 2: proxy.ForecastModels.download_latencies:type_name -> proxy.ForecastModels.DownloadLatenciesEntry
+	9,  // 3: proxy.LatencyDataset.last_updated:type_name -> google.protobuf.Timestamp
+	9,  // 4: proxy.ActionModel.last_updated:type_name -> google.protobuf.Timestamp
+	8,  // 5: proxy.ResourceModels.label_models:type_name -> proxy.ResourceModels.LabelModelsEntry
+	9,  // 6: proxy.ResourceModel.last_updated:type_name -> google.protobuf.Timestamp
+	2,  // 7: proxy.ForecastModels.LabelModelsEntry.value:type_name -> proxy.ActionModel
+	2,  // 8: proxy.ForecastModels.CommandModelsEntry.value:type_name -> proxy.ActionModel
+	1,  // 9: proxy.ForecastModels.DownloadLatenciesEntry.value:type_name -> proxy.LatencyDataset
+	4,  // 10: proxy.ResourceModels.LabelModelsEntry.value:type_name -> proxy.ResourceModel
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_api_proxy_forecast_proto_init() }
+func file_api_proxy_forecast_proto_init() {
+	if File_api_proxy_forecast_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_proxy_forecast_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForecastModels); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proxy_forecast_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LatencyDataset); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proxy_forecast_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActionModel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proxy_forecast_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceModels); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proxy_forecast_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceModel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_proxy_forecast_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proxy_forecast_proto_goTypes,
+		DependencyIndexes: file_api_proxy_forecast_proto_depIdxs,
+		MessageInfos:      file_api_proxy_forecast_proto_msgTypes,
+	}.Build()
+	File_api_proxy_forecast_proto = out.File
+	file_api_proxy_forecast_proto_rawDesc = nil
+	file_api_proxy_forecast_proto_goTypes = nil
+	file_api_proxy_forecast_proto_depIdxs = nil
+}
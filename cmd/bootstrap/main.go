@@ -81,6 +81,8 @@ var (
 	logHTTPCalls                      = flag.Bool("log_http_calls", false, "Log all http requests made with the default http client.")
 	experimentalCredentialsHelper     = flag.String(auth.CredshelperPathFlag, "", "Path to the credentials helper binary. If given execrel://, looks for the `credshelper` binary in the same folder as bootstrap")
 	experimentalCredentialsHelperArgs = flag.String(auth.CredshelperArgsFlag, "", "Arguments for the experimental credentials helper, separated by space.")
+	daemon                            = flag.Bool("daemon", false, "Whether to keep reproxy running across builds to reuse its warm caches. Bootstrap attaches to a running reproxy of the same version and flags instead of starting a new one, and --shutdown only collects the stats of --invocation_id. The reproxy exits after --proxy_idle_timeout of inactivity.")
+	invocationID                      = flag.String("invocation_id", "", "The invocation ID of the build, used with --daemon to collect the stats of the build on --shutdown.")
 )
 
 func main() {
@@ -149,15 +151,24 @@ func main() {
 			EventTimes: map[string]*cpb.TimeInterval{},
 			Metrics:    map[string]*lpb.Metric{},
 		}
-		s, err := shutdownReproxy()
-		if err != nil {
+		var s *spb.Stats
+		var err error
+		if *daemon {
+			if s, err = finishDaemonInvocation(); err != nil {
+				log.Warningf("Error collecting the stats of invocation %q from daemon reproxy: %v", *invocationID, err)
+			}
+		} else if s, err = shutdownReproxy(); err != nil {
 			log.Warningf("Error shutting down reproxy: %v", err)
 		}
 		if *outputDir == "" {
 			log.Fatal("Must provide an output directory.")
 		}
+		// The logs of a daemon reproxy contain the records of other builds.
+		if *daemon && s == nil {
+			return
+		}
 		// Fallback on reading the rpl file if no stats are returned from reproxy
-		if !*fastLogCollection || s == nil {
+		if statsFromLogs(*daemon, *fastLogCollection, s) {
 			if *logPath == "" && len(proxyLogDir) == 0 {
 				return
 			}
@@ -219,7 +230,9 @@ func main() {
 	}
 	args = append(args, "--creds_file="+cf)
 
-	if *fastLogCollection {
+	// A daemon reproxy is never sent a Shutdown rpc and must exit on the first signal sent on idle
+	// timeout.
+	if *fastLogCollection && !*daemon {
 		args = append(args, "--wait_for_shutdown_rpc=true")
 	}
 
@@ -228,7 +241,24 @@ func main() {
 	if *experimentalCredentialsHelper != "" {
 		currArgs = append(currArgs, "--use_external_auth_token=true")
 	}
+	var flagsDigest string
+	if *daemon {
+		if flagsDigest, err = bootstrap.FlagsDigest(currArgs); err != nil {
+			log.Warningf("Failed to compute the reproxy flags digest, starting a new reproxy: %v", err)
+		} else if err := bootstrap.AttachProxy(context.Background(), *serverAddr, flagsDigest); err != nil {
+			log.Infof("Not attaching to a running reproxy, starting a new one: %v", err)
+		} else {
+			fmt.Println("Attached to running proxy.")
+			log.Flush()
+			os.Exit(0)
+		}
+	}
 	msg, exitCode := bootstrapReproxy(currArgs, bootstrapStart)
+	if exitCode == 0 && flagsDigest != "" {
+		if err := bootstrap.MarkDaemon(*serverAddr, flagsDigest); err != nil {
+			log.Warningf("Failed to mark reproxy as a daemon, later builds will not attach to it: %v", err)
+		}
+	}
 	if exitCode == 0 {
 		fmt.Println(msg)
 	} else {
@@ -291,6 +321,13 @@ func defaultMetricsUploader() string {
 	return metricsUploader
 }
 
+// statsFromLogs returns whether the stats of the build are generated from the reproxy logs rather
+// than taken from the stats s returned by reproxy. The stats returned by a daemon reproxy are
+// always used, since its logs contain the records of other builds.
+func statsFromLogs(daemon, fastLogCollection bool, s *spb.Stats) bool {
+	return s == nil || (!daemon && !fastLogCollection)
+}
+
 func shutdownReproxy() (*spb.Stats, error) {
 	if *asyncReproxyShutdown {
 		// On shutdown we may not want to wait for deps cache to finish writing
@@ -305,6 +342,13 @@ func shutdownReproxy() (*spb.Stats, error) {
 	return s, err
 }
 
+func finishDaemonInvocation() (*spb.Stats, error) {
+	if *invocationID == "" {
+		return nil, fmt.Errorf("--invocation_id is required to collect the stats of a build from a daemon reproxy")
+	}
+	return bootstrap.FinishInvocation(*serverAddr, *invocationID, *shutdownSeconds)
+}
+
 func bootstrapReproxy(args []string, startTime time.Time) (string, int) {
 	if err := bootstrap.StartProxyWithOutput(context.Background(), *serverAddr, *reProxy, *outputDir, *waitSeconds, *shutdownSeconds, startTime, args...); err != nil {
 		defaultErr := fmt.Sprintf("Error bootstrapping remote execution proxy: %v", err)
@@ -0,0 +1,65 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	spb "github.com/bazelbuild/reclient/api/stats"
+)
+
+func TestStatsFromLogs(t *testing.T) {
+	tests := []struct {
+		name              string
+		daemon            bool
+		fastLogCollection bool
+		s                 *spb.Stats
+		want              bool
+	}{
+		{
+			name: "no stats",
+			want: true,
+		},
+		{
+			name: "stats without fast log collection",
+			s:    &spb.Stats{},
+			want: true,
+		},
+		{
+			name:              "stats with fast log collection",
+			fastLogCollection: true,
+			s:                 &spb.Stats{},
+			want:              false,
+		},
+		{
+			name:   "daemon invocation stats",
+			daemon: true,
+			s:      &spb.Stats{},
+			want:   false,
+		},
+		{
+			name:   "no daemon invocation stats",
+			daemon: true,
+			want:   true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := statsFromLogs(tc.daemon, tc.fastLogCollection, tc.s); got != tc.want {
+				t.Errorf("statsFromLogs(%v, %v, %v) = %v, want %v", tc.daemon, tc.fastLogCollection, tc.s, got, tc.want)
+			}
+		})
+	}
+}
@@ -32,10 +32,15 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/bazelbuild/reclient/internal/pkg/actioncache"
 	"github.com/bazelbuild/reclient/internal/pkg/auth"
+	"github.com/bazelbuild/reclient/internal/pkg/cgroups"
+	"github.com/bazelbuild/reclient/internal/pkg/event"
+	"github.com/bazelbuild/reclient/internal/pkg/execpolicy"
 	"github.com/bazelbuild/reclient/internal/pkg/ignoremismatch"
 	"github.com/bazelbuild/reclient/internal/pkg/interceptors"
 	"github.com/bazelbuild/reclient/internal/pkg/ipc"
+	"github.com/bazelbuild/reclient/internal/pkg/localcas"
 	"github.com/bazelbuild/reclient/internal/pkg/localresources"
 	"github.com/bazelbuild/reclient/internal/pkg/localresources/usage"
 	"github.com/bazelbuild/reclient/internal/pkg/logger"
@@ -80,8 +85,12 @@ var (
 	logFormat                = flag.String("log_format", "reducedtext", "Format of proxy log. Currently only text and reducedtext are supported. Defaults to reducedtext.")
 	logPath                  = flag.String("log_path", "", "DEPRECATED. Use proxy_log_dir instead. If provided, the path to a log file of all executed records. The format is e.g. text://full/file/path.")
 	mismatchIgnoreConfigPath = flag.String("mismatch_ignore_config_path", "", "If provided, mismatches will be ignored according to the provided rule config.")
+	execPolicyConfigPath     = flag.String("execution_policy_config_path", "", "If provided, the path to a textproto ExecutionPolicyConfig whose rules override the execution options and platform of matching actions.")
 	enableDepsCache          = flag.Bool("enable_deps_cache", false, "Enables the deps cache if --cache_dir is provided")
 	cacheDir                 = flag.String("cache_dir", "", "Directory from which to load the cache files at startup and update at shutdown.")
+	actionCacheMaxMb         = flag.Int("action_cache_max_mb", 0, "Maximum size in MB of the on-disk cache of the results of LOCAL actions and local fallbacks if --cache_dir is provided. Cached results are used even if remote execution is disabled. 0 disables the cache.")
+	localCASMaxMb            = flag.Int("local_cas_max_mb", 0, "Maximum size in MB of the local content-addressable store that outputs are deduplicated through if --cache_dir is provided. Outputs found in it are materialized locally rather than downloaded. 0 disables the store.")
+	localCASHardlinks        = flag.Bool("local_cas_hardlinks", false, "Materialize outputs from the local content-addressable store as hardlinks rather than copies or reflinks. Outputs are unshared before local execution.")
 	keepRecords              = flag.Int("num_records_to_keep", 0, "The number of last executed records to keep in memory for serving.")
 	// TODO(b/157446611): remove this flag.
 	_                     = flag.String("cpp_dependency_scanner_plugin", "", "Deprecated: Location of the CPP dependency scanner plugin.")
@@ -113,6 +122,22 @@ var (
 	failEarlyMinActionCount   = flag.Int64("fail_early_min_action_count", 0, "Minimum number of actions received by reproxy before the fail early mechanism can take effect. 0 indicates fail early is disabled.")
 	failEarlyMinFallbackRatio = flag.Float64("fail_early_min_fallback_ratio", 0, "Minimum ratio of fallbacks to total actions above which the build terminates early. Ratio is a number in the range [0,1]. 0 indicates fail early is disabled.")
 	failEarlyWindow           = flag.Duration("fail_early_window", 0, "Window of time to consider for fail_early_min_action_count and fail_early_min_fallback_ratio. 0 indicates all datapoints should be used.")
+	invocationIdleTimeout     = flag.Duration("invocation_idle_timeout", time.Hour, "Amount of time without actions after which the fail early state and stats of an invocation that did not call FinishInvocation are forgotten. 0 indicates the state is kept until FinishInvocation is called.")
+	racingMaxHoldoff          = flag.Duration("racing_max_holdoff", time.Minute, "Maximum amount of time to hold off local execution when racing.")
+	dynamicRacingHoldoff      = flag.Bool("dynamic_racing_holdoff", false, "Use cache hit and latency models learned per label and per command to decide when to start local execution when racing. Models are persisted in --cache_dir if provided.")
+	hedgeDelay                = flag.Duration("remote_hedge_default_delay", time.Minute, "Amount of time after which a second remote execution of a remote_hedged action is started until the remote execution latency of such actions is learned. 0 indicates such actions are not hedged.")
+	loadShedMaxErrorRatio     = flag.Float64("load_shed_max_remote_error_ratio", 0, "Ratio of remote executions failing with remote errors or timeouts in load_shed_window above which remote_local_fallback and racing actions are executed locally for load_shed_cooldown. Ratio is a number in the range [0,1]. 0 indicates the condition is disabled.")
+	loadShedMaxLatency        = flag.Duration("load_shed_max_remote_latency", 0, "Average remote execution latency in load_shed_window above which remote_local_fallback and racing actions are executed locally for load_shed_cooldown. 0 indicates the condition is disabled.")
+	loadShedMinActions        = flag.Int64("load_shed_min_remote_actions", 100, "Minimum number of remote executions in load_shed_window before load shedding can take effect.")
+	loadShedWindow            = flag.Duration("load_shed_window", time.Minute, "Window of time to consider for load_shed_max_remote_error_ratio and load_shed_max_remote_latency. 0 indicates all datapoints should be used.")
+	loadShedCooldown          = flag.Duration("load_shed_cooldown", time.Minute, "Amount of time to execute remote_local_fallback and racing actions locally once remote execution is considered degraded, before probing remote execution again.")
+	loadShedProbeRatio        = flag.Float64("load_shed_probe_ratio", 0.1, "Ratio of remote_local_fallback and racing actions executed remotely after load_shed_cooldown to probe whether remote execution recovered. Ratio is a number in the range (0,1].")
+	localCgroups              = flag.Bool("local_cgroups", false, "Linux only. Confine each local action run directly on the host to a cgroup v2. Once the resource requirements of an action's labels are learned, the cgroup is limited to the CPUs reserved for it and local_cgroup_memory_factor times the RAM reserved for it. Actions exceeding their memory limit are killed and reported as local failures. Has no effect if cgroups are not writable.")
+	localCgroupRoot           = flag.String("local_cgroup_root", "", "Delegated cgroup v2 directory without processes of its own under which cgroups for local actions are created if local_cgroups is set. If empty, reproxy moves itself to a leaf cgroup under its current cgroup and creates the cgroups for local actions beside it.")
+	localCgroupMemoryFactor   = flag.Float64("local_cgroup_memory_factor", 2, "Factor applied to the RAM reserved for a local action to get its memory limit if local_cgroups is set.")
+	dynamicLocalResources     = flag.Bool("dynamic_local_resources", false, "Resize the resources available for local execution between local_resource_min_fraction and local_resource_fraction of the machine based on the CPU and memory used by other processes.")
+	localResourceMinFraction  = flag.Float64("local_resource_min_fraction", 0.25, "Number [0,local_resource_fraction] indicating the minimum fraction of the local machine resources available for local execution if dynamic_local_resources is set.")
+	localResourceInterval     = flag.Duration("local_resource_sample_interval", 5*time.Second, "Interval at which the machine load is sampled to resize the resources available for local execution if dynamic_local_resources is set.")
 	racingBias                = flag.Float64("racing_bias", 0.75, "Value between [0,1] to indicate how racing manages the tradeoff of saving bandwidth (0) versus speed (1). The default is to prefer speed over bandwidth.")
 	racingTmp                 = flag.String("racing_tmp_dir", "", "DEPRECATED. Use download_tmp_dir instead.")
 	downloadTmp               = flag.String("download_tmp_dir", "", "Directory where reproxy should store outputs temporarily before moving them to the desired location. This should be on the same device as the output directory for the build. The default is outputs will be written to a subdirectory inside the action's working directory. Note that the download_tmp_dir will only be used if the action has racing as its exec strategy or it explicitly sets EnableAtomicDownloads=true. See proxy.proto for details.")
@@ -147,6 +172,27 @@ func verifyFlags() {
 	if *failEarlyWindow < 0 {
 		log.Exitf("Invalid fail_early_window: %v, want >0", *failEarlyWindow)
 	}
+	if *invocationIdleTimeout < 0 {
+		log.Exitf("Invalid invocation_idle_timeout: %v, want >=0", *invocationIdleTimeout)
+	}
+	if *loadShedMaxErrorRatio < 0 || *loadShedMaxErrorRatio > 1 {
+		log.Exitf("Invalid load_shed_max_remote_error_ratio: %v, want [0,1]", *loadShedMaxErrorRatio)
+	}
+	if *loadShedProbeRatio <= 0 || *loadShedProbeRatio > 1 {
+		log.Exitf("Invalid load_shed_probe_ratio: %v, want (0,1]", *loadShedProbeRatio)
+	}
+	if *loadShedMaxLatency < 0 || *loadShedMinActions < 0 || *loadShedWindow < 0 || *loadShedCooldown < 0 {
+		log.Exitf("Invalid load shedding configuration, load_shed_max_remote_latency, load_shed_min_remote_actions, load_shed_window and load_shed_cooldown must be >=0")
+	}
+	if *localCgroupMemoryFactor < 1 {
+		log.Exitf("Invalid local_cgroup_memory_factor: %v, want >=1", *localCgroupMemoryFactor)
+	}
+	if *localResourceMinFraction < 0 || *localResourceMinFraction > *localResourceFraction {
+		log.Exitf("Invalid local_resource_min_fraction: %v, want [0,%v]", *localResourceMinFraction, *localResourceFraction)
+	}
+	if *localResourceInterval <= 0 {
+		log.Exitf("Invalid local_resource_sample_interval: %v, want >0", *localResourceInterval)
+	}
 	if *racingBias < 0 || *racingBias > 1 {
 		log.Exitf("Invalid racing_bias: %v, want [0,1]", *racingBias)
 	}
@@ -275,6 +321,10 @@ func main() {
 	if err != nil {
 		log.Errorf("Failed to create mismatch ignorer: %v", err)
 	}
+	policy, err := execpolicy.New(*execPolicyConfigPath)
+	if err != nil {
+		log.Exitf("Failed to create execution policy: %v", err)
+	}
 	l, err := initializeLogger(mi, e)
 	if err != nil {
 		log.Exitf("%v", err)
@@ -284,6 +334,33 @@ func main() {
 
 	exec := &subprocess.SystemExecutor{}
 	resMgr := localresources.NewFractionalDefaultManager(*localResourceFraction)
+	if *dynamicLocalResources {
+		bounds := localresources.FractionalBounds(*localResourceMinFraction, *localResourceFraction)
+		go resMgr.RunDynamic(ctx, *localResourceInterval, bounds, usage.SystemSampler{}, func(cpus, ramMBs int64) {
+			l.AddStatSampleToProxyInfo(localresources.CapacityCPUs, cpus)
+			l.AddStatSampleToProxyInfo(localresources.CapacityRAM, ramMBs)
+			l.IncrementMetricIntToProxyInfo(event.LocalResourceCapacityChanges, 1)
+		})
+	}
+	localPool := reproxy.NewLocalPool(exec, resMgr)
+	var cgroupManager *cgroups.Manager
+	if *localCgroups {
+		cgroupManager = cgroups.New(*localCgroupRoot)
+		localPool.UseCgroups(cgroupManager, *localCgroupMemoryFactor)
+	}
+
+	var actionCache *actioncache.Cache
+	if *cacheDir != "" && *actionCacheMaxMb > 0 {
+		if actionCache, err = actioncache.New(*cacheDir, int64(*actionCacheMaxMb)*1024*1024); err != nil {
+			log.Errorf("Failed to create action cache: %v", err)
+		}
+	}
+	var localCAS *localcas.CAS
+	if *cacheDir != "" && *localCASMaxMb > 0 {
+		if localCAS, err = localcas.New(*cacheDir, int64(*localCASMaxMb)*1024*1024, *localCASHardlinks); err != nil {
+			log.Errorf("Failed to create local CAS: %v", err)
+		}
+	}
 
 	dTmp := *racingTmp
 	if *downloadTmp != "" {
@@ -293,20 +370,32 @@ func main() {
 	initCtx, cancelInit := context.WithCancel(ctx)
 	server := &reproxy.Server{
 		FileMetadataStore:         st,
-		LocalPool:                 reproxy.NewLocalPool(exec, resMgr),
+		LocalPool:                 localPool,
 		KeepLastRecords:           *keepRecords,
 		CacheSilo:                 *cacheSilo,
 		VersionCacheSilo:          *versionCacheSilo,
 		RemoteDisabled:            *remoteDisabled,
 		DumpInputTree:             *dumpInputTree,
 		Forecast:                  &reproxy.Forecast{},
+		Policy:                    policy,
 		StartTime:                 start,
 		FailEarlyMinActionCount:   *failEarlyMinActionCount,
 		FailEarlyMinFallbackRatio: *failEarlyMinFallbackRatio,
 		FailEarlyWindow:           *failEarlyWindow,
+		InvocationIdleTimeout:     *invocationIdleTimeout,
 		RacingBias:                *racingBias,
 		DownloadTmp:               dTmp,
-		MaxHoldoff:                time.Minute,
+		MaxHoldoff:                *racingMaxHoldoff,
+		DynamicHoldoff:            *dynamicRacingHoldoff,
+		HedgeDelay:                *hedgeDelay,
+		LoadShedMaxErrorRatio:     *loadShedMaxErrorRatio,
+		LoadShedMaxLatency:        *loadShedMaxLatency,
+		LoadShedMinActions:        *loadShedMinActions,
+		LoadShedWindow:            *loadShedWindow,
+		LoadShedCooldown:          *loadShedCooldown,
+		LoadShedProbeRatio:        *loadShedProbeRatio,
+		ActionCache:               actionCache,
+		LocalCAS:                  localCAS,
 		Logger:                    l,
 		StartupCancelFn:           cancelInit,
 	}
@@ -373,6 +462,10 @@ func main() {
 			}
 		}()
 	}
+	if *cacheDir != "" {
+		go server.Forecast.LoadFromDir(*cacheDir)
+		go server.LocalPool.LoadResourceModels(*cacheDir)
+	}
 	go server.Forecast.Run(ctx)
 	go server.MonitorFailBuildConditions(ctx)
 	go reproxy.IdleTimeout(ctx, *idleTimeout)
@@ -418,6 +511,13 @@ func main() {
 		}
 		grpcServer.GracefulStop()
 		<-server.WaitForCleanupDone()
+		if err := cgroupManager.Close(); err != nil {
+			log.Warningf("Failed to clean up cgroups: %v", err)
+		}
+		if *cacheDir != "" {
+			server.Forecast.WriteToDisk(*cacheDir)
+			server.LocalPool.WriteResourceModels(*cacheDir)
+		}
 		log.Infof("Finished shutting down and wrote log records...")
 		log.Flush()
 		wg.Done()
@@ -31,9 +31,11 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/bazelbuild/reclient/internal/pkg/ipc"
@@ -47,14 +49,21 @@ import (
 	pb "github.com/bazelbuild/reclient/api/proxy"
 
 	log "github.com/golang/glog"
+	"github.com/google/uuid"
 )
 
+// cancelTimeout is how long rewrapper waits for reproxy to cancel the command when interrupted.
+const cancelTimeout = 5 * time.Second
+
 var (
 	cOpts       = &rewrapper.CommandOptions{StartTime: time.Now()}
 	serverAddr  = "127.0.0.1:8000"
 	dialTimeout *time.Duration
+	// streamOutput streams the stdout and stderr of the command as it runs.
+	streamOutput bool
 
-	execStrategies = []string{"local", "remote", "remote_local_fallback", "racing"}
+	execStrategies = []string{"local", "remote", "remote_local_fallback", "racing", "remote_hedged"}
+	localPlatforms = []string{"", "docker", "sandbox"}
 )
 
 func initFlags() {
@@ -77,6 +86,8 @@ func initFlags() {
 	flag.Var((*moreflag.StringListValue)(&cOpts.OutputDirectories), "output_directories", "Comma-separated command output directory paths, relative to exec root.")
 	flag.StringVar(&cOpts.ExecStrategy, "exec_strategy", "remote", fmt.Sprintf("one of %s. Defaults to remote.", execStrategies))
 	flag.BoolVar(&cOpts.Compare, "compare", false, "Boolean indicating whether to compare chosen exec strategy with local execution. Default is false.")
+	flag.BoolVar(&cOpts.TraceLocalInputs, "trace_local_inputs", false, "Boolean indicating whether to trace the files read by the first local rerun in compare mode and report the ones that were not declared as inputs. Only supported on Linux with strace installed. Default is false.")
+	flag.BoolVar(&cOpts.RemoteVerify, "remote_verify", false, "Boolean indicating whether to verify remote cache hits of the remote exec strategy by executing the action remotely again and comparing its outputs with the cached ones. Default is false.")
 	flag.IntVar(&cOpts.NumRetriesIfMismatched, "num_retries_if_mismatched", 0, "Deprecated: Number of times the action should be remotely executed to identify determinism. Used only when compare is set to true.")
 	flag.IntVar(&cOpts.NumLocalReruns, "num_local_reruns", 0, "Number of times the action should be rerun locally.")
 	flag.IntVar(&cOpts.NumRemoteReruns, "num_remote_reruns", 0, "Number of times the action should be rerun remotely.")
@@ -88,11 +99,14 @@ func initFlags() {
 	flag.BoolVar(&cOpts.LogEnvironment, "log_env", false, "Boolean indicating whether to pass the entire environment of the rewrapper to the reproxy for logging. Default is false.")
 	flag.BoolVar(&cOpts.PreserveUnchangedOutputMtime, "preserve_unchanged_output_mtime", false, "Boolean indicating whether or not to preserve mtimes of unchanged outputs when they are downloaded. Default is false.")
 	flag.StringVar(&cOpts.LocalWrapper, "local_wrapper", "", "Wrapper path to execute locally only. Relative to the current working directory of rewrapper.")
+	flag.StringVar(&cOpts.LocalPlatform, "local_platform", "", "Platform to execute the command on when it runs locally, one of docker or sandbox. docker runs the command in the container-image of its platform, sandbox runs it in a private exec root with only its declared inputs and reports undeclared inputs it tried to access. Defaults to running the command directly on the host.")
+	flag.IntVar(&cOpts.LocalPriority, "local_priority", 0, "Priority of the command in the reproxy local execution queue. Commands with a higher priority are granted local resources first. If 0, the priority is derived from the command labels.")
 	flag.StringVar(&cOpts.RemoteWrapper, "remote_wrapper", "", "Wrapper path to execute on remote worker. Relative to the current working directory of rewrapper.")
 	dialTimeout = flag.Duration("dial_timeout", 3*time.Minute, "Timeout for dialing reproxy. Default is 3 minutes.")
 	flag.BoolVar(&cOpts.PreserveSymlink, "preserve_symlink", false, "Boolean indicating whether to preserve symlinks in input tree. Default is false.")
 	flag.BoolVar(&cOpts.CanonicalizeWorkingDir, "canonicalize_working_dir", false, "Replaces local working directory with a canonical value when running on RE server. The feature makes actions working-dir agnostic and enables to cache them across various same depth (e.g. out/default and out/rbe-build) local working directories (default: false)")
 	flag.StringVar(&cOpts.ActionLog, "action_log", "", "If set, write a reproxy log entry for this remote action to the named file.")
+	flag.BoolVar(&streamOutput, "stream_output", false, "Boolean indicating whether to print the stdout and stderr of the command as it runs locally rather than once it completes. Requires a reproxy supporting output streaming, otherwise the output is printed once the command completes. Default is false.")
 }
 
 func execStrategyValid() bool {
@@ -104,6 +118,15 @@ func execStrategyValid() bool {
 	return false
 }
 
+func localPlatformValid() bool {
+	for _, p := range localPlatforms {
+		if cOpts.LocalPlatform == p {
+			return true
+		}
+	}
+	return false
+}
+
 func main() {
 	initFlags()
 	version.PrintAndExitOnVersionFlag(false)
@@ -122,6 +145,10 @@ func main() {
 		flag.Usage()
 		log.Exitf("No exec_strategy provided, must be one of %v", execStrategies)
 	}
+	if !localPlatformValid() {
+		flag.Usage()
+		log.Exitf("Invalid local_platform (%q), must be one of %v", cOpts.LocalPlatform, localPlatforms[1:])
+	}
 	if serverAddr == "" {
 		log.Exitf("Invalid server address (%q), must be non empty.", serverAddr)
 	}
@@ -171,9 +198,30 @@ func main() {
 		log.Exitf("Expected num_local_reruns to be at least 0, got %v.", cOpts.NumLocalReruns)
 	}
 
+	// Cancel the command in reproxy if rewrapper is interrupted, e.g. by the build tool, so that
+	// reproxy does not keep running it.
+	cOpts.ExecutionID = uuid.New().String()
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigs
+		cCtx, cancel := context.WithTimeout(ctx, cancelTimeout)
+		defer cancel()
+		if err := rewrapper.CancelCommand(cCtx, proxy, cOpts.ExecutionID); err != nil {
+			log.Warningf("Failed to cancel command %v after %v signal: %v", cOpts.ExecutionID, sig, err)
+		}
+		log.Flush()
+		os.Exit(128 + int(sig.(syscall.Signal)))
+	}()
+
 	// TODO (b/296409009): Add support for preserve true and download outputs false for downloading stubs.
 
-	resp, err := rewrapper.RunCommand(ctx, *dialTimeout, proxy, cmd, cOpts)
+	var resp *pb.RunResponse
+	if streamOutput {
+		resp, err = rewrapper.RunCommandStream(ctx, *dialTimeout, proxy, cmd, cOpts, os.Stdout, os.Stderr)
+	} else {
+		resp, err = rewrapper.RunCommand(ctx, *dialTimeout, proxy, cmd, cOpts)
+	}
 	if err != nil {
 		// Don't use log.Fatalf to avoid printing a stack trace.
 		log.Exitf("Command failed: %v", err)
@@ -0,0 +1,301 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package actioncache implements an on-disk cache of action results and their outputs, so that
+// actions can be served from a cache without a remote backend.
+package actioncache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bazelbuild/reclient/internal/pkg/diskcache"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/digest"
+	"google.golang.org/protobuf/proto"
+
+	repb "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	log "github.com/golang/glog"
+)
+
+const (
+	// dirName is the name of the directory of the action cache under the cache directory of
+	// reproxy.
+	dirName = "reproxy.actions"
+	// resultFile is the name of the file holding the ActionResult of a cache entry. Its
+	// modification time is the last time the entry was used.
+	resultFile = "result"
+	// tmpPrefix is the prefix of the directories of cache entries being written.
+	tmpPrefix = "tmp-"
+
+	regularMode    = 0644
+	executableMode = 0755
+)
+
+// Cache is an on-disk cache of action results keyed by action digest. Each entry is a directory
+// holding the ActionResult of the action and the contents of its output files. Once the total
+// size of the entries exceeds the capacity of the cache, the least recently used entries are
+// evicted.
+type Cache struct {
+	dir      string
+	maxBytes int64
+
+	mu  sync.Mutex
+	lru *diskcache.LRU
+}
+
+// New creates a cache in cacheDir holding at most maxBytes, loading the entries already stored
+// in cacheDir.
+func New(cacheDir string, maxBytes int64) (*Cache, error) {
+	dir := filepath.Join(cacheDir, dirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create action cache directory %v: %v", dir, err)
+	}
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read action cache directory %v: %v", dir, err)
+	}
+	var loaded []*diskcache.Item
+	for _, de := range des {
+		path := filepath.Join(dir, de.Name())
+		if !de.IsDir() || strings.HasPrefix(de.Name(), tmpPrefix) {
+			// Leftovers of writes interrupted by a shutdown.
+			os.RemoveAll(path)
+			continue
+		}
+		fi, err := os.Stat(filepath.Join(path, resultFile))
+		if err != nil {
+			os.RemoveAll(path)
+			continue
+		}
+		size, err := diskcache.DirSize(path)
+		if err != nil {
+			os.RemoveAll(path)
+			continue
+		}
+		loaded = append(loaded, &diskcache.Item{Name: de.Name(), Size: size, ModTime: fi.ModTime()})
+	}
+	c := &Cache{dir: dir, maxBytes: maxBytes}
+	c.lru = diskcache.NewLRU(maxBytes, c.removeEntry)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Load(loaded)
+	log.Infof("Loaded %v entries of %v bytes from action cache %v", c.lru.Len(), c.lru.Size(), dir)
+	return c, nil
+}
+
+func entryName(key digest.Digest) string {
+	return fmt.Sprintf("%s_%d", key.Hash, key.Size)
+}
+
+// Get restores the outputs of the action with digest key under outDir and returns its result,
+// or nil if the action is not in the cache.
+func (c *Cache) Get(key digest.Digest, outDir string) (*repb.ActionResult, error) {
+	if c == nil {
+		return nil, nil
+	}
+	name := entryName(key)
+	c.mu.Lock()
+	ok := c.lru.Use(name) != nil
+	c.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	path := filepath.Join(c.dir, name)
+	res, err := restore(path, outDir)
+	if err != nil {
+		c.remove(name)
+		return nil, fmt.Errorf("failed to restore action cache entry %v: %v", name, err)
+	}
+	now := time.Now()
+	if err := os.Chtimes(filepath.Join(path, resultFile), now, now); err != nil {
+		log.Warningf("Failed to update the last use time of action cache entry %v: %v", name, err)
+	}
+	return res, nil
+}
+
+func restore(path, outDir string) (*repb.ActionResult, error) {
+	blob, err := os.ReadFile(filepath.Join(path, resultFile))
+	if err != nil {
+		return nil, err
+	}
+	res := &repb.ActionResult{}
+	if err := proto.Unmarshal(blob, res); err != nil {
+		return nil, err
+	}
+	for _, d := range res.GetOutputDirectories() {
+		if err := os.MkdirAll(filepath.Join(outDir, filepath.FromSlash(d.GetPath())), 0755); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range res.GetOutputFiles() {
+		dst := filepath.Join(outDir, filepath.FromSlash(f.GetPath()))
+		mode := os.FileMode(regularMode)
+		if f.GetIsExecutable() {
+			mode = executableMode
+		}
+		if err := replaceFile(filepath.Join(path, f.GetDigest().GetHash()), dst, mode); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range res.GetOutputSymlinks() {
+		dst := filepath.Join(outDir, filepath.FromSlash(s.GetPath()))
+		if err := diskcache.PrepareDst(dst); err != nil {
+			return nil, err
+		}
+		if err := os.Symlink(s.GetTarget(), dst); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Put stores the outputs at the given paths relative to outDir as the result of the action with
+// digest key, together with its exit code and output streams. Outputs that don't exist are
+// skipped, and output directories are stored as the files and symlinks they contain.
+func (c *Cache) Put(key digest.Digest, outDir string, paths []string, exitCode int32, stdout, stderr []byte) error {
+	if c == nil {
+		return nil
+	}
+	name := entryName(key)
+	c.mu.Lock()
+	ok := c.lru.Contains(name)
+	c.mu.Unlock()
+	if ok {
+		return nil
+	}
+	tmp, err := os.MkdirTemp(c.dir, tmpPrefix)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	res := &repb.ActionResult{ExitCode: exitCode, StdoutRaw: stdout, StderrRaw: stderr}
+	for _, p := range paths {
+		if err := store(tmp, outDir, p, res); err != nil {
+			return fmt.Errorf("failed to store output %v: %v", p, err)
+		}
+	}
+	blob, err := proto.Marshal(res)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, resultFile), blob, regularMode); err != nil {
+		return err
+	}
+	size, err := diskcache.DirSize(tmp)
+	if err != nil {
+		return err
+	}
+	if size > c.maxBytes {
+		log.V(1).Infof("Not caching action %v, its outputs of %v bytes exceed the action cache capacity", key, size)
+		return nil
+	}
+	if err := os.Rename(tmp, filepath.Join(c.dir, name)); err != nil {
+		if _, serr := os.Stat(filepath.Join(c.dir, name)); serr == nil {
+			// Stored concurrently by another action with the same digest.
+			return nil
+		}
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Add(&diskcache.Item{Name: name, Size: size, ModTime: time.Now()})
+	return nil
+}
+
+func store(entryDir, outDir, path string, res *repb.ActionResult) error {
+	abs := filepath.Join(outDir, path)
+	fi, err := os.Lstat(abs)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return storeNode(entryDir, abs, filepath.ToSlash(path), fi, res)
+	}
+	return filepath.WalkDir(abs, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(outDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			res.OutputDirectories = append(res.OutputDirectories, &repb.OutputDirectory{Path: rel})
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return storeNode(entryDir, p, rel, fi, res)
+	})
+}
+
+func storeNode(entryDir, abs, rel string, fi fs.FileInfo, res *repb.ActionResult) error {
+	if fi.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(abs)
+		if err != nil {
+			return err
+		}
+		res.OutputSymlinks = append(res.OutputSymlinks, &repb.OutputSymlink{Path: rel, Target: target})
+		return nil
+	}
+	if !fi.Mode().IsRegular() {
+		return fmt.Errorf("%v is not a regular file", abs)
+	}
+	dg, err := digest.NewFromFile(abs)
+	if err != nil {
+		return err
+	}
+	res.OutputFiles = append(res.OutputFiles, &repb.OutputFile{
+		Path:         rel,
+		Digest:       dg.ToProto(),
+		IsExecutable: fi.Mode()&0100 != 0,
+	})
+	blob := filepath.Join(entryDir, dg.Hash)
+	if _, err := os.Stat(blob); err == nil {
+		return nil
+	}
+	return diskcache.CopyFile(abs, blob, regularMode)
+}
+
+func (c *Cache) remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Remove(name)
+}
+
+// removeEntry removes an entry evicted from the cache.
+func (c *Cache) removeEntry(name string) {
+	if err := os.RemoveAll(filepath.Join(c.dir, name)); err != nil {
+		log.Warningf("Failed to remove action cache entry %v: %v", name, err)
+	}
+}
+
+func replaceFile(src, dst string, mode os.FileMode) error {
+	if err := diskcache.PrepareDst(dst); err != nil {
+		return err
+	}
+	return diskcache.CopyFile(src, dst, mode)
+}
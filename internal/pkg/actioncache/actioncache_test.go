@@ -0,0 +1,164 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actioncache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/digest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		abs := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
+			t.Fatalf("MkdirAll(%v) failed: %v", filepath.Dir(abs), err)
+		}
+		if err := os.WriteFile(abs, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile(%v) failed: %v", abs, err)
+		}
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%v) failed: %v", path, err)
+	}
+	return string(blob)
+}
+
+func TestPutGet(t *testing.T) {
+	c, err := New(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	out := t.TempDir()
+	writeFiles(t, out, map[string]string{
+		"a.o":       "object",
+		"gen/b.h":   "header",
+		"gen/sub/c": "object",
+	})
+	if err := os.Symlink("b.h", filepath.Join(out, "gen", "link")); err != nil {
+		t.Fatalf("Symlink() failed: %v", err)
+	}
+	key := digest.NewFromBlob([]byte("action"))
+	if err := c.Put(key, out, []string{"a.o", "gen", "missing.o"}, 0, []byte("stdout"), []byte("stderr")); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+
+	restored := t.TempDir()
+	writeFiles(t, restored, map[string]string{"a.o": "stale"})
+	res, err := c.Get(key, restored)
+	if err != nil || res == nil {
+		t.Fatalf("Get() = %v, %v, want result", res, err)
+	}
+	if string(res.GetStdoutRaw()) != "stdout" || string(res.GetStderrRaw()) != "stderr" {
+		t.Errorf("Get() returned stdout %q and stderr %q, want %q and %q", res.GetStdoutRaw(), res.GetStderrRaw(), "stdout", "stderr")
+	}
+	want := map[string]string{"a.o": "object", "gen/b.h": "header", "gen/sub/c": "object"}
+	got := make(map[string]string)
+	for path := range want {
+		got[path] = readFile(t, filepath.Join(restored, path))
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Get() restored outputs with diff (-want +got):\n%s", diff)
+	}
+	if target, err := os.Readlink(filepath.Join(restored, "gen", "link")); err != nil || target != "b.h" {
+		t.Errorf("Readlink(gen/link) = %v, %v, want b.h", target, err)
+	}
+	if _, err := os.Stat(filepath.Join(restored, "missing.o")); err == nil {
+		t.Errorf("Get() restored missing.o, which was not an output of the action")
+	}
+
+	if res, err := c.Get(digest.NewFromBlob([]byte("other")), restored); res != nil || err != nil {
+		t.Errorf("Get() of uncached action = %v, %v, want nil, nil", res, err)
+	}
+}
+
+func TestEviction(t *testing.T) {
+	dir := t.TempDir()
+	out := t.TempDir()
+	writeFiles(t, out, map[string]string{"out": string(make([]byte, 1000))})
+	c, err := New(dir, 2500)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	keys := []digest.Digest{
+		digest.NewFromBlob([]byte("a")),
+		digest.NewFromBlob([]byte("b")),
+		digest.NewFromBlob([]byte("c")),
+	}
+	for i, key := range keys[:2] {
+		if err := c.Put(key, out, []string{"out"}, 0, nil, nil); err != nil {
+			t.Fatalf("Put(%v) failed: %v", i, err)
+		}
+	}
+	// Make the first entry the most recently used one.
+	if res, err := c.Get(keys[0], t.TempDir()); res == nil || err != nil {
+		t.Fatalf("Get(0) = %v, %v, want result", res, err)
+	}
+	if err := c.Put(keys[2], out, []string{"out"}, 0, nil, nil); err != nil {
+		t.Fatalf("Put(2) failed: %v", err)
+	}
+	for i, wantCached := range []bool{true, false, true} {
+		if res, _ := c.Get(keys[i], t.TempDir()); (res != nil) != wantCached {
+			t.Errorf("Get(%v) cached = %v, want %v", i, res != nil, wantCached)
+		}
+	}
+
+	// Entries are loaded from disk, evicting the least recently used ones over the capacity.
+	c, err = New(dir, 1500)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	for i, wantCached := range []bool{false, false, true} {
+		if res, _ := c.Get(keys[i], t.TempDir()); (res != nil) != wantCached {
+			t.Errorf("Get(%v) after reload cached = %v, want %v", i, res != nil, wantCached)
+		}
+	}
+}
+
+func TestPutTooLarge(t *testing.T) {
+	c, err := New(t.TempDir(), 100)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	out := t.TempDir()
+	writeFiles(t, out, map[string]string{"out": string(make([]byte, 1000))})
+	key := digest.NewFromBlob([]byte("action"))
+	if err := c.Put(key, out, []string{"out"}, 0, nil, nil); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+	if res, err := c.Get(key, t.TempDir()); res != nil || err != nil {
+		t.Errorf("Get() of action larger than the cache = %v, %v, want nil, nil", res, err)
+	}
+}
+
+func TestNilCache(t *testing.T) {
+	var c *Cache
+	key := digest.NewFromBlob([]byte("action"))
+	if err := c.Put(key, t.TempDir(), []string{"out"}, 0, nil, nil); err != nil {
+		t.Errorf("Put() on nil cache failed: %v", err)
+	}
+	if res, err := c.Get(key, t.TempDir()); res != nil || err != nil {
+		t.Errorf("Get() on nil cache = %v, %v, want nil, nil", res, err)
+	}
+}
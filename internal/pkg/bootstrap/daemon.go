@@ -0,0 +1,127 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	ppb "github.com/bazelbuild/reclient/api/proxy"
+	spb "github.com/bazelbuild/reclient/api/stats"
+	"github.com/bazelbuild/reclient/internal/pkg/ipc"
+	"github.com/bazelbuild/reclient/internal/pkg/reproxypid"
+	"github.com/bazelbuild/reclient/internal/pkg/version"
+
+	log "github.com/golang/glog"
+)
+
+// perBuildEnvVars are environment variables that differ between builds sharing a daemon reproxy,
+// and are thus excluded from its flags digest.
+var perBuildEnvVars = map[string]bool{
+	"RBE_invocation_id": true,
+}
+
+// FlagsDigest returns a digest of the flags reproxy is started with: its arguments, the contents of
+// the config file passed with --cfg, and the RBE_ environment variables.
+func FlagsDigest(args []string) (string, error) {
+	h := sha256.New()
+	for _, arg := range args {
+		fmt.Fprintf(h, "arg:%s\n", arg)
+		if cfg := strings.TrimPrefix(arg, "--cfg="); cfg != arg {
+			contents, err := os.ReadFile(cfg)
+			if err != nil {
+				return "", fmt.Errorf("failed to read config file %v: %w", cfg, err)
+			}
+			fmt.Fprintf(h, "cfg:%s\n", contents)
+		}
+	}
+	var env []string
+	for _, e := range os.Environ() {
+		if k, _, _ := strings.Cut(e, "="); strings.HasPrefix(k, "RBE_") && !perBuildEnvVars[k] {
+			env = append(env, e)
+		}
+	}
+	sort.Strings(env)
+	for _, e := range env {
+		fmt.Fprintf(h, "env:%s\n", e)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// AttachProxy checks whether a daemon reproxy of the current version, started with flags of the
+// given digest, is running at serverAddr. If so, it can be used instead of starting a new one.
+func AttachProxy(ctx context.Context, serverAddr, flagsDigest string) error {
+	pf, err := reproxypid.ReadFile(serverAddr)
+	if err != nil {
+		return err
+	}
+	if pf.Version == "" {
+		return fmt.Errorf("reproxy with pid=%d is not a daemon", pf.Pid)
+	}
+	if v := version.CurrentVersion(); pf.Version != v {
+		return fmt.Errorf("reproxy with pid=%d has version %v, want %v", pf.Pid, pf.Version, v)
+	}
+	if pf.FlagsDigest != flagsDigest {
+		return fmt.Errorf("reproxy with pid=%d was started with different flags", pf.Pid)
+	}
+	alive, err := pf.IsAlive()
+	if err != nil {
+		return fmt.Errorf("failed to check whether the reproxy process %d is alive: %w", pf.Pid, err)
+	} else if !alive {
+		return fmt.Errorf("reproxy is not running with pid=%d", pf.Pid)
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, initialDialTimeout*time.Second)
+	defer cancel()
+	conn, err := ipc.DialContextWithBlock(dialCtx, serverAddr)
+	if err != nil {
+		return fmt.Errorf("reproxy with pid=%d is not responding at %v: %w", pf.Pid, serverAddr, err)
+	}
+	conn.Close()
+	log.Infof("Attached to daemon reproxy with pid=%d", pf.Pid)
+	return nil
+}
+
+// MarkDaemon records in the pid file of the reproxy running at serverAddr that it is a daemon
+// started with flags of the given digest, so that later builds can attach to it.
+func MarkDaemon(serverAddr, flagsDigest string) error {
+	pf, err := reproxypid.ReadFile(serverAddr)
+	if err != nil {
+		return err
+	}
+	return reproxypid.WriteDaemonFile(serverAddr, pf.Pid, version.CurrentVersion(), flagsDigest)
+}
+
+// FinishInvocation sends a FinishInvocation rpc to the daemon reproxy running at serverAddr and
+// returns the stats of the given invocation. The reproxy keeps running.
+func FinishInvocation(serverAddr, invocationID string, timeoutSeconds int) (*spb.Stats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutSeconds)*time.Second)
+	defer cancel()
+	conn, err := ipc.DialContext(ctx, serverAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial reproxy at %v: %w", serverAddr, err)
+	}
+	defer conn.Close()
+	resp, err := ppb.NewCommandsClient(conn).FinishInvocation(ctx, &ppb.FinishInvocationRequest{InvocationId: invocationID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetStats(), nil
+}
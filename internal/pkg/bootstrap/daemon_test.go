@@ -0,0 +1,110 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bazelbuild/reclient/internal/pkg/reproxypid"
+	"github.com/bazelbuild/reclient/internal/pkg/version"
+)
+
+func TestFlagsDigest(t *testing.T) {
+	cfg := filepath.Join(t.TempDir(), "reproxy.cfg")
+	if err := os.WriteFile(cfg, []byte("instance=a"), 0644); err != nil {
+		t.Fatalf("WriteFile(%v) failed: %v", cfg, err)
+	}
+	args := []string{"--cfg=" + cfg, "--log_dir=/tmp"}
+	digest := func() string {
+		t.Helper()
+		d, err := FlagsDigest(args)
+		if err != nil {
+			t.Fatalf("FlagsDigest(%v) returned error: %v", args, err)
+		}
+		return d
+	}
+	base := digest()
+
+	t.Setenv("RBE_invocation_id", "build-2")
+	if got := digest(); got != base {
+		t.Errorf("FlagsDigest(%v) with RBE_invocation_id set = %v, want %v", args, got, base)
+	}
+
+	if err := os.WriteFile(cfg, []byte("instance=b"), 0644); err != nil {
+		t.Fatalf("WriteFile(%v) failed: %v", cfg, err)
+	}
+	changedCfg := digest()
+	if changedCfg == base {
+		t.Errorf("FlagsDigest(%v) did not change with the contents of %v", args, cfg)
+	}
+
+	t.Setenv("RBE_service", "remote:443")
+	if got := digest(); got == changedCfg {
+		t.Errorf("FlagsDigest(%v) did not change with RBE_service set", args)
+	}
+
+	if _, err := FlagsDigest([]string{"--cfg=" + filepath.Join(t.TempDir(), "missing.cfg")}); err == nil {
+		t.Errorf("FlagsDigest() with a missing config file returned nil error, want error")
+	}
+}
+
+func TestAttachProxyMismatch(t *testing.T) {
+	serverAddr := fmt.Sprintf("unix://%s/reproxy.sock", t.TempDir())
+	tests := []struct {
+		name        string
+		version     string
+		flagsDigest string
+		wantErr     string
+	}{
+		{
+			name:        "version mismatch",
+			version:     "0.0.0.other",
+			flagsDigest: "digest",
+			wantErr:     "has version",
+		},
+		{
+			name:        "flags mismatch",
+			version:     version.CurrentVersion(),
+			flagsDigest: "other",
+			wantErr:     "different flags",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := reproxypid.WriteDaemonFile(serverAddr, os.Getpid(), tc.version, tc.flagsDigest); err != nil {
+				t.Fatalf("WriteDaemonFile(%v) failed: %v", serverAddr, err)
+			}
+			err := AttachProxy(context.Background(), serverAddr, "digest")
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("AttachProxy(%v) returned error %v, want error containing %q", serverAddr, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAttachProxyNotDaemon(t *testing.T) {
+	serverAddr := fmt.Sprintf("unix://%s/reproxy.sock", t.TempDir())
+	if err := reproxypid.WriteFile(serverAddr, os.Getpid()); err != nil {
+		t.Fatalf("WriteFile(%v) failed: %v", serverAddr, err)
+	}
+	if err := AttachProxy(context.Background(), serverAddr, "digest"); err == nil {
+		t.Errorf("AttachProxy(%v) to a reproxy that is not a daemon returned nil error, want error", serverAddr)
+	}
+}
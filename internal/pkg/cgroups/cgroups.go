@@ -0,0 +1,222 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cgroups confines locally executed commands to cgroup v2 groups that limit their memory
+// and CPU usage.
+package cgroups
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// cpuPeriodUs is the cpu.max period, in microseconds.
+	cpuPeriodUs = 100000
+	bytesPerMB  = 1024 * 1024
+	// leafName is the name of the cgroup reproxy moves itself to when it creates the root
+	// cgroup itself.
+	leafName = "reproxy"
+)
+
+// controllers are the controllers enabled for the cgroups of commands.
+var controllers = []string{"memory", "cpu"}
+
+// Manager creates a cgroup for each confined command under a root cgroup owned by reproxy.
+// A nil *Manager confines nothing.
+type Manager struct {
+	root   string
+	numGrp int64
+	// parent is the cgroup reproxy was started in if it created root itself, and empty if root
+	// was given.
+	parent string
+	// parentEnabled are the controllers enabled by reproxy for the children of parent, which
+	// were not enabled before reproxy started.
+	parentEnabled []string
+}
+
+// Group is the cgroup of a single command. A nil *Group confines nothing.
+type Group struct {
+	path string
+}
+
+// newManager creates a manager whose groups are created under root, which must be an existing
+// cgroup without processes of its own.
+func newManager(root string) (*Manager, error) {
+	if err := enableControllers(root, controllers); err != nil {
+		return nil, err
+	}
+	return &Manager{root: root}, nil
+}
+
+// Close removes the root cgroup if it was created by the manager, moving reproxy back to the
+// cgroup it was started in. The groups of all commands must be closed first.
+func (m *Manager) Close() error {
+	if m == nil || m.parent == "" {
+		return nil
+	}
+	// A cgroup with controllers enabled for its children cannot have processes of its own, so
+	// the controllers enabled when the root was created are disabled again first. Controllers
+	// enabled before reproxy started are left alone, since other cgroups may depend on them.
+	if err := disableControllers(m.parent, m.parentEnabled); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(m.parent, "cgroup.procs"), []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		return fmt.Errorf("failed to move reproxy back to cgroup %v: %v", m.parent, err)
+	}
+	for _, dir := range []string{filepath.Join(m.root, leafName), m.root} {
+		if err := os.Remove(dir); err != nil {
+			return fmt.Errorf("failed to remove cgroup %v: %v", dir, err)
+		}
+	}
+	return nil
+}
+
+// enableControllers makes the given controllers available to the children of the cgroup at dir.
+func enableControllers(dir string, ctrls []string) error {
+	return writeSubtreeControl(dir, "+", ctrls)
+}
+
+// disableControllers undoes enableControllers for the cgroup at dir.
+func disableControllers(dir string, ctrls []string) error {
+	return writeSubtreeControl(dir, "-", ctrls)
+}
+
+func writeSubtreeControl(dir, op string, ctrls []string) error {
+	if len(ctrls) == 0 {
+		return nil
+	}
+	var val []string
+	for _, c := range ctrls {
+		val = append(val, op+c)
+	}
+	path := filepath.Join(dir, "cgroup.subtree_control")
+	if err := os.WriteFile(path, []byte(strings.Join(val, " ")), 0644); err != nil {
+		return fmt.Errorf("failed to write %q to %v: %v", strings.Join(val, " "), path, err)
+	}
+	return nil
+}
+
+// missingControllers returns the controllers of commands which are not yet available to the
+// children of the cgroup at dir.
+func missingControllers(dir string) ([]string, error) {
+	b, err := os.ReadFile(filepath.Join(dir, "cgroup.subtree_control"))
+	if err != nil {
+		return nil, err
+	}
+	enabled := make(map[string]bool)
+	for _, c := range strings.Fields(string(b)) {
+		enabled[c] = true
+	}
+	var missing []string
+	for _, c := range controllers {
+		if !enabled[c] {
+			missing = append(missing, c)
+		}
+	}
+	return missing, nil
+}
+
+// NewGroup creates a cgroup limited to the given number of CPUs and MBs of RAM. Limits of 0 are
+// not enforced.
+func (m *Manager) NewGroup(cpus, ramMBs int64) (*Group, error) {
+	if m == nil {
+		return nil, nil
+	}
+	path := filepath.Join(m.root, fmt.Sprintf("action-%d", atomic.AddInt64(&m.numGrp, 1)))
+	if err := os.Mkdir(path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cgroup %v: %v", path, err)
+	}
+	g := &Group{path: path}
+	if ramMBs > 0 {
+		if err := g.write("memory.max", strconv.FormatInt(ramMBs*bytesPerMB, 10)); err != nil {
+			g.Close()
+			return nil, err
+		}
+		// Without swap the memory limit is hit rather than the command being slowed down by
+		// swapping. Not all kernels have swap accounting, so failures are ignored.
+		g.write("memory.swap.max", "0")
+	}
+	if cpus > 0 {
+		if err := g.write("cpu.max", fmt.Sprintf("%d %d", cpus*cpuPeriodUs, cpuPeriodUs)); err != nil {
+			g.Close()
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+func (g *Group) write(file, val string) error {
+	if err := os.WriteFile(filepath.Join(g.path, file), []byte(val), 0644); err != nil {
+		return fmt.Errorf("failed to write %q to %v of cgroup %v: %v", val, file, g.path, err)
+	}
+	return nil
+}
+
+// Path returns the directory of the cgroup.
+func (g *Group) Path() string {
+	if g == nil {
+		return ""
+	}
+	return g.path
+}
+
+// Add moves the process with the given pid to the cgroup. Processes subsequently started by it
+// are in the cgroup as well.
+func (g *Group) Add(pid int) error {
+	if g == nil {
+		return nil
+	}
+	return g.write("cgroup.procs", strconv.Itoa(pid))
+}
+
+// OOMKilled returns whether a process in the cgroup was killed for exceeding the memory limit.
+func (g *Group) OOMKilled() bool {
+	if g == nil {
+		return false
+	}
+	events, err := os.ReadFile(filepath.Join(g.path, "memory.events"))
+	if err != nil {
+		return false
+	}
+	s := bufio.NewScanner(bytes.NewReader(events))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 2 || fields[0] != "oom_kill" {
+			continue
+		}
+		n, err := strconv.ParseInt(fields[1], 10, 64)
+		return err == nil && n > 0
+	}
+	return false
+}
+
+// Close kills any processes left in the cgroup and removes it.
+func (g *Group) Close() error {
+	if g == nil {
+		return nil
+	}
+	// cgroup.kill is only available from Linux 5.14, and is not needed if the cgroup is empty.
+	g.write("cgroup.kill", "1")
+	if err := os.Remove(g.path); err != nil {
+		return fmt.Errorf("failed to remove cgroup %v: %v", g.path, err)
+	}
+	return nil
+}
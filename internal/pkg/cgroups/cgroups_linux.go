@@ -0,0 +1,106 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroups
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/golang/glog"
+)
+
+const mountPoint = "/sys/fs/cgroup"
+
+// New creates a manager confining commands to cgroups under root, which must be an existing
+// cgroup v2 without processes of its own. If root is empty, a cgroup is created under the cgroup
+// of reproxy, and reproxy moves itself to a leaf cgroup beside the cgroups of commands so that the
+// memory and cpu controllers can be enabled for them. Returns nil if cgroup v2 is not available
+// or not writable.
+func New(root string) *Manager {
+	parent := ""
+	var parentEnabled []string
+	if root == "" {
+		var err error
+		if root, parent, parentEnabled, err = ownRoot(); err != nil {
+			log.Warningf("Unable to set up cgroups, local actions will not be confined: %v", err)
+			return nil
+		}
+	}
+	m, err := newManager(root)
+	if err != nil {
+		log.Warningf("Unable to enable controllers in cgroup %v, local actions will not be confined: %v", root, err)
+		if err := (&Manager{root: root, parent: parent, parentEnabled: parentEnabled}).Close(); err != nil {
+			log.Warningf("Unable to clean up cgroup %v: %v", root, err)
+		}
+		return nil
+	}
+	m.parent = parent
+	m.parentEnabled = parentEnabled
+	log.Infof("Confining local actions to cgroups under %v", root)
+	return m
+}
+
+// ownRoot creates a cgroup for the cgroups of commands under the cgroup of reproxy. Returns the
+// created cgroup, the cgroup of reproxy and the controllers enabled in the cgroup of reproxy that
+// were not enabled before.
+func ownRoot() (string, string, []string, error) {
+	if _, err := os.Stat(filepath.Join(mountPoint, "cgroup.controllers")); err != nil {
+		return "", "", nil, fmt.Errorf("cgroup v2 is not mounted at %v: %v", mountPoint, err)
+	}
+	self, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", "", nil, err
+	}
+	// The cgroup v2 hierarchy is listed as "0::<path>".
+	rel, found := "", false
+	for _, line := range strings.Split(string(self), "\n") {
+		if strings.HasPrefix(line, "0::") {
+			rel, found = strings.TrimPrefix(line, "0::"), true
+			break
+		}
+	}
+	if !found {
+		return "", "", nil, fmt.Errorf("reproxy is not in a cgroup v2 hierarchy")
+	}
+	pid := []byte(strconv.Itoa(os.Getpid()))
+	parent := filepath.Join(mountPoint, rel)
+	root := filepath.Join(parent, fmt.Sprintf("reproxy-%d", os.Getpid()))
+	leaf := filepath.Join(root, leafName)
+	if err := os.MkdirAll(leaf, 0755); err != nil {
+		return "", "", nil, err
+	}
+	// A cgroup with processes of its own cannot make controllers available to its children.
+	if err := os.WriteFile(filepath.Join(leaf, "cgroup.procs"), pid, 0644); err != nil {
+		os.Remove(leaf)
+		os.Remove(root)
+		return "", "", nil, err
+	}
+	missing, err := missingControllers(parent)
+	if err == nil {
+		err = enableControllers(parent, missing)
+	}
+	if err != nil {
+		// Some of the controllers may have been enabled before the failure.
+		disableControllers(parent, missing)
+		os.WriteFile(filepath.Join(parent, "cgroup.procs"), pid, 0644)
+		os.Remove(leaf)
+		os.Remove(root)
+		return "", "", nil, fmt.Errorf("unable to enable controllers in cgroup %v: %v", parent, err)
+	}
+	return root, parent, missing, nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroups
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %v: %v", path, err)
+	}
+	return string(b)
+}
+
+func TestNewGroup(t *testing.T) {
+	root := t.TempDir()
+	m, err := newManager(root)
+	if err != nil {
+		t.Fatalf("newManager(%v) returned error: %v", root, err)
+	}
+	if got, want := readFile(t, filepath.Join(root, "cgroup.subtree_control")), "+memory +cpu"; got != want {
+		t.Errorf("cgroup.subtree_control = %q, want %q", got, want)
+	}
+	g, err := m.NewGroup(2, 512)
+	if err != nil {
+		t.Fatalf("NewGroup(2, 512) returned error: %v", err)
+	}
+	if got, want := readFile(t, filepath.Join(g.Path(), "memory.max")), "536870912"; got != want {
+		t.Errorf("memory.max = %q, want %q", got, want)
+	}
+	if got, want := readFile(t, filepath.Join(g.Path(), "cpu.max")), "200000 100000"; got != want {
+		t.Errorf("cpu.max = %q, want %q", got, want)
+	}
+	if err := g.Add(1234); err != nil {
+		t.Errorf("Add(1234) returned error: %v", err)
+	}
+	if got, want := readFile(t, filepath.Join(g.Path(), "cgroup.procs")), "1234"; got != want {
+		t.Errorf("cgroup.procs = %q, want %q", got, want)
+	}
+	g2, err := m.NewGroup(0, 0)
+	if err != nil {
+		t.Fatalf("NewGroup(0, 0) returned error: %v", err)
+	}
+	if g2.Path() == g.Path() {
+		t.Errorf("NewGroup() returned the same cgroup %v twice", g.Path())
+	}
+	if _, err := os.Stat(filepath.Join(g2.Path(), "memory.max")); !os.IsNotExist(err) {
+		t.Errorf("NewGroup(0, 0) set a memory limit, want none")
+	}
+}
+
+func TestOOMKilled(t *testing.T) {
+	tests := []struct {
+		name   string
+		events string
+		want   bool
+	}{
+		{
+			name: "no events file",
+		},
+		{
+			name:   "no oom kills",
+			events: "low 0\nhigh 0\nmax 3\noom 1\noom_kill 0\n",
+		},
+		{
+			name:   "oom killed",
+			events: "low 0\nhigh 0\nmax 7\noom 1\noom_kill 1\noom_group_kill 0\n",
+			want:   true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := &Group{path: t.TempDir()}
+			if tc.events != "" {
+				if err := os.WriteFile(filepath.Join(g.path, "memory.events"), []byte(tc.events), 0644); err != nil {
+					t.Fatalf("Failed to write memory.events: %v", err)
+				}
+			}
+			if got := g.OOMKilled(); got != tc.want {
+				t.Errorf("OOMKilled() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNilManager(t *testing.T) {
+	var m *Manager
+	g, err := m.NewGroup(1, 512)
+	if g != nil || err != nil {
+		t.Errorf("NewGroup() on nil manager = %v, %v, want nil, nil", g, err)
+	}
+	if err := g.Add(1234); err != nil {
+		t.Errorf("Add() on nil group returned error: %v", err)
+	}
+	if g.OOMKilled() {
+		t.Errorf("OOMKilled() on nil group = true, want false")
+	}
+	if err := g.Close(); err != nil {
+		t.Errorf("Close() on nil group returned error: %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Errorf("Close() on nil manager returned error: %v", err)
+	}
+}
+
+func TestCloseOwnRoot(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "reproxy-1234")
+	if err := os.MkdirAll(filepath.Join(root, leafName), 0755); err != nil {
+		t.Fatalf("Failed to create cgroup: %v", err)
+	}
+	m, err := newManager(root)
+	if err != nil {
+		t.Fatalf("newManager(%v) returned error: %v", root, err)
+	}
+	m.parent = parent
+	// The cpu controller was already enabled in the parent before reproxy started.
+	m.parentEnabled = []string{"memory"}
+	// Unlike on a cgroup file system, the fake cgroups hold regular files and cannot be removed.
+	m.Close()
+	if got, want := readFile(t, filepath.Join(parent, "cgroup.procs")), strconv.Itoa(os.Getpid()); got != want {
+		t.Errorf("cgroup.procs of parent = %q, want %q", got, want)
+	}
+	if got, want := readFile(t, filepath.Join(parent, "cgroup.subtree_control")), "-memory"; got != want {
+		t.Errorf("cgroup.subtree_control of parent = %q, want %q", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(root, leafName)); !os.IsNotExist(err) {
+		t.Errorf("Close() did not remove cgroup %v", filepath.Join(root, leafName))
+	}
+}
+
+func TestCloseOwnRootWithoutEnabledControllers(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "reproxy-1234")
+	if err := os.MkdirAll(filepath.Join(root, leafName), 0755); err != nil {
+		t.Fatalf("Failed to create cgroup: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte("cpu io memory"), 0644); err != nil {
+		t.Fatalf("Failed to write cgroup.subtree_control: %v", err)
+	}
+	m, err := newManager(root)
+	if err != nil {
+		t.Fatalf("newManager(%v) returned error: %v", root, err)
+	}
+	m.parent = parent
+	m.Close()
+	if got, want := readFile(t, filepath.Join(parent, "cgroup.subtree_control")), "cpu io memory"; got != want {
+		t.Errorf("Close() changed cgroup.subtree_control of parent to %q, want %q", got, want)
+	}
+}
+
+func TestMissingControllers(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled string
+		want    []string
+	}{
+		{
+			name: "none enabled",
+			want: []string{"memory", "cpu"},
+		},
+		{
+			name:    "some enabled",
+			enabled: "cpu io\n",
+			want:    []string{"memory"},
+		},
+		{
+			name:    "all enabled",
+			enabled: "cpu io memory\n",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte(tc.enabled), 0644); err != nil {
+				t.Fatalf("Failed to write cgroup.subtree_control: %v", err)
+			}
+			got, err := missingControllers(dir)
+			if err != nil {
+				t.Fatalf("missingControllers(%v) returned error: %v", dir, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("missingControllers(%v) returned diff (-want +got):\n%s", dir, diff)
+			}
+		})
+	}
+}
+
+func TestCloseGivenRoot(t *testing.T) {
+	root := t.TempDir()
+	m, err := newManager(root)
+	if err != nil {
+		t.Fatalf("newManager(%v) returned error: %v", root, err)
+	}
+	if err := m.Close(); err != nil {
+		t.Errorf("Close() returned error: %v", err)
+	}
+	if got, want := readFile(t, filepath.Join(root, "cgroup.subtree_control")), "+memory +cpu"; got != want {
+		t.Errorf("Close() changed cgroup.subtree_control of given root to %q, want %q", got, want)
+	}
+}
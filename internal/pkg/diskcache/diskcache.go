@@ -0,0 +1,166 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diskcache implements what the on-disk caches of reproxy have in common: an index of
+// the cached items bounded in size and ordered by recency of use, and helpers to write files.
+package diskcache
+
+import (
+	"container/list"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Item is an item of an on-disk cache.
+type Item struct {
+	Name string
+	Size int64
+	// ModTime is the modification time of the item on disk when it was added.
+	ModTime time.Time
+}
+
+// LRU is an index of the items of an on-disk cache. Once the total size of the items exceeds the
+// capacity of the index, the least recently used items are evicted. LRU is not thread safe.
+type LRU struct {
+	maxBytes int64
+	evict    func(name string)
+
+	size  int64
+	lru   *list.List
+	items map[string]*list.Element
+}
+
+// NewLRU creates an index holding items of at most maxBytes in total. evict is called with the
+// name of every item removed from the index, and is expected to remove it from disk.
+func NewLRU(maxBytes int64, evict func(name string)) *LRU {
+	return &LRU{
+		maxBytes: maxBytes,
+		evict:    evict,
+		lru:      list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Load adds items found on disk. Recency of use is not persisted, so they are ordered by their
+// modification time.
+func (l *LRU) Load(items []*Item) {
+	sort.Slice(items, func(i, j int) bool { return items[i].ModTime.Before(items[j].ModTime) })
+	for _, it := range items {
+		l.Add(it)
+	}
+}
+
+// Add adds the item as the most recently used one, unless an item with the same name is already
+// in the index, and evicts the least recently used items until the index fits in its capacity.
+func (l *LRU) Add(it *Item) {
+	if _, ok := l.items[it.Name]; ok {
+		return
+	}
+	l.items[it.Name] = l.lru.PushFront(it)
+	l.size += it.Size
+	for l.size > l.maxBytes && l.lru.Len() > 0 {
+		l.Remove(l.lru.Back().Value.(*Item).Name)
+	}
+}
+
+// Use marks the item with the given name as recently used and returns it, or nil if it is not in
+// the index.
+func (l *LRU) Use(name string) *Item {
+	el, ok := l.items[name]
+	if !ok {
+		return nil
+	}
+	l.lru.MoveToFront(el)
+	return el.Value.(*Item)
+}
+
+// Contains returns whether the item with the given name is in the index.
+func (l *LRU) Contains(name string) bool {
+	_, ok := l.items[name]
+	return ok
+}
+
+// Remove removes the item with the given name from the index and evicts it.
+func (l *LRU) Remove(name string) {
+	el, ok := l.items[name]
+	if !ok {
+		return
+	}
+	it := l.lru.Remove(el).(*Item)
+	delete(l.items, name)
+	l.size -= it.Size
+	l.evict(name)
+}
+
+// Len returns the number of items in the index.
+func (l *LRU) Len() int {
+	return l.lru.Len()
+}
+
+// Size returns the total size of the items in the index.
+func (l *LRU) Size() int64 {
+	return l.size
+}
+
+// PrepareDst removes any existing file at dst and creates its parent directory.
+func PrepareDst(dst string) error {
+	if err := os.Remove(dst); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return os.MkdirAll(filepath.Dir(dst), 0755)
+}
+
+// CopyFile copies the contents of src to dst, creating dst with the given mode if it doesn't
+// exist.
+func CopyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// DirSize returns the total size of the files under dir.
+func DirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += fi.Size()
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diskcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	var evicted []string
+	l := NewLRU(10, func(name string) { evicted = append(evicted, name) })
+	now := time.Now()
+	l.Load([]*Item{
+		{Name: "b", Size: 4, ModTime: now},
+		{Name: "a", Size: 4, ModTime: now.Add(-time.Hour)},
+	})
+	if it := l.Use("a"); it == nil || it.Size != 4 {
+		t.Fatalf("Use(a) = %v, want item of size 4", it)
+	}
+	l.Add(&Item{Name: "c", Size: 4})
+	if len(evicted) != 1 || evicted[0] != "b" {
+		t.Errorf("Add(c) evicted %v, want [b]", evicted)
+	}
+	if l.Contains("b") || !l.Contains("a") || !l.Contains("c") {
+		t.Errorf("LRU after Add(c) contains a=%v b=%v c=%v, want a=true b=false c=true", l.Contains("a"), l.Contains("b"), l.Contains("c"))
+	}
+	if l.Len() != 2 || l.Size() != 8 {
+		t.Errorf("LRU after Add(c) has %v items of %v bytes, want 2 items of 8 bytes", l.Len(), l.Size())
+	}
+	l.Remove("a")
+	if l.Use("a") != nil {
+		t.Errorf("Use(a) after Remove(a) returned an item, want nil")
+	}
+	if len(evicted) != 2 || evicted[1] != "a" {
+		t.Errorf("Remove(a) evicted %v, want [b a]", evicted)
+	}
+}
+
+func TestCopyFileAndDirSize(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	if err := os.WriteFile(src, []byte("content"), 0644); err != nil {
+		t.Fatalf("WriteFile(%v) failed: %v", src, err)
+	}
+	dst := filepath.Join(dir, "sub", "dst")
+	if err := PrepareDst(dst); err != nil {
+		t.Fatalf("PrepareDst(%v) failed: %v", dst, err)
+	}
+	if err := CopyFile(src, dst, 0755); err != nil {
+		t.Fatalf("CopyFile(%v, %v) failed: %v", src, dst, err)
+	}
+	fi, err := os.Stat(dst)
+	if err != nil {
+		t.Fatalf("Stat(%v) failed: %v", dst, err)
+	}
+	if fi.Mode().Perm() != 0755 {
+		t.Errorf("CopyFile() created file with mode %v, want %v", fi.Mode().Perm(), os.FileMode(0755))
+	}
+	if size, err := DirSize(dir); err != nil || size != 14 {
+		t.Errorf("DirSize(%v) = %v, %v, want 14, nil", dir, size, err)
+	}
+}
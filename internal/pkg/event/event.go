@@ -56,6 +56,14 @@ const (
 	// remote wins.
 	RacingFinalizationOverhead = "RacingFinalizationOverhead"
 
+	// HedgeHoldoff: time waited for the first remote execution of a hedged action before starting
+	// a hedged remote execution.
+	HedgeHoldoff = "HedgeHoldoff"
+
+	// HedgedRemoteExecution: time spent on the hedged remote execution of an action, until it
+	// completed or was canceled.
+	HedgedRemoteExecution = "HedgedRemoteExecution"
+
 	// AtomicOutputOverhead: time spent writing outputs atomically.
 	AtomicOutputOverhead = "AtomicOutputOverhead"
 
@@ -91,4 +99,8 @@ const (
 
 	// DepsCacheWriteCount is the number of deps cache entries written at reproxy shutdown.
 	DepsCacheWriteCount = "DepsCacheWriteCount"
+
+	// LocalResourceCapacityChanges is the number of times the capacity of the dynamic local
+	// resource pool changed.
+	LocalResourceCapacityChanges = "LocalResourceCapacityChanges"
 )
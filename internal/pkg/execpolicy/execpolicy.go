@@ -0,0 +1,280 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package execpolicy overrides the execution options of requests to reproxy based on rules
+// matching their labels and command lines.
+package execpolicy
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	lpb "github.com/bazelbuild/reclient/api/log"
+	ppb "github.com/bazelbuild/reclient/api/proxy"
+	cpb "github.com/bazelbuild/remote-apis-sdks/go/api/command"
+)
+
+const (
+	executionOptionsField = "execution_options"
+	platformField         = "platform"
+)
+
+// Policy overrides the execution options and platform properties of requests matching its
+// rules.
+type Policy struct {
+	rules []*rule
+}
+
+type rule struct {
+	name     string
+	labels   map[string]string
+	patterns []*regexp.Regexp
+	inverted []bool
+	opts     *ppb.ProxyExecutionOptions
+	// paths are the fields of opts to override, each given as a list of field names.
+	paths    [][]string
+	platform map[string]string
+}
+
+// New creates a policy from a textproto file containing an ExecutionPolicyConfig.
+func New(configPath string) (*Policy, error) {
+	if configPath == "" {
+		return nil, nil
+	}
+	config, err := readConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create execution policy from file %v, error: %v", configPath, err)
+	}
+	return fromConfig(config)
+}
+
+func readConfig(configPath string) (*ppb.ExecutionPolicyConfig, error) {
+	blob, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read execution policy config from %v, error: %v", configPath, err)
+	}
+	config := &ppb.ExecutionPolicyConfig{}
+	if err = prototext.Unmarshal(blob, config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal text proto to ExecutionPolicyConfig proto, text: %v error: %v", string(blob), err)
+	}
+	return config, nil
+}
+
+func fromConfig(conf *ppb.ExecutionPolicyConfig) (*Policy, error) {
+	p := &Policy{}
+	for i, rpb := range conf.GetRules() {
+		r, err := newRule(i, rpb)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create rule from %+v: %v", rpb, err)
+		}
+		p.rules = append(p.rules, r)
+	}
+	return p, nil
+}
+
+func newRule(idx int, rpb *ppb.ExecutionPolicyRule) (*rule, error) {
+	r := &rule{
+		name:     rpb.GetName(),
+		labels:   rpb.GetLabels(),
+		opts:     rpb.GetExecutionOptions(),
+		platform: rpb.GetPlatform(),
+	}
+	if r.name == "" {
+		r.name = strconv.Itoa(idx)
+	}
+	for _, pat := range rpb.GetCommandPatterns() {
+		re, err := regexp.Compile(pat.GetExpression())
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %v", pat.GetExpression())
+		}
+		r.patterns = append(r.patterns, re)
+		r.inverted = append(r.inverted, pat.GetInverted())
+	}
+	if r.opts == nil {
+		r.opts = &ppb.ProxyExecutionOptions{}
+	}
+	if mask := rpb.GetExecutionOptionsMask(); len(mask.GetPaths()) > 0 {
+		for _, path := range mask.GetPaths() {
+			names := strings.Split(path, ".")
+			if err := validatePath(r.opts.ProtoReflect().Descriptor(), names); err != nil {
+				return nil, fmt.Errorf("invalid execution_options_mask path %q: %v", path, err)
+			}
+			r.paths = append(r.paths, names)
+		}
+	} else {
+		r.paths = populatedPaths(r.opts.ProtoReflect(), nil)
+		sort.Slice(r.paths, func(i, j int) bool {
+			return strings.Join(r.paths[i], ".") < strings.Join(r.paths[j], ".")
+		})
+	}
+	return r, nil
+}
+
+// validatePath checks that names is a path of fields in messages of type md.
+func validatePath(md protoreflect.MessageDescriptor, names []string) error {
+	for i, n := range names {
+		fd := md.Fields().ByName(protoreflect.Name(n))
+		if fd == nil {
+			return fmt.Errorf("no field %v in %v", n, md.FullName())
+		}
+		if i == len(names)-1 {
+			return nil
+		}
+		if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+			return fmt.Errorf("field %v of %v is not a message", n, md.FullName())
+		}
+		md = fd.Message()
+	}
+	return nil
+}
+
+// populatedPaths returns the paths of all scalar fields with a non-default value in m.
+func populatedPaths(m protoreflect.Message, prefix []string) [][]string {
+	var paths [][]string
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		path := append(append([]string{}, prefix...), string(fd.Name()))
+		if fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() {
+			paths = append(paths, populatedPaths(v.Message(), path)...)
+			return true
+		}
+		paths = append(paths, path)
+		return true
+	})
+	return paths
+}
+
+// Apply returns the request with the overrides of all matching rules applied, along with the
+// applied overrides. The given request is not modified.
+func (p *Policy) Apply(req *ppb.RunRequest) (*ppb.RunRequest, []*lpb.PolicyOverride) {
+	if p == nil {
+		return req, nil
+	}
+	var overrides []*lpb.PolicyOverride
+	cloned := false
+	for _, r := range p.rules {
+		if !r.matches(req) {
+			continue
+		}
+		if !cloned {
+			req = proto.Clone(req).(*ppb.RunRequest)
+			cloned = true
+		}
+		overrides = append(overrides, r.apply(req)...)
+	}
+	return req, overrides
+}
+
+func (r *rule) matches(req *ppb.RunRequest) bool {
+	for k, v := range r.labels {
+		if lv, ok := req.GetLabels()[k]; !ok || lv != v {
+			return false
+		}
+	}
+	if len(r.patterns) == 0 {
+		return true
+	}
+	cmdLine := strings.Join(req.GetCommand().GetArgs(), " ")
+	for i, re := range r.patterns {
+		if re.MatchString(cmdLine) == r.inverted[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *rule) apply(req *ppb.RunRequest) []*lpb.PolicyOverride {
+	var overrides []*lpb.PolicyOverride
+	if len(r.paths) > 0 && req.ExecutionOptions == nil {
+		req.ExecutionOptions = &ppb.ProxyExecutionOptions{}
+	}
+	for _, path := range r.paths {
+		old, val := overrideField(req.ExecutionOptions.ProtoReflect(), r.opts.ProtoReflect(), path)
+		overrides = append(overrides, &lpb.PolicyOverride{
+			Rule:          r.name,
+			Field:         executionOptionsField + "." + strings.Join(path, "."),
+			OriginalValue: old,
+			Value:         val,
+		})
+	}
+	if len(r.platform) == 0 {
+		return overrides
+	}
+	if req.Command == nil {
+		req.Command = &cpb.Command{}
+	}
+	if req.Command.Platform == nil {
+		req.Command.Platform = make(map[string]string)
+	}
+	keys := make([]string, 0, len(r.platform))
+	for k := range r.platform {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		overrides = append(overrides, &lpb.PolicyOverride{
+			Rule:          r.name,
+			Field:         platformField + "." + k,
+			OriginalValue: req.Command.Platform[k],
+			Value:         r.platform[k],
+		})
+		req.Command.Platform[k] = r.platform[k]
+	}
+	return overrides
+}
+
+// overrideField sets the field at path in dst to its value in src, and returns the string forms
+// of the old and new values.
+func overrideField(dst, src protoreflect.Message, path []string) (string, string) {
+	for _, n := range path[:len(path)-1] {
+		fd := dst.Descriptor().Fields().ByName(protoreflect.Name(n))
+		dst = dst.Mutable(fd).Message()
+		src = src.Get(fd).Message()
+	}
+	fd := dst.Descriptor().Fields().ByName(protoreflect.Name(path[len(path)-1]))
+	old := formatValue(fd, dst.Get(fd))
+	if src.Has(fd) {
+		dst.Set(fd, cloneValue(fd, src.Get(fd)))
+	} else {
+		dst.Clear(fd)
+	}
+	return old, formatValue(fd, dst.Get(fd))
+}
+
+func cloneValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) protoreflect.Value {
+	if fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() {
+		return protoreflect.ValueOfMessage(proto.Clone(v.Message().Interface()).ProtoReflect())
+	}
+	return v
+}
+
+func formatValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch {
+	case fd.Kind() == protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	case fd.Kind() == protoreflect.MessageKind:
+		return prototext.MarshalOptions{}.Format(v.Message().Interface())
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,203 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package execpolicy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	lpb "github.com/bazelbuild/reclient/api/log"
+	ppb "github.com/bazelbuild/reclient/api/proxy"
+	cpb "github.com/bazelbuild/remote-apis-sdks/go/api/command"
+)
+
+const textConfig = `
+rules {
+  name: "javac-racing"
+  labels {
+    key: "type"
+    value: "compile"
+  }
+  labels {
+    key: "compiler"
+    value: "javac"
+  }
+  execution_options {
+    execution_strategy: RACING
+    remote_execution_options {
+      download_outputs: true
+    }
+  }
+  platform {
+    key: "container-image"
+    value: "docker://java"
+  }
+}
+rules {
+  command_patterns {
+    expression: "--no-cache"
+  }
+  execution_options_mask {
+    paths: "remote_execution_options.accept_cached"
+  }
+}
+`
+
+func writeConfig(t *testing.T, config string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.textproto")
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatalf("Unable to write config to %v: %v", path, err)
+	}
+	return path
+}
+
+func TestNew(t *testing.T) {
+	if p, err := New(""); p != nil || err != nil {
+		t.Errorf("New(\"\") = %v, %v, want nil, nil", p, err)
+	}
+	if _, err := New("non-exist.textproto"); err == nil {
+		t.Errorf("New(non-exist.textproto) succeeded, want error")
+	}
+	if _, err := New(writeConfig(t, textConfig)); err != nil {
+		t.Errorf("New() returned error: %v", err)
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{
+			name:   "invalid regex",
+			config: `rules { command_patterns { expression: "(" } }`,
+		},
+		{
+			name:   "unknown mask field",
+			config: `rules { execution_options_mask { paths: "remote_execution_options.foo" } }`,
+		},
+		{
+			name:   "mask through scalar field",
+			config: `rules { execution_options_mask { paths: "compare_with_local.foo" } }`,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := New(writeConfig(t, tc.config)); err == nil {
+				t.Errorf("New() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	p, err := New(writeConfig(t, textConfig))
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	tests := []struct {
+		name          string
+		req           *ppb.RunRequest
+		want          *ppb.RunRequest
+		wantOverrides []*lpb.PolicyOverride
+	}{
+		{
+			name: "no match",
+			req: &ppb.RunRequest{
+				Command: &cpb.Command{Args: []string{"clang", "-c", "a.cc"}},
+				Labels:  map[string]string{"type": "compile", "compiler": "clang"},
+			},
+			want: &ppb.RunRequest{
+				Command: &cpb.Command{Args: []string{"clang", "-c", "a.cc"}},
+				Labels:  map[string]string{"type": "compile", "compiler": "clang"},
+			},
+		},
+		{
+			name: "label match",
+			req: &ppb.RunRequest{
+				Command: &cpb.Command{Args: []string{"javac", "A.java"}},
+				Labels:  map[string]string{"type": "compile", "compiler": "javac", "lang": "java"},
+				ExecutionOptions: &ppb.ProxyExecutionOptions{
+					ExecutionStrategy:      ppb.ExecutionStrategy_REMOTE,
+					RemoteExecutionOptions: &ppb.RemoteExecutionOptions{AcceptCached: true},
+				},
+			},
+			want: &ppb.RunRequest{
+				Command: &cpb.Command{
+					Args:     []string{"javac", "A.java"},
+					Platform: map[string]string{"container-image": "docker://java"},
+				},
+				Labels: map[string]string{"type": "compile", "compiler": "javac", "lang": "java"},
+				ExecutionOptions: &ppb.ProxyExecutionOptions{
+					ExecutionStrategy:      ppb.ExecutionStrategy_RACING,
+					RemoteExecutionOptions: &ppb.RemoteExecutionOptions{AcceptCached: true, DownloadOutputs: true},
+				},
+			},
+			wantOverrides: []*lpb.PolicyOverride{
+				{Rule: "javac-racing", Field: "execution_options.execution_strategy", OriginalValue: "REMOTE", Value: "RACING"},
+				{Rule: "javac-racing", Field: "execution_options.remote_execution_options.download_outputs", OriginalValue: "false", Value: "true"},
+				{Rule: "javac-racing", Field: "platform.container-image", OriginalValue: "", Value: "docker://java"},
+			},
+		},
+		{
+			name: "command match overrides to default value",
+			req: &ppb.RunRequest{
+				Command: &cpb.Command{Args: []string{"tool", "--no-cache"}},
+				ExecutionOptions: &ppb.ProxyExecutionOptions{
+					RemoteExecutionOptions: &ppb.RemoteExecutionOptions{AcceptCached: true},
+				},
+			},
+			want: &ppb.RunRequest{
+				Command: &cpb.Command{Args: []string{"tool", "--no-cache"}},
+				ExecutionOptions: &ppb.ProxyExecutionOptions{
+					RemoteExecutionOptions: &ppb.RemoteExecutionOptions{},
+				},
+			},
+			wantOverrides: []*lpb.PolicyOverride{
+				{Rule: "1", Field: "execution_options.remote_execution_options.accept_cached", OriginalValue: "true", Value: "false"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, gotOverrides := p.Apply(tc.req)
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("Apply() returned diff in request: (-want +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantOverrides, gotOverrides, protocmp.Transform()); diff != "" {
+				t.Errorf("Apply() returned diff in overrides: (-want +got)\n%s", diff)
+			}
+			if len(tc.wantOverrides) > 0 && got == tc.req {
+				t.Errorf("Apply() modified the given request in place")
+			}
+		})
+	}
+}
+
+func TestApplyNilPolicy(t *testing.T) {
+	var p *Policy
+	req := &ppb.RunRequest{Labels: map[string]string{"type": "tool"}}
+	got, overrides := p.Apply(req)
+	if got != req || overrides != nil {
+		t.Errorf("Apply() on nil policy = %v, %v, want %v, nil", got, overrides, req)
+	}
+}
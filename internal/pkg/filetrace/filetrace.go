@@ -0,0 +1,49 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filetrace records the files accessed by locally executed commands.
+package filetrace
+
+// Access is a single file system access made by a traced command.
+type Access struct {
+	// Path is the accessed path as passed to the system call. Relative paths are relative to the
+	// working directory of the command.
+	Path string
+	// Missing is true if the access failed because the path does not exist.
+	Missing bool
+	// Write is true if the path was opened for writing.
+	Write bool
+}
+
+// Missing returns the paths of the accesses that failed because the path does not exist.
+func Missing(accesses []Access) []string {
+	var res []string
+	for _, a := range accesses {
+		if a.Missing {
+			res = append(res, a.Path)
+		}
+	}
+	return res
+}
+
+// Read returns the paths of the accesses that successfully read an existing path.
+func Read(accesses []Access) []string {
+	var res []string
+	for _, a := range accesses {
+		if !a.Missing && !a.Write {
+			res = append(res, a.Path)
+		}
+	}
+	return res
+}
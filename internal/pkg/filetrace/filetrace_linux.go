@@ -0,0 +1,158 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filetrace
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/golang/glog"
+)
+
+var (
+	straceOnce sync.Once
+	stracePath string
+
+	// writeSyscalls are the traced system calls that modify the file system rather than read it.
+	writeSyscalls = map[string]bool{
+		"creat": true, "mkdir": true, "mkdirat": true, "mknod": true, "mknodat": true,
+		"rmdir": true, "unlink": true, "unlinkat": true, "rename": true, "renameat": true,
+		"renameat2": true, "link": true, "linkat": true, "symlink": true, "symlinkat": true,
+		"chmod": true, "fchmodat": true, "chown": true, "fchownat": true, "lchown": true,
+		"truncate": true, "utime": true, "utimes": true, "utimensat": true,
+	}
+)
+
+// straceBinary returns the path to a working strace binary, or an empty string if strace is not
+// installed or is not permitted to trace processes.
+func straceBinary() string {
+	straceOnce.Do(func() {
+		p, err := exec.LookPath("strace")
+		if err != nil {
+			log.Infof("strace not found, file accesses of local actions will not be traced")
+			return
+		}
+		if err := exec.Command(p, "-f", "-qq", "-o", os.DevNull, "-e", "trace=file", "true").Run(); err != nil {
+			log.Warningf("strace is not usable, file accesses of local actions will not be traced: %v", err)
+			return
+		}
+		stracePath = p
+	})
+	return stracePath
+}
+
+// Tracer records the file accesses of a command using strace.
+type Tracer struct {
+	bin  string
+	file string
+}
+
+// New creates a tracer writing its trace to a temporary file. Returns nil if tracing is not
+// supported on this machine.
+func New() *Tracer {
+	bin := straceBinary()
+	if bin == "" {
+		return nil
+	}
+	f, err := os.CreateTemp("", "reproxy_trace_")
+	if err != nil {
+		log.Warningf("Failed to create trace file: %v", err)
+		return nil
+	}
+	f.Close()
+	return &Tracer{bin: bin, file: f.Name()}
+}
+
+// Wrap returns args wrapped in a strace invocation writing to the trace file.
+func (t *Tracer) Wrap(args []string) []string {
+	return append([]string{t.bin, "-f", "-qq", "-o", t.file, "-e", "trace=file", "--"}, args...)
+}
+
+// Accesses returns the file accesses recorded by the tracer.
+func (t *Tracer) Accesses() ([]Access, error) {
+	f, err := os.Open(t.file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var res []Access
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		if a, ok := parseLine(s.Text()); ok {
+			res = append(res, a)
+		}
+	}
+	return res, s.Err()
+}
+
+// Close removes the trace file.
+func (t *Tracer) Close() {
+	if err := os.Remove(t.file); err != nil {
+		log.Warningf("Failed to remove trace file %v: %v", t.file, err)
+	}
+}
+
+// parseLine parses a single strace output line. Only accesses of paths relative to the current
+// working directory or absolute paths are returned, since paths relative to other directory file
+// descriptors cannot be resolved. Calls split by strace across lines are ignored.
+func parseLine(line string) (Access, bool) {
+	open := strings.IndexByte(line, '(')
+	if open < 0 {
+		return Access{}, false
+	}
+	fields := strings.Fields(line[:open])
+	if len(fields) == 0 {
+		return Access{}, false
+	}
+	syscall := fields[len(fields)-1]
+	args := strings.TrimPrefix(line[open+1:], "AT_FDCWD, ")
+	if !strings.HasPrefix(args, `"`) {
+		return Access{}, false
+	}
+	end := 1
+	for end < len(args) && args[end] != '"' {
+		if args[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end >= len(args) {
+		return Access{}, false
+	}
+	p, err := strconv.Unquote(args[:end+1])
+	if err != nil {
+		return Access{}, false
+	}
+	ret := strings.LastIndex(args, ") = ")
+	if ret < 0 {
+		return Access{}, false
+	}
+	res := args[ret+len(") = "):]
+	a := Access{Path: p}
+	switch {
+	case strings.HasPrefix(res, "-1 ENOENT"):
+		a.Missing = true
+	case strings.HasPrefix(res, "-1 "), strings.HasPrefix(res, "?"):
+		return Access{}, false
+	}
+	flags := args[end+1 : ret]
+	a.Write = writeSyscalls[syscall] || strings.Contains(flags, "O_WRONLY") || strings.Contains(flags, "O_RDWR") || strings.Contains(flags, "O_CREAT")
+	return a, true
+}
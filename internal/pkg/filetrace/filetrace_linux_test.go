@@ -0,0 +1,74 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filetrace
+
+import (
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   Access
+		wantOk bool
+	}{
+		{
+			line:   `1234  openat(AT_FDCWD, "../include/foo.h", O_RDONLY|O_CLOEXEC) = -1 ENOENT (No such file or directory)`,
+			want:   Access{Path: "../include/foo.h", Missing: true},
+			wantOk: true,
+		},
+		{
+			line:   `1234  stat("/src/a \"b\".h", {st_mode=S_IFREG|0644, st_size=10, ...}) = 0`,
+			want:   Access{Path: `/src/a "b".h`},
+			wantOk: true,
+		},
+		{
+			line:   `1234  openat(AT_FDCWD, "foo.h", O_RDONLY) = 3`,
+			want:   Access{Path: "foo.h"},
+			wantOk: true,
+		},
+		{
+			line:   `1234  openat(AT_FDCWD, "foo.o", O_WRONLY|O_CREAT|O_TRUNC, 0666) = 4`,
+			want:   Access{Path: "foo.o", Write: true},
+			wantOk: true,
+		},
+		{
+			line:   `1234  unlink("foo.d") = 0`,
+			want:   Access{Path: "foo.d", Write: true},
+			wantOk: true,
+		},
+		{
+			line: `1234  openat(AT_FDCWD, "foo.h", O_RDONLY) = -1 EACCES (Permission denied)`,
+		},
+		{
+			line: `1234  openat(5, "foo.h", O_RDONLY) = -1 ENOENT (No such file or directory)`,
+		},
+		{
+			line: `1234  openat(AT_FDCWD, "foo.h", O_RDONLY <unfinished ...>`,
+		},
+		{
+			line: `1234  <... openat resumed>) = 3`,
+		},
+		{
+			line: `1234  +++ exited with 0 +++`,
+		},
+	}
+	for _, tc := range tests {
+		got, ok := parseLine(tc.line)
+		if got != tc.want || ok != tc.wantOk {
+			t.Errorf("parseLine(%q) = %+v, %v, want %+v, %v", tc.line, got, ok, tc.want, tc.wantOk)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+
+package filetrace
+
+// Tracer records the file accesses of a command. Tracing is only supported on linux.
+type Tracer struct{}
+
+// New returns nil since tracing is not supported on this platform.
+func New() *Tracer {
+	return nil
+}
+
+// Wrap returns args unchanged.
+func (t *Tracer) Wrap(args []string) []string {
+	return args
+}
+
+// Accesses returns no accesses.
+func (t *Tracer) Accesses() ([]Access, error) {
+	return nil, nil
+}
+
+// Close does nothing.
+func (t *Tracer) Close() {}
@@ -0,0 +1,131 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/args"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/clangparser"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/flags"
+)
+
+var (
+	// gccOptions are GCC-only flags which take a separate value and are not part of the clang
+	// flag table.
+	gccOptions = map[string]int{
+		"-aux-info":   1,
+		"-imultiarch": 1,
+		"-imultilib":  1,
+		"-wrapper":    1,
+	}
+	// gccPrefixes are GCC-only flags which take a joined value and are not part of the clang
+	// flag table.
+	gccPrefixes = []args.PrefixOption{
+		{Prefix: "-iplugindir="},
+	}
+
+	tablesOnce sync.Once
+	options    map[string]int
+	prefixes   []args.PrefixOption
+)
+
+// gccTables returns the flag tables used to parse GCC commands, which are the clang flag
+// tables extended with GCC-only flags.
+func gccTables() (map[string]int, []args.PrefixOption) {
+	tablesOnce.Do(func() {
+		options = make(map[string]int, len(clangparser.ClangOptions)+len(gccOptions))
+		for k, v := range clangparser.ClangOptions {
+			options[k] = v
+		}
+		for k, v := range gccOptions {
+			options[k] = v
+		}
+		prefixes = append(append([]args.PrefixOption{}, clangparser.ClangPrefixes...), gccPrefixes...)
+		sort.Slice(prefixes, func(i, j int) bool {
+			return prefixes[i].Prefix > prefixes[j].Prefix
+		})
+	})
+	return options, prefixes
+}
+
+// isUnsupported returns true if the given flag is not understood by the clang based
+// dependency scanner.
+func isUnsupported(res *args.FlagResult) bool {
+	key := res.NormalizedKey
+	if key == "" || key == "-" {
+		return false
+	}
+	if _, ok := gccOptions[key]; ok {
+		return true
+	}
+	for _, p := range gccPrefixes {
+		if key == p.Prefix {
+			return true
+		}
+	}
+	if res.Joined {
+		return false
+	}
+	_, ok := clangparser.ClangOptions[key]
+	return !ok
+}
+
+// parseFlags is used to translate the given action command into gcc compiler options, so that
+// they can be used during input processing. It also returns the flags of the command that are
+// not understood by the dependency scanner.
+func parseFlags(ctx context.Context, command []string, workingDir, execRoot string) (*flags.CommandFlags, []string, error) {
+	numArgs := len(command)
+	if numArgs < 2 {
+		return nil, nil, fmt.Errorf("insufficient number of arguments in command: %v", command)
+	}
+
+	res := &flags.CommandFlags{
+		ExecutablePath:   command[0],
+		TargetFilePaths:  []string{},
+		WorkingDirectory: workingDir,
+		ExecRoot:         execRoot,
+	}
+	opts, prefs := gccTables()
+	s := &args.Scanner{
+		Args:       command[1:],
+		Flags:      opts,
+		Joined:     prefs,
+		Normalized: clangparser.ClangNormalizedFlags,
+	}
+	var state clangparser.State
+	var unsupported []string
+	defer state.Finalize(res)
+	for s.HasNext() {
+		nextRes := s.ReadNextFlag()
+		if isUnsupported(nextRes) {
+			unsupported = append(unsupported, nextRes.Args[0])
+		}
+		switch nextRes.NormalizedKey {
+		case "-specs=", "--specs=", "--specs", "-fplugin=":
+			// Spec files and plugins that don't exist relative to the working directory are
+			// looked up in GCC's search directories, and are expected to be part of the
+			// toolchain. Non-existent dependencies are dropped when sanitizing the inputs.
+			res.Dependencies = append(res.Dependencies, nextRes.Values[0])
+		}
+		if err := state.HandleClangFlags(nextRes, res, false); err != nil {
+			return res, unsupported, err
+		}
+	}
+	return res, unsupported, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/flags"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const fakeExecRoot = "fake"
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name            string
+		command         []string
+		want            *flags.CommandFlags
+		wantUnsupported []string
+	}{
+		{
+			name: "simple gcc command",
+			command: []string{
+				"gcc",
+				"-Iinclude",
+				"-fplugin=plugins/foo.so",
+				"--specs=foo.specs",
+				"-c",
+				"test.c",
+				"-o", "test.o",
+			},
+			want: &flags.CommandFlags{
+				ExecutablePath: "gcc",
+				Flags: []*flags.Flag{
+					{Key: "-I", Value: "include", Joined: true},
+					{Key: "-fplugin=", Value: "plugins/foo.so", Joined: true},
+					{Key: "--specs=", Value: "foo.specs", Joined: true},
+					{Key: "-c"},
+				},
+				Dependencies:       []string{"plugins/foo.so", "foo.specs"},
+				ExecRoot:           fakeExecRoot,
+				TargetFilePaths:    []string{"test.c"},
+				OutputFilePaths:    []string{"test.o"},
+				OutputDirPaths:     []string{},
+				IncludeDirPaths:    []string{"include"},
+				VirtualDirectories: []string{},
+			},
+		},
+		{
+			name: "gcc only flags",
+			command: []string{
+				"gcc",
+				"-imultiarch", "x86_64-linux-gnu",
+				"-iplugindir=plugins",
+				"-c",
+				"test.c",
+				"-o", "test.o",
+			},
+			want: &flags.CommandFlags{
+				ExecutablePath: "gcc",
+				Flags: []*flags.Flag{
+					{Key: "-imultiarch", Value: "x86_64-linux-gnu"},
+					{Key: "-iplugindir=", Value: "plugins", Joined: true},
+					{Key: "-c"},
+				},
+				ExecRoot:        fakeExecRoot,
+				TargetFilePaths: []string{"test.c"},
+				OutputFilePaths: []string{"test.o"},
+			},
+			wantUnsupported: []string{"-imultiarch", "-iplugindir=plugins"},
+		},
+		{
+			name: "search prefixes and multilib",
+			command: []string{
+				"gcc",
+				"-B", "toolchain/bin",
+				"-Btoolchain/lib",
+				"-iprefix", "include/",
+				"-imultilib", "32",
+				"-c",
+				"test.c",
+				"-o", "test.o",
+			},
+			want: &flags.CommandFlags{
+				ExecutablePath: "gcc",
+				Flags: []*flags.Flag{
+					{Key: "-B", Value: "toolchain/bin"},
+					{Key: "-B", Value: "toolchain/lib", Joined: true},
+					{Key: "-iprefix", Value: "include/"},
+					{Key: "-imultilib", Value: "32"},
+					{Key: "-c"},
+				},
+				Dependencies:    []string{"toolchain/bin", "toolchain/lib"},
+				ExecRoot:        fakeExecRoot,
+				TargetFilePaths: []string{"test.c"},
+				OutputFilePaths: []string{"test.o"},
+			},
+			wantUnsupported: []string{"-imultilib"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotUnsupported, err := parseFlags(context.Background(), test.command, "", fakeExecRoot)
+			if err != nil {
+				t.Fatalf("parseFlags(%v) failed: %v", test.command, err)
+			}
+			if diff := cmp.Diff(test.want, got, cmpopts.EquateEmpty(), cmpopts.IgnoreUnexported(flags.Flag{})); diff != "" {
+				t.Errorf("parseFlags(%v) returned diff (-want +got): %v", test.command, diff)
+			}
+			if diff := cmp.Diff(test.wantUnsupported, gotUnsupported); diff != "" {
+				t.Errorf("parseFlags(%v) returned unsupported flags diff (-want +got): %v", test.command, diff)
+			}
+		})
+	}
+}
+
+func TestParseFlagsSpecs(t *testing.T) {
+	for _, specs := range []string{"-specs=foo.specs", "--specs=foo.specs"} {
+		command := []string{"gcc", specs, "-c", "test.c", "-o", "test.o"}
+		got, gotUnsupported, err := parseFlags(context.Background(), command, "", fakeExecRoot)
+		if err != nil {
+			t.Fatalf("parseFlags(%v) failed: %v", command, err)
+		}
+		if diff := cmp.Diff([]string{"foo.specs"}, got.Dependencies); diff != "" {
+			t.Errorf("parseFlags(%v) returned dependencies diff (-want +got): %v", command, diff)
+		}
+		if len(gotUnsupported) > 0 {
+			t.Errorf("parseFlags(%v) returned unsupported flags %v, want none", command, gotUnsupported)
+		}
+	}
+}
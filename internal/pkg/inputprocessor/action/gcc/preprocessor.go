@@ -0,0 +1,149 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gcc performs include processing given a valid gcc action.
+package gcc
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/cppcompile"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/flags"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+
+	log "github.com/golang/glog"
+)
+
+var (
+	toAbsArgs = map[string]bool{
+		"--sysroot":  true,
+		"--sysroot=": true,
+		"-isysroot":  true,
+		"-iprefix":   true,
+	}
+	virtualInputFlags = map[string]bool{
+		"-I":           true,
+		"-isystem":     true,
+		"-isysroot":    true,
+		"--sysroot=":   true,
+		"--sysroot":    true,
+		"-iprefix":     true,
+		"-iplugindir=": true,
+	}
+	// toRemoveFlags are flags that don't affect include processing but that the dependency
+	// scanner would act upon, such as by loading GCC plugins.
+	toRemoveFlags = map[string]bool{
+		"-fplugin=":     true,
+		"-fplugin-arg-": true,
+		"-specs=":       true,
+		"--specs=":      true,
+		"--specs":       true,
+	}
+)
+
+// Preprocessor is the preprocessor of gcc compile actions.
+type Preprocessor struct {
+	*cppcompile.Preprocessor
+	// unsupportedFlags are the flags of the command that are not understood by the dependency
+	// scanner.
+	unsupportedFlags []string
+}
+
+// ParseFlags parses the commands flags and populates the ActionSpec object with inferred
+// information.
+func (p *Preprocessor) ParseFlags() error {
+	f, unsupported, err := parseFlags(p.Ctx, p.Options.Cmd, p.Options.WorkingDir, p.Options.ExecRoot)
+	if err != nil {
+		p.Err = fmt.Errorf("flag parsing failed. %v", err)
+		return p.Err
+	}
+	p.Flags = f
+	p.unsupportedFlags = unsupported
+	p.FlagsToActionSpec()
+	return nil
+}
+
+// ComputeSpec computes cpp header dependencies. Commands with GCC-only flags that the
+// dependency scanner doesn't understand are not scanned, which degrades them to shallow mode
+// if it is allowed for the action.
+func (p *Preprocessor) ComputeSpec() error {
+	s := &inputprocessor.ActionSpec{InputSpec: &command.InputSpec{}}
+	defer p.AppendSpec(s)
+
+	if len(p.unsupportedFlags) > 0 {
+		s.UsedShallowMode = true
+		return fmt.Errorf("gcc flags not supported by the dependency scanner: %v", p.unsupportedFlags)
+	}
+	var scanFlags []*flags.Flag
+	for _, f := range p.Flags.Flags {
+		if !toRemoveFlags[f.Key] {
+			scanFlags = append(scanFlags, f)
+		}
+	}
+	p.Flags.Flags = scanFlags
+
+	args := p.BuildCommandLine("-o", false, toAbsArgs)
+	if p.CPPDepScanner.Capabilities().GetExpectsResourceDir() {
+		args = p.addResourceDir(args)
+	}
+	headerInputFiles, err := p.FindDependencies(args)
+	if err != nil {
+		s.UsedShallowMode = true
+		return err
+	}
+
+	s.InputSpec = &command.InputSpec{
+		Inputs:        headerInputFiles,
+		VirtualInputs: cppcompile.VirtualInputs(p.Flags, p),
+	}
+	return nil
+}
+
+// IsVirtualInput returns true if the flag specifies a virtual input to be added to InputSpec.
+func (p *Preprocessor) IsVirtualInput(flag string) bool {
+	return virtualInputFlags[flag]
+}
+
+func (p *Preprocessor) addResourceDir(args []string) []string {
+	for _, arg := range args {
+		if arg == "-resource-dir" {
+			return args
+		}
+	}
+	resourceDir := p.resourceDir(args)
+	if resourceDir != "" {
+		return append(args, "-resource-dir", resourceDir)
+	}
+	return args
+}
+
+// resourceDir returns the installation directory of gcc, which holds the compiler specific
+// headers like clang's resource directory does.
+func (p *Preprocessor) resourceDir(args []string) string {
+	return p.ResourceDir(args, "-print-search-dirs", func(stdout string) (string, error) {
+		for _, l := range strings.Split(stdout, "\n") {
+			l = strings.TrimSpace(l)
+			if strings.HasPrefix(l, "install:") {
+				resourceDir := filepath.Clean(strings.TrimSpace(strings.TrimPrefix(l, "install:")))
+				log.Infof("%s -print-search-dirs => resource-dir:%q", args[0], resourceDir)
+				return resourceDir, nil
+			}
+		}
+		return "", fmt.Errorf("unexpected search dirs of %s: %q", args[0], stdout)
+	})
+}
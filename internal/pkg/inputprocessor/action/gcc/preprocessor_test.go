@@ -0,0 +1,203 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	spb "github.com/bazelbuild/reclient/api/scandeps"
+	"github.com/bazelbuild/reclient/internal/pkg/execroot"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/cppcompile"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResourceDir(t *testing.T) {
+	ctx := context.Background()
+	execRoot := t.TempDir()
+	gcc := filepath.Join(execRoot, "toolchain/bin/gcc")
+	if err := os.MkdirAll(filepath.Dir(gcc), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(gcc, nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(execRoot, "toolchain/lib/gcc/x86_64-linux-gnu/12")
+
+	e := &stubExecutor{
+		outStr: "install: " + want + "/\n" +
+			"programs: =" + filepath.Join(execRoot, "toolchain/libexec/gcc/x86_64-linux-gnu/12") + "/\n" +
+			"libraries: =" + want + "/\n",
+	}
+	p := &Preprocessor{
+		Preprocessor: &cppcompile.Preprocessor{
+			BasePreprocessor: &inputprocessor.BasePreprocessor{
+				Ctx:      ctx,
+				Executor: e,
+			},
+		},
+	}
+
+	got := p.resourceDir([]string{gcc, "-c", "../../base/foo.cc", "-o", "obj/base/base/foo.o"})
+	if got != want {
+		t.Errorf("p.resourceDir([]string{%q, ..})=%q; want=%q", gcc, got, want)
+	}
+	wantCmd := &command.Command{
+		Args:       []string{gcc, "-print-search-dirs"},
+		WorkingDir: "/",
+	}
+	if !cmp.Equal(e.gotCmd, wantCmd) {
+		t.Errorf("executor got=%v; want=%v", e.gotCmd, wantCmd)
+	}
+}
+
+func TestComputeSpec(t *testing.T) {
+	ctx := context.Background()
+	s := &stubCPPDepScanner{
+		res:          []string{"foo.h"},
+		capabilities: &spb.CapabilitiesResponse{},
+	}
+	er, cleanup := execroot.Setup(t, []string{"bin/gcc", "out/dummy"})
+	t.Cleanup(cleanup)
+	p := Preprocessor{
+		Preprocessor: &cppcompile.Preprocessor{
+			BasePreprocessor: &inputprocessor.BasePreprocessor{Ctx: ctx},
+			CPPDepScanner:    s,
+		},
+	}
+	p.Options = inputprocessor.Options{
+		ExecRoot:   er,
+		WorkingDir: "out",
+		Cmd: []string{
+			filepath.Join(er, "bin/gcc"),
+			"-I", "a/b",
+			"-fplugin=../plugins/foo.so",
+			"-fplugin-arg-foo-bar=baz",
+			"--specs=foo.specs",
+			"-c",
+			"test.cpp",
+			"-o", "test.o",
+		},
+	}
+	if err := p.ParseFlags(); err != nil {
+		t.Fatalf("ParseFlags() failed: %v", err)
+	}
+	if err := p.ComputeSpec(); err != nil {
+		t.Fatalf("ComputeSpec() failed: %v", err)
+	}
+	spec, _ := p.Spec()
+	if spec.UsedShallowMode {
+		t.Errorf("ComputeSpec() used shallow mode, want dependency scanning")
+	}
+	if diff := cmp.Diff([]string{filepath.Join("out", "test.o")}, spec.OutputFiles); diff != "" {
+		t.Errorf("OutputFiles has diff (-want +got): %s", diff)
+	}
+	wantVirtualInputs := []*command.VirtualInput{
+		{Path: filepath.Join("out", "a/b"), IsEmptyDirectory: true},
+	}
+	if diff := cmp.Diff(wantVirtualInputs, spec.InputSpec.VirtualInputs); diff != "" {
+		t.Errorf("InputSpec.VirtualInputs had diff (-want +got): %v", diff)
+	}
+	wantCmd := []string{
+		filepath.Join(er, "bin/gcc"),
+		"-I", "a/b",
+		"-c",
+		"-Qunused-arguments",
+		"-o", "test.o",
+		filepath.Join(er, "out", "test.cpp"),
+	}
+	if diff := cmp.Diff(wantCmd, s.gotCmd); diff != "" {
+		t.Errorf("CPP command had diff (-want +got): %s", diff)
+	}
+}
+
+func TestComputeSpecUnsupportedFlags(t *testing.T) {
+	ctx := context.Background()
+	s := &stubCPPDepScanner{
+		res:          []string{"foo.h"},
+		capabilities: &spb.CapabilitiesResponse{},
+	}
+	er, cleanup := execroot.Setup(t, []string{"bin/gcc"})
+	t.Cleanup(cleanup)
+	p := Preprocessor{
+		Preprocessor: &cppcompile.Preprocessor{
+			BasePreprocessor: &inputprocessor.BasePreprocessor{Ctx: ctx},
+			CPPDepScanner:    s,
+		},
+	}
+	p.Options = inputprocessor.Options{
+		ExecRoot:   er,
+		WorkingDir: "out",
+		Cmd: []string{
+			filepath.Join(er, "bin/gcc"),
+			"-imultiarch", "x86_64-linux-gnu",
+			"-c",
+			"test.cpp",
+			"-o", "test.o",
+		},
+	}
+	if err := p.ParseFlags(); err != nil {
+		t.Fatalf("ParseFlags() failed: %v", err)
+	}
+	if err := p.ComputeSpec(); err == nil {
+		t.Errorf("ComputeSpec() succeeded, want error for unsupported flags")
+	}
+	spec, _ := p.Spec()
+	if !spec.UsedShallowMode {
+		t.Errorf("ComputeSpec() did not use shallow mode")
+	}
+	if s.processCalls != 0 {
+		t.Errorf("Dependency scanner called %v times, want 0", s.processCalls)
+	}
+}
+
+type stubCPPDepScanner struct {
+	gotCmd []string
+
+	res []string
+	err error
+
+	capabilities *spb.CapabilitiesResponse
+
+	processCalls int
+}
+
+func (s *stubCPPDepScanner) ProcessInputs(_ context.Context, _ string, command []string, _, _ string, _ []string) ([]string, bool, error) {
+	s.gotCmd = command
+	s.processCalls++
+	return s.res, false, s.err
+}
+
+func (s *stubCPPDepScanner) Capabilities() *spb.CapabilitiesResponse {
+	return s.capabilities
+}
+
+type stubExecutor struct {
+	gotCmd *command.Command
+
+	outStr string
+	errStr string
+	err    error
+}
+
+func (s *stubExecutor) Execute(ctx context.Context, cmd *command.Command) (string, string, error) {
+	s.gotCmd = cmd
+	return s.outStr, s.errStr, s.err
+}
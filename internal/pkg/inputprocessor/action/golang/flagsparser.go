@@ -0,0 +1,261 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/args"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/flags"
+)
+
+// valueFlags are the flags of go tool compile and go tool link that take a value.
+var valueFlags = []string{
+	"-B", "-D", "-E", "-H", "-I", "-L", "-R", "-T", "-X",
+	"-asmhdr", "-buildid", "-buildmode", "-c", "-coveragecfg", "-embedcfg", "-extar", "-extld",
+	"-extldflags", "-goversion", "-importcfg", "-installsuffix", "-lang", "-libgcc", "-linkmode",
+	"-linkobj", "-o", "-p", "-pgoprofile", "-pluginpath", "-r", "-symabis", "-tmpdir", "-trimpath",
+}
+
+// embedCfg is the JSON configuration passed to go tool compile with -embedcfg.
+type embedCfg struct {
+	// Patterns maps each //go:embed pattern to the file names it matched.
+	Patterns map[string][]string
+	// Files maps each matched file name to the path of the file to embed.
+	Files map[string]string
+}
+
+// parseFlags is used to transform a go tool compile or go tool link command into a
+// CommandFlags structure. Also returns the path of the tool binary run by the go command, if
+// the command is run through "go tool" and the path is known.
+func parseFlags(ctx context.Context, command []string, workingDir, execRoot string) (*flags.CommandFlags, string, error) {
+	numArgs := len(command)
+	if numArgs < 2 {
+		return nil, "", fmt.Errorf("insufficient number of arguments in command: %v", command)
+	}
+
+	res := &flags.CommandFlags{
+		ExecutablePath:   command[0],
+		WorkingDirectory: workingDir,
+		ExecRoot:         execRoot,
+	}
+	cmdArgs := command[1:]
+	var tool string
+	// The tools may be invoked through the go command, as in "go tool compile".
+	if cmdArgs[0] == "tool" {
+		if numArgs < 3 {
+			return nil, "", fmt.Errorf("insufficient number of arguments in command: %v", command)
+		}
+		res.Flags = append(res.Flags, &flags.Flag{Value: cmdArgs[0]}, &flags.Flag{Value: cmdArgs[1]})
+		tool = toolPath(command[0], cmdArgs[1])
+		cmdArgs = cmdArgs[2:]
+	}
+	s := args.Scanner{
+		Args:       cmdArgs,
+		Flags:      map[string]int{},
+		Normalized: map[string]string{},
+	}
+	// Flags can also be passed in the form -flag=value.
+	for _, f := range valueFlags {
+		s.Flags[f] = 1
+		s.Joined = append(s.Joined, args.PrefixOption{Prefix: f + "="})
+		s.Normalized[f+"="] = f
+	}
+	sort.Slice(s.Joined, func(i, j int) bool { return s.Joined[i].Prefix > s.Joined[j].Prefix })
+	for s.HasNext() {
+		if err := handleGoFlags(res, &s); err != nil {
+			return nil, "", err
+		}
+	}
+	return res, tool, nil
+}
+
+// toolPath returns the path of the given tool run by the go command at goPath. The tools are in
+// the pkg/tool/<GOOS>_<GOARCH> directory of the GOROOT, whose bin directory holds the go
+// command. Returns an empty path if the go command is not in a GOROOT bin directory.
+func toolPath(goPath, tool string) string {
+	bin := filepath.Dir(goPath)
+	if filepath.Base(bin) != "bin" {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		tool += ".exe"
+	}
+	return filepath.Join(filepath.Dir(bin), "pkg", "tool", runtime.GOOS+"_"+runtime.GOARCH, tool)
+}
+
+func handleGoFlags(cmdFlags *flags.CommandFlags, scanner *args.Scanner) error {
+	nextRes := scanner.ReadNextFlag()
+	// A temporary CommandFlags structure is used to collect the dependencies and outputs of the
+	// flag, so that the flags within an argument file are not passed along, only the argument
+	// file itself.
+	f := &flags.CommandFlags{
+		ExecRoot:         cmdFlags.ExecRoot,
+		WorkingDirectory: cmdFlags.WorkingDirectory,
+	}
+	if strings.HasPrefix(nextRes.Args[0], "@") {
+		argFile := nextRes.Args[0][1:]
+		cmdFlags.Dependencies = append(cmdFlags.Dependencies, argFile)
+		cmdFlags.Flags = append(cmdFlags.Flags, &flags.Flag{Value: nextRes.Args[0]})
+		fileArgs, err := readArgFile(absPath(cmdFlags, argFile))
+		if err != nil {
+			return err
+		}
+		sc := &args.Scanner{
+			Args:       fileArgs,
+			Flags:      scanner.Flags,
+			Joined:     scanner.Joined,
+			Normalized: scanner.Normalized,
+		}
+		for sc.HasNext() {
+			sc.ReadNextFlag()
+			if err := handleGoArg(f, sc); err != nil {
+				return err
+			}
+		}
+	} else {
+		if err := handleGoArg(f, scanner); err != nil {
+			return err
+		}
+		cmdFlags.Flags = append(cmdFlags.Flags, f.Flags...)
+	}
+	cmdFlags.TargetFilePaths = append(cmdFlags.TargetFilePaths, f.TargetFilePaths...)
+	cmdFlags.Dependencies = append(cmdFlags.Dependencies, f.Dependencies...)
+	cmdFlags.OutputFilePaths = append(cmdFlags.OutputFilePaths, f.OutputFilePaths...)
+	return nil
+}
+
+// handleGoArg records the dependencies and outputs indicated by the current flag of the
+// scanner.
+func handleGoArg(f *flags.CommandFlags, sc *args.Scanner) error {
+	curr := sc.CurResult
+	flag, args, values := curr.NormalizedKey, curr.Args, curr.Values
+	switch flag {
+	case "-importcfg":
+		f.Dependencies = append(f.Dependencies, values[0])
+		deps, err := parseImportCfg(absPath(f, values[0]))
+		if err != nil {
+			return err
+		}
+		f.Dependencies = append(f.Dependencies, deps...)
+	case "-embedcfg":
+		f.Dependencies = append(f.Dependencies, values[0])
+		deps, err := parseEmbedCfg(absPath(f, values[0]))
+		if err != nil {
+			return err
+		}
+		f.Dependencies = append(f.Dependencies, deps...)
+	case "-symabis", "-pgoprofile", "-coveragecfg", "-L":
+		f.Dependencies = append(f.Dependencies, values[0])
+	case "-o", "-linkobj", "-asmhdr":
+		f.OutputFilePaths = append(f.OutputFilePaths, values[0])
+	case "":
+		f.TargetFilePaths = append(f.TargetFilePaths, args[0])
+	}
+	for _, arg := range args {
+		f.Flags = append(f.Flags, &flags.Flag{Value: arg})
+	}
+	return nil
+}
+
+// parseImportCfg returns the package files referenced by the importcfg file at the given path.
+func parseImportCfg(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var deps []string
+	for _, l := range strings.Split(string(data), "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		verb, arg, _ := strings.Cut(l, " ")
+		switch verb {
+		case "packagefile", "packageshlib":
+			// The argument is of the form importpath=file.
+			if _, file, ok := strings.Cut(arg, "="); ok && file != "" {
+				deps = append(deps, strings.TrimSpace(file))
+			}
+		}
+	}
+	return deps, nil
+}
+
+// parseEmbedCfg returns the files matched by the embed patterns of the embedcfg file at the
+// given path.
+func parseEmbedCfg(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &embedCfg{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse embedcfg %v: %v", path, err)
+	}
+	var names []string
+	for _, ns := range cfg.Patterns {
+		names = append(names, ns...)
+	}
+	sort.Strings(names)
+	var deps []string
+	for _, n := range names {
+		if file, ok := cfg.Files[n]; ok {
+			deps = append(deps, file)
+		}
+	}
+	return deps, nil
+}
+
+// readArgFile reads an argument file, which holds a single argument per line. Lines starting
+// with a double quote are Go quoted strings.
+func readArgFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for _, l := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if l == "" {
+			continue
+		}
+		if strings.HasPrefix(l, `"`) {
+			u, err := strconv.Unquote(l)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted argument %v in %v: %v", l, path, err)
+			}
+			l = u
+		}
+		res = append(res, l)
+	}
+	return res, nil
+}
+
+// absPath returns the given path, relative to the working directory of the command, as an
+// absolute path.
+func absPath(f *flags.CommandFlags, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(f.ExecRoot, f.WorkingDirectory, path)
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/bazelbuild/reclient/internal/pkg/execroot"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/flags"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestGoParser(t *testing.T) {
+	er, cleanup := execroot.Setup(t, nil)
+	defer cleanup()
+	exe := ""
+	if runtime.GOOS == "windows" {
+		exe = ".exe"
+	}
+	tests := []struct {
+		name          string
+		cmd           []string
+		existingFiles map[string][]byte
+		want          *flags.CommandFlags
+		wantTool      string
+	}{
+		{
+			name: "go tool compile with joined values",
+			cmd:  []string{"goroot/bin/go", "tool", "compile", "-o=out/foo.a", "-p=example.com/foo", "-importcfg=foo.importcfg", "-pack", "foo.go"},
+			existingFiles: map[string][]byte{
+				"foo.importcfg": []byte("packagefile fmt=pkg/fmt.a\npackageshlib example.com/shared=lib/libshared.so\n"),
+			},
+			want: &flags.CommandFlags{
+				ExecutablePath:  "goroot/bin/go",
+				TargetFilePaths: []string{"foo.go"},
+				Dependencies:    []string{"foo.importcfg", "pkg/fmt.a", "lib/libshared.so"},
+				OutputFilePaths: []string{"out/foo.a"},
+				ExecRoot:        er,
+				Flags: []*flags.Flag{
+					{Value: "tool"}, {Value: "compile"},
+					{Value: "-o=out/foo.a"},
+					{Value: "-p=example.com/foo"},
+					{Value: "-importcfg=foo.importcfg"},
+					{Value: "-pack"},
+					{Value: "foo.go"},
+				},
+			},
+			wantTool: filepath.Join("goroot", "pkg", "tool", runtime.GOOS+"_"+runtime.GOARCH, "compile"+exe),
+		},
+		{
+			name: "link with quoted argfile lines",
+			cmd:  []string{"link", "@link.args"},
+			existingFiles: map[string][]byte{
+				"link.args": []byte("-o\n\"out/my bin\"\n-L\n\"lib dir\"\n-X=main.version=1.0\n\"obj/main.a\"\n"),
+			},
+			want: &flags.CommandFlags{
+				ExecutablePath:  "link",
+				TargetFilePaths: []string{"obj/main.a"},
+				Dependencies:    []string{"link.args", "lib dir"},
+				OutputFilePaths: []string{"out/my bin"},
+				ExecRoot:        er,
+				Flags:           []*flags.Flag{{Value: "@link.args"}},
+			},
+		},
+		{
+			name: "go tool link with go from the PATH",
+			cmd:  []string{"go", "tool", "link", "-o", "bin/main", "-buildmode", "pie", "main.a"},
+			want: &flags.CommandFlags{
+				ExecutablePath:  "go",
+				TargetFilePaths: []string{"main.a"},
+				OutputFilePaths: []string{"bin/main"},
+				ExecRoot:        er,
+				Flags: []*flags.Flag{
+					{Value: "tool"}, {Value: "link"},
+					{Value: "-o"}, {Value: "bin/main"},
+					{Value: "-buildmode"}, {Value: "pie"},
+					{Value: "main.a"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			execroot.AddFilesWithContent(t, er, test.existingFiles)
+			got, gotTool, err := parseFlags(context.Background(), test.cmd, "", er)
+			if err != nil {
+				t.Fatalf("parseFlags(%v) returned error: %v", test.cmd, err)
+			}
+			if diff := cmp.Diff(test.want, got, cmpopts.IgnoreUnexported(flags.Flag{})); diff != "" {
+				t.Errorf("parseFlags(%v) returned diff in flags, (-want +got): %s", test.cmd, diff)
+			}
+			if gotTool != test.wantTool {
+				t.Errorf("parseFlags(%v) returned tool %q, want %q", test.cmd, gotTool, test.wantTool)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package golang performs input processing given a valid go tool compile or go tool link
+// action.
+package golang
+
+import (
+	"fmt"
+
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor"
+	"github.com/bazelbuild/reclient/internal/pkg/pathtranslator"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+)
+
+// Preprocessor is the preprocessor of go compile and link actions.
+type Preprocessor struct {
+	*inputprocessor.BasePreprocessor
+	// tool is the compile or link binary run by the go command, if the command is run through
+	// "go tool" and the GOROOT of the go command is known.
+	tool string
+}
+
+// ParseFlags parses the commands flags and populates the ActionSpec object with inferred
+// information.
+func (p *Preprocessor) ParseFlags() error {
+	f, tool, err := parseFlags(p.Ctx, p.Options.Cmd, p.Options.WorkingDir, p.Options.ExecRoot)
+	if err != nil {
+		p.Err = fmt.Errorf("flag parsing failed. %v", err)
+		return p.Err
+	}
+	p.Flags = f
+	p.tool = tool
+	p.FlagsToActionSpec()
+	return nil
+}
+
+// ProcessToolchains determines toolchain inputs required for the command, including the
+// compile or link binary run by the go command.
+func (p *Preprocessor) ProcessToolchains() error {
+	if err := p.BasePreprocessor.ProcessToolchains(); err != nil {
+		return err
+	}
+	if p.tool == "" {
+		return nil
+	}
+	p.AppendSpec(&inputprocessor.ActionSpec{
+		InputSpec: &command.InputSpec{
+			Inputs: pathtranslator.ListRelToExecRoot(p.Options.ExecRoot, p.Options.WorkingDir, []string{p.tool}),
+		},
+	})
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package golang
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/bazelbuild/reclient/internal/pkg/execroot"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const (
+	wd = "wd"
+)
+
+var (
+	strSliceCmp = cmpopts.SortSlices(func(a, b string) bool { return a < b })
+)
+
+func TestGoCompilePreprocessor(t *testing.T) {
+	er, cleanup := execroot.Setup(t, []string{
+		"go",
+		"wd/foo/foo.go",
+		"wd/foo/bar.go",
+		"wd/foo/asm.s",
+		"wd/foo/static/index.html",
+		"wd/foo/static/style.css",
+		"wd/foo/unused.txt",
+		"wd/obj/fmt.a",
+		"wd/obj/example.com/baz.a",
+		"wd/obj/symabis",
+	})
+	defer cleanup()
+	execroot.AddFileWithContent(t, filepath.Join(er, wd, "obj/foo.importcfg"), []byte(
+		"# import config\n"+
+			"importmap example.com/old=example.com/baz\n"+
+			"packagefile fmt=obj/fmt.a\n"+
+			"packagefile example.com/baz="+filepath.Join(er, wd, "obj/example.com/baz.a")+"\n"))
+	execroot.AddFileWithContent(t, filepath.Join(er, wd, "obj/foo.embedcfg"), []byte(`{
+  "Patterns": {"static/*": ["static/index.html", "static/style.css"]},
+  "Files": {
+    "static/index.html": "foo/static/index.html",
+    "static/style.css": "foo/static/style.css"
+  }
+}`))
+	execroot.AddFileWithContent(t, filepath.Join(er, wd, "obj/foo.args"), []byte("-embedcfg\nobj/foo.embedcfg\nfoo/foo.go\n\"foo/bar.go\"\n"))
+	cmd := []string{"../go", "tool", "compile", "-o", "obj/foo.a", "-p", "example.com/foo", "-trimpath", "$WORK=>", "-importcfg=obj/foo.importcfg", "-symabis", "obj/symabis", "-asmhdr", "obj/go_asm.h", "-pack", "@obj/foo.args"}
+	pp := &Preprocessor{
+		BasePreprocessor: &inputprocessor.BasePreprocessor{
+			Ctx: context.Background(),
+		},
+	}
+	gotSpec, err := inputprocessor.Compute(pp, inputprocessor.Options{
+		Cmd:        cmd,
+		WorkingDir: wd,
+		ExecRoot:   er,
+	})
+	if err != nil {
+		t.Fatalf("Compute() returned error: %v", err)
+	}
+	wantSpec := &inputprocessor.ActionSpec{
+		InputSpec: &command.InputSpec{
+			Inputs: []string{
+				"go",
+				"wd/foo/foo.go",
+				"wd/foo/bar.go",
+				"wd/foo/static/index.html",
+				"wd/foo/static/style.css",
+				"wd/obj/foo.args",
+				"wd/obj/foo.embedcfg",
+				"wd/obj/foo.importcfg",
+				"wd/obj/fmt.a",
+				"wd/obj/example.com/baz.a",
+				"wd/obj/symabis",
+			},
+		},
+		OutputFiles: []string{"wd/obj/foo.a", "wd/obj/go_asm.h"},
+	}
+	if diff := cmp.Diff(wantSpec, gotSpec, strSliceCmp); diff != "" {
+		t.Errorf("Compute() returned diff in ActionSpec, (-want +got): %s", diff)
+	}
+}
+
+func TestGoLinkPreprocessor(t *testing.T) {
+	er, cleanup := execroot.Setup(t, []string{
+		"link",
+		"wd/obj/main.a",
+		"wd/obj/runtime.a",
+		"wd/obj/example.com/foo.a",
+	})
+	defer cleanup()
+	execroot.AddFileWithContent(t, filepath.Join(er, wd, "obj/importcfg.link"), []byte(
+		"packagefile example.com/cmd/main=obj/main.a\n"+
+			"packagefile runtime=obj/runtime.a\n"+
+			"packagefile example.com/foo=obj/example.com/foo.a\n"+
+			"modinfo \"0w\\xaf\\f\\x92t\\b\\x02A\\x16\\x18\\x05\\n\"\n"))
+	cmd := []string{"../link", "-o=bin/main", "-importcfg", "obj/importcfg.link", "-buildmode=exe", "-X", "main.version=1.0", "-s", "-w", "obj/main.a"}
+	pp := &Preprocessor{
+		BasePreprocessor: &inputprocessor.BasePreprocessor{
+			Ctx: context.Background(),
+		},
+	}
+	gotSpec, err := inputprocessor.Compute(pp, inputprocessor.Options{
+		Cmd:        cmd,
+		WorkingDir: wd,
+		ExecRoot:   er,
+	})
+	if err != nil {
+		t.Fatalf("Compute() returned error: %v", err)
+	}
+	wantSpec := &inputprocessor.ActionSpec{
+		InputSpec: &command.InputSpec{
+			Inputs: []string{
+				"link",
+				"wd/obj/importcfg.link",
+				"wd/obj/main.a",
+				"wd/obj/runtime.a",
+				"wd/obj/example.com/foo.a",
+			},
+		},
+		OutputFiles: []string{"wd/bin/main"},
+	}
+	if diff := cmp.Diff(wantSpec, gotSpec, strSliceCmp); diff != "" {
+		t.Errorf("Compute() returned diff in ActionSpec, (-want +got): %s", diff)
+	}
+}
+
+func TestGoToolPreprocessor(t *testing.T) {
+	exe := ""
+	if runtime.GOOS == "windows" {
+		exe = ".exe"
+	}
+	tool := filepath.Join("goroot", "pkg", "tool", runtime.GOOS+"_"+runtime.GOARCH, "compile"+exe)
+	er, cleanup := execroot.Setup(t, []string{
+		"goroot/bin/go",
+		tool,
+		"wd/foo.go",
+	})
+	defer cleanup()
+	cmd := []string{"../goroot/bin/go", "tool", "compile", "-o", "foo.a", "-p", "main", "foo.go"}
+	pp := &Preprocessor{
+		BasePreprocessor: &inputprocessor.BasePreprocessor{
+			Ctx: context.Background(),
+		},
+	}
+	gotSpec, err := inputprocessor.Compute(pp, inputprocessor.Options{
+		Cmd:        cmd,
+		WorkingDir: wd,
+		ExecRoot:   er,
+	})
+	if err != nil {
+		t.Fatalf("Compute() returned error: %v", err)
+	}
+	wantSpec := &inputprocessor.ActionSpec{
+		InputSpec: &command.InputSpec{
+			Inputs: []string{
+				filepath.Clean("goroot/bin/go"),
+				tool,
+				filepath.Clean("wd/foo.go"),
+			},
+		},
+		OutputFiles: []string{filepath.Clean("wd/foo.a")},
+	}
+	if diff := cmp.Diff(wantSpec, gotSpec, strSliceCmp); diff != "" {
+		t.Errorf("Compute() returned diff in ActionSpec, (-want +got): %s", diff)
+	}
+}
@@ -76,14 +76,7 @@ func handleJavacFlags(cmdFlags *flags.CommandFlags, scanner *args.Scanner) error
 		flag, args, values := curr.NormalizedKey, curr.Args, curr.Values
 		switch flag {
 		case "-bootclasspath", "-classpath", "-processorpath":
-			deps := strings.Split(values[0], ":")
-			for _, d := range deps {
-				// Exclude empty strings and . strings from dependencies.
-				if d == "" || d == "." {
-					continue
-				}
-				f.Dependencies = append(f.Dependencies, d)
-			}
+			f.Dependencies = append(f.Dependencies, ClasspathDependencies(values[0])...)
 		case "--system=", "-Aroom.schemaLocation=":
 			f.Dependencies = append(f.Dependencies, values[0])
 		case "-d", "-s":
@@ -122,3 +115,16 @@ func handleJavacFlags(cmdFlags *flags.CommandFlags, scanner *args.Scanner) error
 	cmdFlags.OutputFilePaths = append(cmdFlags.OutputFilePaths, f.OutputFilePaths...)
 	return nil
 }
+
+// ClasspathDependencies returns the dependencies listed in the given colon separated classpath.
+func ClasspathDependencies(classpath string) []string {
+	var deps []string
+	for _, d := range strings.Split(classpath, ":") {
+		// Exclude empty strings and . strings from dependencies.
+		if d == "" || d == "." {
+			continue
+		}
+		deps = append(deps, d)
+	}
+	return deps
+}
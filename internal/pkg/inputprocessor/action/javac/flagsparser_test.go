@@ -0,0 +1,51 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package javac
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClasspathDependencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		classpath string
+		want      []string
+	}{
+		{
+			name:      "jars and directories",
+			classpath: "a.jar:lib/b.jar:classes",
+			want:      []string{"a.jar", "lib/b.jar", "classes"},
+		},
+		{
+			name:      "empty and current directory entries",
+			classpath: ":a.jar::.:b.jar:",
+			want:      []string{"a.jar", "b.jar"},
+		},
+		{
+			name:      "empty classpath",
+			classpath: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(test.want, ClasspathDependencies(test.classpath)); diff != "" {
+				t.Errorf("ClasspathDependencies(%q) returned diff, (-want +got): %s", test.classpath, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kotlinc
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/javac"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/args"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/flags"
+	"github.com/bazelbuild/reclient/internal/pkg/rsp"
+)
+
+// modules is the root element of a -Xbuild-file XML module file.
+type modules struct {
+	Modules []module `xml:"module"`
+}
+
+// module is a single module of a -Xbuild-file XML module file.
+type module struct {
+	OutputDir       string       `xml:"outputDir,attr"`
+	Sources         []modulePath `xml:"sources"`
+	CommonSources   []modulePath `xml:"commonSources"`
+	JavaSourceRoots []modulePath `xml:"javaSourceRoots"`
+	Classpath       []modulePath `xml:"classpath"`
+	FriendDirs      []modulePath `xml:"friendDir"`
+}
+
+// modulePath is an element of a module that references a path.
+type modulePath struct {
+	Path string `xml:"path,attr"`
+}
+
+// parseFlags is used to transform a kotlinc command into a CommandFlags structure. It also
+// returns the JDK and Kotlin home directories referenced by the command, which are part of the
+// toolchain of the action.
+func parseFlags(ctx context.Context, command []string, workingDir, execRoot string) (*flags.CommandFlags, []string, error) {
+	numArgs := len(command)
+	if numArgs < 2 {
+		return nil, nil, fmt.Errorf("insufficient number of arguments in command: %v", command)
+	}
+
+	res := &flags.CommandFlags{
+		ExecutablePath:   command[0],
+		WorkingDirectory: workingDir,
+		ExecRoot:         execRoot,
+	}
+	var homes []string
+	s := args.Scanner{
+		Args: command[1:],
+		Flags: map[string]int{
+			"-classpath":        1,
+			"-cp":               1,
+			"-d":                1,
+			"-jdk-home":         1,
+			"-kotlin-home":      1,
+			"-module-name":      1,
+			"-jvm-target":       1,
+			"-api-version":      1,
+			"-language-version": 1,
+			"-opt-in":           1,
+			"-P":                1,
+			"-script-templates": 1,
+			"-Xbuild-file":      1,
+		},
+		Joined: []args.PrefixOption{
+			{Prefix: "-Xplugin="},
+			{Prefix: "-Xjava-source-roots="},
+			{Prefix: "-Xfriend-paths="},
+			{Prefix: "-Xbuild-file="},
+		},
+		Normalized: map[string]string{
+			"-cp":           "-classpath",
+			"-Xbuild-file=": "-Xbuild-file",
+		},
+	}
+	for s.HasNext() {
+		if err := handleKotlincFlags(res, &homes, &s); err != nil {
+			return nil, nil, err
+		}
+	}
+	return res, homes, nil
+}
+
+func handleKotlincFlags(cmdFlags *flags.CommandFlags, homes *[]string, scanner *args.Scanner) error {
+	nextRes := scanner.ReadNextFlag()
+	// A temporary CommandFlags structure is used to collect the dependencies and outputs, so
+	// that the flags within a rsp file are not passed along, only the rsp file itself.
+	f := &flags.CommandFlags{
+		ExecRoot:         cmdFlags.ExecRoot,
+		WorkingDirectory: cmdFlags.WorkingDirectory,
+	}
+	handleArgFunc := func(sc *args.Scanner) error {
+		curr := sc.CurResult
+		flag, args, values := curr.NormalizedKey, curr.Args, curr.Values
+		switch flag {
+		case "-classpath":
+			f.Dependencies = append(f.Dependencies, javac.ClasspathDependencies(values[0])...)
+		case "-Xfriend-paths=", "-Xplugin=", "-Xjava-source-roots=":
+			for _, d := range strings.Split(values[0], ",") {
+				if d != "" {
+					f.Dependencies = append(f.Dependencies, d)
+				}
+			}
+		case "-Xbuild-file":
+			if err := handleBuildFile(f, values[0]); err != nil {
+				return err
+			}
+		case "-d":
+			if strings.HasSuffix(values[0], ".jar") {
+				f.OutputFilePaths = append(f.OutputFilePaths, values[0])
+			} else {
+				f.OutputDirPaths = append(f.OutputDirPaths, values[0])
+			}
+		case "-jdk-home", "-kotlin-home":
+			*homes = append(*homes, values[0])
+		case "":
+			f.TargetFilePaths = append(f.TargetFilePaths, args[0])
+		}
+		for _, arg := range args {
+			f.Flags = append(f.Flags, &flags.Flag{Value: arg})
+		}
+		return nil
+	}
+	// Check if this is a rsp file that needs processing or just a normal flag.
+	if strings.HasPrefix(nextRes.Args[0], "@") {
+		rspFile := nextRes.Args[0][1:]
+		cmdFlags.Dependencies = append(cmdFlags.Dependencies, rspFile)
+		if !filepath.IsAbs(rspFile) {
+			rspFile = filepath.Join(cmdFlags.ExecRoot, cmdFlags.WorkingDirectory, rspFile)
+		}
+		cmdFlags.Flags = append(cmdFlags.Flags, &flags.Flag{Value: nextRes.Args[0]})
+		if err := rsp.ParseWithFunc(rspFile, *scanner, handleArgFunc); err != nil {
+			return err
+		}
+	} else {
+		if err := handleArgFunc(scanner); err != nil {
+			return err
+		}
+		cmdFlags.Flags = append(cmdFlags.Flags, f.Flags...)
+	}
+	cmdFlags.TargetFilePaths = append(cmdFlags.TargetFilePaths, f.TargetFilePaths...)
+	cmdFlags.Dependencies = append(cmdFlags.Dependencies, f.Dependencies...)
+	cmdFlags.OutputDirPaths = append(cmdFlags.OutputDirPaths, f.OutputDirPaths...)
+	cmdFlags.OutputFilePaths = append(cmdFlags.OutputFilePaths, f.OutputFilePaths...)
+	return nil
+}
+
+// handleBuildFile adds the sources, classpath and output directories of all modules in the
+// given XML module file. Relative paths in the module file are relative to its directory, as
+// they are for kotlinc.
+func handleBuildFile(f *flags.CommandFlags, buildFile string) error {
+	f.Dependencies = append(f.Dependencies, buildFile)
+	path := buildFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(f.ExecRoot, f.WorkingDirectory, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	ms := &modules{}
+	if err := xml.Unmarshal(data, ms); err != nil {
+		return fmt.Errorf("failed to parse build file %v: %v", buildFile, err)
+	}
+	dir := filepath.Dir(buildFile)
+	rel := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+	for _, m := range ms.Modules {
+		for _, p := range m.Sources {
+			f.TargetFilePaths = append(f.TargetFilePaths, rel(p.Path))
+		}
+		var deps []modulePath
+		deps = append(deps, m.CommonSources...)
+		deps = append(deps, m.JavaSourceRoots...)
+		deps = append(deps, m.Classpath...)
+		deps = append(deps, m.FriendDirs...)
+		for _, p := range deps {
+			f.Dependencies = append(f.Dependencies, rel(p.Path))
+		}
+		if m.OutputDir != "" {
+			f.OutputDirPaths = append(f.OutputDirPaths, rel(m.OutputDir))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kotlinc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bazelbuild/reclient/internal/pkg/execroot"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/flags"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestKotlincParser(t *testing.T) {
+	er, cleanup := execroot.Setup(t, nil)
+	defer cleanup()
+	tests := []struct {
+		name          string
+		cmd           []string
+		existingFiles map[string][]byte
+		want          *flags.CommandFlags
+		wantHomes     []string
+	}{
+		{
+			name: "short classpath and output directory",
+			cmd:  []string{"kotlinc", "-cp", "a.jar:.:lib/classes", "-Xfriend-paths=friend.jar,friend/classes", "-d", "out/classes", "-jdk-home", "jdk", "Foo.kt"},
+			want: &flags.CommandFlags{
+				ExecutablePath:  "kotlinc",
+				TargetFilePaths: []string{"Foo.kt"},
+				Dependencies:    []string{"a.jar", "lib/classes", "friend.jar", "friend/classes"},
+				OutputDirPaths:  []string{"out/classes"},
+				ExecRoot:        er,
+				Flags: []*flags.Flag{
+					{Value: "-cp"}, {Value: "a.jar:.:lib/classes"},
+					{Value: "-Xfriend-paths=friend.jar,friend/classes"},
+					{Value: "-d"}, {Value: "out/classes"},
+					{Value: "-jdk-home"}, {Value: "jdk"},
+					{Value: "Foo.kt"},
+				},
+			},
+			wantHomes: []string{"jdk"},
+		},
+		{
+			name: "rsp file with output jar",
+			cmd:  []string{"kotlinc", "@args.rsp"},
+			existingFiles: map[string][]byte{
+				"args.rsp": []byte("-cp a.jar -Xfriend-paths=friend.jar -d out/foo.jar Foo.kt"),
+			},
+			want: &flags.CommandFlags{
+				ExecutablePath:  "kotlinc",
+				TargetFilePaths: []string{"Foo.kt"},
+				Dependencies:    []string{"args.rsp", "a.jar", "friend.jar"},
+				OutputFilePaths: []string{"out/foo.jar"},
+				ExecRoot:        er,
+				Flags:           []*flags.Flag{{Value: "@args.rsp"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			execroot.AddFilesWithContent(t, er, test.existingFiles)
+			got, gotHomes, err := parseFlags(context.Background(), test.cmd, "", er)
+			if err != nil {
+				t.Fatalf("parseFlags(%v) returned error: %v", test.cmd, err)
+			}
+			if diff := cmp.Diff(test.want, got, cmpopts.IgnoreUnexported(flags.Flag{})); diff != "" {
+				t.Errorf("parseFlags(%v) returned diff in flags, (-want +got): %s", test.cmd, diff)
+			}
+			if diff := cmp.Diff(test.wantHomes, gotHomes); diff != "" {
+				t.Errorf("parseFlags(%v) returned diff in homes, (-want +got): %s", test.cmd, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kotlinc performs input processing given a valid kotlinc action.
+package kotlinc
+
+import (
+	"fmt"
+
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor"
+	"github.com/bazelbuild/reclient/internal/pkg/pathtranslator"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+)
+
+// Preprocessor is the preprocessor of kotlin compile actions.
+type Preprocessor struct {
+	*inputprocessor.BasePreprocessor
+	// homes are the JDK and Kotlin home directories referenced by the command.
+	homes []string
+}
+
+// ParseFlags parses the commands flags and populates the ActionSpec object with inferred
+// information.
+func (p *Preprocessor) ParseFlags() error {
+	f, homes, err := parseFlags(p.Ctx, p.Options.Cmd, p.Options.WorkingDir, p.Options.ExecRoot)
+	if err != nil {
+		p.Err = fmt.Errorf("flag parsing failed. %v", err)
+		return p.Err
+	}
+	p.Flags = f
+	p.homes = homes
+	p.FlagsToActionSpec()
+	return nil
+}
+
+// ProcessToolchains determines toolchain inputs required for the command, including the JDK
+// and Kotlin home directories.
+func (p *Preprocessor) ProcessToolchains() error {
+	if err := p.BasePreprocessor.ProcessToolchains(); err != nil {
+		return err
+	}
+	p.AppendSpec(&inputprocessor.ActionSpec{
+		InputSpec: &command.InputSpec{
+			Inputs: pathtranslator.ListRelToExecRoot(p.Options.ExecRoot, p.Options.WorkingDir, p.homes),
+		},
+	})
+	return nil
+}
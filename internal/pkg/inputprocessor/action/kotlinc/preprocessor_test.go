@@ -0,0 +1,105 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kotlinc
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/bazelbuild/reclient/internal/pkg/execroot"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+var (
+	strSliceCmp = cmpopts.SortSlices(func(a, b string) bool { return a < b })
+)
+
+func TestKotlincPreprocessor(t *testing.T) {
+	er, cleanup := execroot.Setup(t, []string{"a.jar", "b.jar", "friend.jar", "plugin1.jar", "plugin2.jar", "Foo.kt", "Bar.kt", "jdk/bin/java", "kotlin/lib/kotlin-stdlib.jar", "kotlinc"})
+	defer cleanup()
+	execroot.AddFileWithContent(t, filepath.Join(er, "args.rsp"), []byte("-Xfriend-paths=friend.jar\nBar.kt"))
+	cmd := []string{"kotlinc", "-J-Xmx2048M", "-classpath", "a.jar:b.jar:.", "-Xplugin=plugin1.jar,plugin2.jar", "-jdk-home", "jdk", "-kotlin-home", "kotlin", "-jvm-target", "17", "-d", "out/foo.jar", "Foo.kt", "@args.rsp"}
+	pp := &Preprocessor{
+		BasePreprocessor: &inputprocessor.BasePreprocessor{
+			Ctx: context.Background(),
+		},
+	}
+	gotSpec, err := inputprocessor.Compute(pp, inputprocessor.Options{
+		ExecRoot: er,
+		Cmd:      cmd,
+	})
+	if err != nil {
+		t.Fatalf("Compute() returned error: %v", err)
+	}
+	wantSpec := &inputprocessor.ActionSpec{
+		InputSpec: &command.InputSpec{
+			Inputs: []string{"a.jar", "b.jar", "friend.jar", "plugin1.jar", "plugin2.jar", "Foo.kt", "Bar.kt", "args.rsp", "jdk", "kotlin", "kotlinc"},
+		},
+		OutputFiles: []string{filepath.Clean("out/foo.jar")},
+	}
+	if diff := cmp.Diff(wantSpec, gotSpec, strSliceCmp); diff != "" {
+		t.Errorf("Compute() returned diff in ActionSpec, (-want +got): %s", diff)
+	}
+}
+
+func TestKotlincBuildFilePreprocessor(t *testing.T) {
+	er, cleanup := execroot.Setup(t, []string{"wd/mod/src/Foo.kt", "wd/mod/src/Bar.kt", "wd/mod/java/Baz.java", "wd/lib/a.jar", "wd/friend/classes", "kotlinc-jvm"})
+	defer cleanup()
+	execroot.AddFileWithContent(t, filepath.Join(er, "wd/mod/build.xml"), []byte(`<modules>
+  <module name="foo" type="java-production" outputDir="out/classes">
+    <sources path="src/Foo.kt"/>
+    <sources path="src/Bar.kt"/>
+    <javaSourceRoots path="java"/>
+    <classpath path="../lib/a.jar"/>
+    <friendDir path="../friend/classes"/>
+  </module>
+</modules>`))
+	cmd := []string{"../kotlinc-jvm", "-Xbuild-file=mod/build.xml", "-module-name", "foo"}
+	pp := &Preprocessor{
+		BasePreprocessor: &inputprocessor.BasePreprocessor{
+			Ctx: context.Background(),
+		},
+	}
+	gotSpec, err := inputprocessor.Compute(pp, inputprocessor.Options{
+		ExecRoot:   er,
+		WorkingDir: "wd",
+		Cmd:        cmd,
+	})
+	if err != nil {
+		t.Fatalf("Compute() returned error: %v", err)
+	}
+	wantSpec := &inputprocessor.ActionSpec{
+		InputSpec: &command.InputSpec{
+			Inputs: []string{
+				filepath.Clean("wd/mod/build.xml"),
+				filepath.Clean("wd/mod/src/Foo.kt"),
+				filepath.Clean("wd/mod/src/Bar.kt"),
+				filepath.Clean("wd/mod/java"),
+				filepath.Clean("wd/lib/a.jar"),
+				filepath.Clean("wd/friend/classes"),
+				"kotlinc-jvm",
+			},
+		},
+		OutputDirectories: []string{filepath.Clean("wd/mod/out/classes")},
+	}
+	if diff := cmp.Diff(wantSpec, gotSpec, strSliceCmp); diff != "" {
+		t.Errorf("Compute() returned diff in ActionSpec, (-want +got): %s", diff)
+	}
+}
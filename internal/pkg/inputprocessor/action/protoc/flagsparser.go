@@ -0,0 +1,222 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/args"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/flags"
+	"github.com/bazelbuild/reclient/internal/pkg/rsp"
+)
+
+// archiveExts are the extensions of --*_out locations that are written as a single archive
+// instead of a directory.
+var archiveExts = []string{".zip", ".jar", ".srcjar"}
+
+// protoPath is a directory in which protoc looks for imports. Files under Dir are imported
+// with Prefix prepended to their path relative to Dir.
+type protoPath struct {
+	Prefix string
+	Dir    string
+}
+
+// protocState holds the protoc specific information collected while parsing a command that is
+// needed to compute its inputs.
+type protocState struct {
+	// protoPaths are the import search paths of the command, in order of precedence.
+	protoPaths []protoPath
+	// plugins are the code generator plugins used by the command.
+	plugins []string
+}
+
+// parseFlags is used to transform a protoc command into a CommandFlags structure. It also
+// returns the import search paths and plugins of the command.
+func parseFlags(ctx context.Context, command []string, workingDir, execRoot string) (*flags.CommandFlags, *protocState, error) {
+	numArgs := len(command)
+	if numArgs < 2 {
+		return nil, nil, fmt.Errorf("insufficient number of arguments in command: %v", command)
+	}
+
+	res := &flags.CommandFlags{
+		ExecutablePath:   command[0],
+		WorkingDirectory: workingDir,
+		ExecRoot:         execRoot,
+	}
+	st := &protocState{}
+	s := args.Scanner{
+		Args: command[1:],
+		Flags: map[string]int{
+			"-I":                   1,
+			"--proto_path":         1,
+			"-o":                   1,
+			"--descriptor_set_out": 1,
+			"--descriptor_set_in":  1,
+			"--dependency_out":     1,
+			"--plugin":             1,
+			"--error_format":       1,
+			"--encode":             1,
+			"--decode":             1,
+		},
+		Joined: []args.PrefixOption{
+			{Prefix: "-o"},
+			{Prefix: "-I"},
+			{Prefix: "--proto_path="},
+			{Prefix: "--plugin="},
+			{Prefix: "--descriptor_set_out="},
+			{Prefix: "--descriptor_set_in="},
+			{Prefix: "--dependency_out="},
+		},
+		Normalized: map[string]string{
+			"--proto_path":          "-I",
+			"--proto_path=":         "-I",
+			"--descriptor_set_out":  "-o",
+			"--descriptor_set_out=": "-o",
+			"--descriptor_set_in=":  "--descriptor_set_in",
+			"--dependency_out=":     "--dependency_out",
+			"--plugin=":             "--plugin",
+		},
+	}
+	for s.HasNext() {
+		if err := handleProtocFlags(res, st, &s); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(st.protoPaths) == 0 {
+		// Without any import search paths, protoc searches the working directory.
+		st.protoPaths = []protoPath{{Dir: "."}}
+	}
+	return res, st, nil
+}
+
+func handleProtocFlags(cmdFlags *flags.CommandFlags, st *protocState, scanner *args.Scanner) error {
+	nextRes := scanner.ReadNextFlag()
+	// A temporary CommandFlags structure is used to collect the dependencies and outputs, so
+	// that the flags within a argument file are not passed along, only the file itself.
+	f := &flags.CommandFlags{
+		ExecRoot:         cmdFlags.ExecRoot,
+		WorkingDirectory: cmdFlags.WorkingDirectory,
+	}
+	handleArgFunc := func(sc *args.Scanner) error {
+		curr := sc.CurResult
+		flag, args, values := curr.NormalizedKey, curr.Args, curr.Values
+		switch {
+		case flag == "-I":
+			for _, p := range filepath.SplitList(values[0]) {
+				pp := protoPath{Dir: p}
+				// A search path can map a directory on disk to a virtual import prefix.
+				if prefix, dir, ok := strings.Cut(p, "="); ok {
+					pp = protoPath{Prefix: prefix, Dir: dir}
+				}
+				st.protoPaths = append(st.protoPaths, pp)
+				f.VirtualDirectories = append(f.VirtualDirectories, pp.Dir)
+			}
+		case flag == "--descriptor_set_in":
+			f.Dependencies = append(f.Dependencies, filepath.SplitList(values[0])...)
+		case flag == "-o", flag == "--dependency_out":
+			f.OutputFilePaths = append(f.OutputFilePaths, values[0])
+		case flag == "--plugin":
+			// The plugin is given either as a path or as protoc-gen-NAME=path.
+			path := values[0]
+			if _, p, ok := strings.Cut(path, "="); ok {
+				path = p
+			}
+			st.plugins = append(st.plugins, path)
+		case strings.HasPrefix(flag, "--") && strings.Contains(flag, "_out="):
+			_, out, _ := strings.Cut(flag, "=")
+			addGeneratorOutput(f, out)
+		case strings.HasPrefix(flag, "--") && (strings.HasSuffix(flag, "_out") || strings.HasSuffix(flag, "_opt")):
+			// Generator flags can't all be registered up front, so their value given as a separate
+			// argument, as in --cpp_out gen, is consumed here.
+			if !sc.HasNext() {
+				return fmt.Errorf("missing value for %v", flag)
+			}
+			args = append(args, sc.Args[0])
+			sc.Args = sc.Args[1:]
+			if strings.HasSuffix(flag, "_out") {
+				addGeneratorOutput(f, args[1])
+			}
+		case flag == "":
+			f.TargetFilePaths = append(f.TargetFilePaths, args[0])
+		}
+		for _, arg := range args {
+			f.Flags = append(f.Flags, &flags.Flag{Value: arg})
+		}
+		return nil
+	}
+	// Check if this is an argument file that needs processing or just a normal flag.
+	if strings.HasPrefix(nextRes.Args[0], "@") {
+		argFile := nextRes.Args[0][1:]
+		cmdFlags.Dependencies = append(cmdFlags.Dependencies, argFile)
+		if !filepath.IsAbs(argFile) {
+			argFile = filepath.Join(cmdFlags.ExecRoot, cmdFlags.WorkingDirectory, argFile)
+		}
+		cmdFlags.Flags = append(cmdFlags.Flags, &flags.Flag{Value: nextRes.Args[0]})
+		if err := rsp.ParseWithFunc(argFile, *scanner, handleArgFunc); err != nil {
+			return err
+		}
+	} else {
+		if err := handleArgFunc(scanner); err != nil {
+			return err
+		}
+		cmdFlags.Flags = append(cmdFlags.Flags, f.Flags...)
+	}
+	cmdFlags.TargetFilePaths = append(cmdFlags.TargetFilePaths, f.TargetFilePaths...)
+	cmdFlags.Dependencies = append(cmdFlags.Dependencies, f.Dependencies...)
+	cmdFlags.VirtualDirectories = append(cmdFlags.VirtualDirectories, f.VirtualDirectories...)
+	cmdFlags.OutputDirPaths = append(cmdFlags.OutputDirPaths, f.OutputDirPaths...)
+	cmdFlags.OutputFilePaths = append(cmdFlags.OutputFilePaths, f.OutputFilePaths...)
+	return nil
+}
+
+// addGeneratorOutput adds the location of a --*_out flag value to the outputs of f.
+func addGeneratorOutput(f *flags.CommandFlags, value string) {
+	out := outputLocation(value)
+	if isArchive(out) {
+		f.OutputFilePaths = append(f.OutputFilePaths, out)
+	} else {
+		f.OutputDirPaths = append(f.OutputDirPaths, out)
+	}
+}
+
+// outputLocation returns the location of a --*_out flag value, which can be prefixed with
+// generator parameters separated from the location by the last colon.
+func outputLocation(value string) string {
+	if isWindowsAbs(value) {
+		return value
+	}
+	if i := strings.LastIndex(value, ":"); i >= 0 {
+		return value[i+1:]
+	}
+	return value
+}
+
+// isWindowsAbs returns true if the given path starts with a windows drive letter.
+func isWindowsAbs(path string) bool {
+	return len(path) >= 3 && path[1] == ':' && (path[2] == '\\' || path[2] == '/') &&
+		((path[0] >= 'a' && path[0] <= 'z') || (path[0] >= 'A' && path[0] <= 'Z'))
+}
+
+func isArchive(path string) bool {
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
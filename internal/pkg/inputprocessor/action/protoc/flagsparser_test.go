@@ -0,0 +1,180 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bazelbuild/reclient/internal/pkg/execroot"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/flags"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestProtocParser(t *testing.T) {
+	er, cleanup := execroot.Setup(t, nil)
+	defer cleanup()
+	tests := []struct {
+		name           string
+		cmd            []string
+		existingFiles  map[string][]byte
+		want           *flags.CommandFlags
+		wantProtoPaths []protoPath
+		wantPlugins    []string
+	}{
+		{
+			name: "search paths, plugins and outputs",
+			cmd: []string{
+				"protoc",
+				"-Iprotos",
+				"--proto_path=third_party=vendor/tp",
+				"--plugin=protoc-gen-go=plugins/protoc-gen-go",
+				"--go_out=gen",
+				"--java_out=lite:out/foo.srcjar",
+				"--descriptor_set_in=a.pb",
+				"-o", "out/desc.pb",
+				"--dependency_out=out/foo.d",
+				"foo.proto",
+			},
+			want: &flags.CommandFlags{
+				ExecutablePath:     "protoc",
+				TargetFilePaths:    []string{"foo.proto"},
+				Dependencies:       []string{"a.pb"},
+				VirtualDirectories: []string{"protos", "vendor/tp"},
+				OutputDirPaths:     []string{"gen"},
+				OutputFilePaths:    []string{"out/foo.srcjar", "out/desc.pb", "out/foo.d"},
+				ExecRoot:           er,
+				Flags: []*flags.Flag{
+					{Value: "-Iprotos"},
+					{Value: "--proto_path=third_party=vendor/tp"},
+					{Value: "--plugin=protoc-gen-go=plugins/protoc-gen-go"},
+					{Value: "--go_out=gen"},
+					{Value: "--java_out=lite:out/foo.srcjar"},
+					{Value: "--descriptor_set_in=a.pb"},
+					{Value: "-o"}, {Value: "out/desc.pb"},
+					{Value: "--dependency_out=out/foo.d"},
+					{Value: "foo.proto"},
+				},
+			},
+			wantProtoPaths: []protoPath{{Dir: "protos"}, {Prefix: "third_party", Dir: "vendor/tp"}},
+			wantPlugins:    []string{"plugins/protoc-gen-go"},
+		},
+		{
+			name: "space separated values",
+			cmd: []string{
+				"protoc",
+				"--proto_path", "protos",
+				"--plugin", "protoc-gen-x=plugins/protoc-gen-x",
+				"--cpp_out", "gen",
+				"--x_out", "out/x.zip",
+				"--x_opt", "foo=bar",
+				"--descriptor_set_in", "a.pb",
+				"--descriptor_set_out", "out/desc.pb",
+				"--dependency_out", "out/foo.d",
+				"--error_format", "gcc",
+				"foo.proto",
+			},
+			want: &flags.CommandFlags{
+				ExecutablePath:     "protoc",
+				TargetFilePaths:    []string{"foo.proto"},
+				Dependencies:       []string{"a.pb"},
+				VirtualDirectories: []string{"protos"},
+				OutputDirPaths:     []string{"gen"},
+				OutputFilePaths:    []string{"out/x.zip", "out/desc.pb", "out/foo.d"},
+				ExecRoot:           er,
+				Flags: []*flags.Flag{
+					{Value: "--proto_path"}, {Value: "protos"},
+					{Value: "--plugin"}, {Value: "protoc-gen-x=plugins/protoc-gen-x"},
+					{Value: "--cpp_out"}, {Value: "gen"},
+					{Value: "--x_out"}, {Value: "out/x.zip"},
+					{Value: "--x_opt"}, {Value: "foo=bar"},
+					{Value: "--descriptor_set_in"}, {Value: "a.pb"},
+					{Value: "--descriptor_set_out"}, {Value: "out/desc.pb"},
+					{Value: "--dependency_out"}, {Value: "out/foo.d"},
+					{Value: "--error_format"}, {Value: "gcc"},
+					{Value: "foo.proto"},
+				},
+			},
+			wantProtoPaths: []protoPath{{Dir: "protos"}},
+			wantPlugins:    []string{"plugins/protoc-gen-x"},
+		},
+		{
+			name: "rsp file with space separated values",
+			cmd:  []string{"protoc", "@args.rsp"},
+			existingFiles: map[string][]byte{
+				"args.rsp": []byte("-I protos --go_out gen foo.proto"),
+			},
+			want: &flags.CommandFlags{
+				ExecutablePath:     "protoc",
+				TargetFilePaths:    []string{"foo.proto"},
+				Dependencies:       []string{"args.rsp"},
+				VirtualDirectories: []string{"protos"},
+				OutputDirPaths:     []string{"gen"},
+				ExecRoot:           er,
+				Flags:              []*flags.Flag{{Value: "@args.rsp"}},
+			},
+			wantProtoPaths: []protoPath{{Dir: "protos"}},
+		},
+		{
+			name: "rsp file without search paths",
+			cmd:  []string{"protoc", "@args.rsp"},
+			existingFiles: map[string][]byte{
+				"args.rsp": []byte("--cpp_out=gen foo.proto"),
+			},
+			want: &flags.CommandFlags{
+				ExecutablePath:  "protoc",
+				TargetFilePaths: []string{"foo.proto"},
+				Dependencies:    []string{"args.rsp"},
+				OutputDirPaths:  []string{"gen"},
+				ExecRoot:        er,
+				Flags:           []*flags.Flag{{Value: "@args.rsp"}},
+			},
+			wantProtoPaths: []protoPath{{Dir: "."}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			execroot.AddFilesWithContent(t, er, test.existingFiles)
+			got, gotState, err := parseFlags(context.Background(), test.cmd, "", er)
+			if err != nil {
+				t.Fatalf("parseFlags(%v) returned error: %v", test.cmd, err)
+			}
+			if diff := cmp.Diff(test.want, got, cmpopts.IgnoreUnexported(flags.Flag{})); diff != "" {
+				t.Errorf("parseFlags(%v) returned diff in flags, (-want +got): %s", test.cmd, diff)
+			}
+			if diff := cmp.Diff(test.wantProtoPaths, gotState.protoPaths); diff != "" {
+				t.Errorf("parseFlags(%v) returned diff in proto paths, (-want +got): %s", test.cmd, diff)
+			}
+			if diff := cmp.Diff(test.wantPlugins, gotState.plugins); diff != "" {
+				t.Errorf("parseFlags(%v) returned diff in plugins, (-want +got): %s", test.cmd, diff)
+			}
+		})
+	}
+}
+
+func TestProtocParserMissingOutValue(t *testing.T) {
+	cmd := []string{"protoc", "foo.proto", "--cpp_out"}
+	if _, _, err := parseFlags(context.Background(), cmd, "", "/er"); err == nil {
+		t.Errorf("parseFlags(%v) returned nil error, want error", cmd)
+	}
+}
+
+func TestProtocParserInsufficientArgs(t *testing.T) {
+	if _, _, err := parseFlags(context.Background(), []string{"protoc"}, "", "/er"); err == nil {
+		t.Errorf("parseFlags([protoc]) returned nil error, want error")
+	}
+}
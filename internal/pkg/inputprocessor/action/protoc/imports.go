@@ -0,0 +1,116 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// commentRe matches the line and block comments of a .proto file.
+	commentRe = regexp.MustCompile(`(?s)//[^\n]*|/\*.*?\*/`)
+	// importRe matches the import statements of a .proto file.
+	importRe = regexp.MustCompile(`\bimport\s+(?:(?:public|weak)\s+)?["']([^"']+)["']\s*;`)
+)
+
+// importScanner computes the transitive imports of .proto files.
+type importScanner struct {
+	execRoot   string
+	workingDir string
+	protoPaths []protoPath
+}
+
+// transitiveImports returns the paths, relative to the working directory, of the given sources
+// and all the files they transitively import. Imports that cannot be found in the import
+// search paths are skipped, since they may be provided by a descriptor set.
+func (s *importScanner) transitiveImports(sources []string) ([]string, error) {
+	var res, queue []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if path == "" || seen[path] {
+			return
+		}
+		seen[path] = true
+		res = append(res, path)
+		queue = append(queue, path)
+	}
+	for _, src := range sources {
+		// Sources are given either as paths on disk or as paths within the search paths.
+		if s.exists(src) {
+			add(src)
+			continue
+		}
+		add(s.resolve(src))
+	}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		imports, err := s.imports(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, imp := range imports {
+			add(s.resolve(imp))
+		}
+	}
+	return res, nil
+}
+
+// imports returns the import paths of the given .proto file.
+func (s *importScanner) imports(path string) ([]string, error) {
+	data, err := os.ReadFile(s.abs(path))
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for _, m := range importRe.FindAllStringSubmatch(commentRe.ReplaceAllString(string(data), ""), -1) {
+		res = append(res, m[1])
+	}
+	return res, nil
+}
+
+// resolve returns the path, relative to the working directory, of the file with the given
+// import path in the first search path that contains it, or an empty string if none does.
+func (s *importScanner) resolve(importPath string) string {
+	for _, pp := range s.protoPaths {
+		rel := importPath
+		if pp.Prefix != "" {
+			if !strings.HasPrefix(rel, pp.Prefix+"/") {
+				continue
+			}
+			rel = strings.TrimPrefix(rel, pp.Prefix+"/")
+		}
+		path := filepath.Join(pp.Dir, filepath.FromSlash(rel))
+		if s.exists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+func (s *importScanner) exists(path string) bool {
+	fi, err := os.Stat(s.abs(path))
+	return err == nil && !fi.IsDir()
+}
+
+func (s *importScanner) abs(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(s.execRoot, s.workingDir, path)
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/bazelbuild/reclient/internal/pkg/execroot"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+var (
+	strSliceCmp = cmpopts.SortSlices(func(a, b string) bool { return a < b })
+)
+
+func TestProtocPreprocessor(t *testing.T) {
+	er, cleanup := execroot.Setup(t, []string{"protoc", "plugins/protoc-gen-go", "deps.pb", "unused/unused.proto", "third_party/protobuf/google/protobuf/empty.proto"})
+	defer cleanup()
+	execroot.AddFileWithContent(t, filepath.Join(er, "src/foo/foo.proto"), []byte(`syntax = "proto3";
+package foo;
+
+import "foo/bar.proto";
+import public 'google/protobuf/empty.proto';
+// import "unused/unused.proto";
+/* import "unused/unused.proto"; */
+import "from/descriptor_set.proto";
+`))
+	execroot.AddFileWithContent(t, filepath.Join(er, "src/foo/bar.proto"), []byte(`syntax = "proto3";
+import weak "foo/baz.proto";`))
+	execroot.AddFileWithContent(t, filepath.Join(er, "src/foo/baz.proto"), []byte(`syntax = "proto3";`))
+	cmd := []string{
+		"../protoc",
+		"-I../src",
+		"--proto_path", "../third_party/protobuf",
+		"--descriptor_set_in=../deps.pb",
+		"--plugin=protoc-gen-go=../plugins/protoc-gen-go",
+		"--go_out=paths=source_relative:gen/go",
+		"--go_opt=Mfoo/foo.proto=example.com/foo",
+		"--java_out=gen/java.srcjar",
+		"--descriptor_set_out=foo.pb",
+		"foo/foo.proto",
+	}
+	pp := &Preprocessor{
+		BasePreprocessor: &inputprocessor.BasePreprocessor{
+			Ctx: context.Background(),
+		},
+	}
+	gotSpec, err := inputprocessor.Compute(pp, inputprocessor.Options{
+		ExecRoot:   er,
+		WorkingDir: "out",
+		Cmd:        cmd,
+	})
+	if err != nil {
+		t.Fatalf("Compute() returned error: %v", err)
+	}
+	wantSpec := &inputprocessor.ActionSpec{
+		InputSpec: &command.InputSpec{
+			Inputs: []string{
+				"protoc",
+				filepath.Clean("plugins/protoc-gen-go"),
+				"deps.pb",
+				filepath.Clean("src/foo/foo.proto"),
+				filepath.Clean("src/foo/bar.proto"),
+				filepath.Clean("src/foo/baz.proto"),
+				filepath.Clean("third_party/protobuf/google/protobuf/empty.proto"),
+			},
+			VirtualInputs: []*command.VirtualInput{
+				{Path: "src", IsEmptyDirectory: true},
+				{Path: filepath.Clean("third_party/protobuf"), IsEmptyDirectory: true},
+			},
+		},
+		OutputFiles: []string{
+			filepath.Clean("out/gen/java.srcjar"),
+			filepath.Clean("out/foo.pb"),
+		},
+		OutputDirectories: []string{filepath.Clean("out/gen/go")},
+	}
+	if diff := cmp.Diff(wantSpec, gotSpec, strSliceCmp); diff != "" {
+		t.Errorf("Compute() returned diff in ActionSpec, (-want +got): %s", diff)
+	}
+}
+
+func TestProtocPreprocessorSourcePaths(t *testing.T) {
+	er, cleanup := execroot.Setup(t, []string{"protoc"})
+	defer cleanup()
+	execroot.AddFileWithContent(t, filepath.Join(er, "protos/a.proto"), []byte(`import "mapped/b.proto";`))
+	execroot.AddFileWithContent(t, filepath.Join(er, "lib/b.proto"), []byte(`syntax = "proto2";`))
+	execroot.AddFileWithContent(t, filepath.Join(er, "args.txt"), []byte("-Imapped=lib\n-Iprotos\n--cpp_out=gen\nprotos/a.proto\n"))
+	cmd := []string{"./protoc", "@args.txt"}
+	pp := &Preprocessor{
+		BasePreprocessor: &inputprocessor.BasePreprocessor{
+			Ctx: context.Background(),
+		},
+	}
+	gotSpec, err := inputprocessor.Compute(pp, inputprocessor.Options{
+		ExecRoot: er,
+		Cmd:      cmd,
+	})
+	if err != nil {
+		t.Fatalf("Compute() returned error: %v", err)
+	}
+	wantSpec := &inputprocessor.ActionSpec{
+		InputSpec: &command.InputSpec{
+			Inputs: []string{
+				"protoc",
+				"args.txt",
+				filepath.Clean("protos/a.proto"),
+				filepath.Clean("lib/b.proto"),
+			},
+			VirtualInputs: []*command.VirtualInput{
+				{Path: "lib", IsEmptyDirectory: true},
+				{Path: "protos", IsEmptyDirectory: true},
+			},
+		},
+		OutputDirectories: []string{"gen"},
+	}
+	if diff := cmp.Diff(wantSpec, gotSpec, strSliceCmp); diff != "" {
+		t.Errorf("Compute() returned diff in ActionSpec, (-want +got): %s", diff)
+	}
+}
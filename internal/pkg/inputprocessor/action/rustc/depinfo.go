@@ -0,0 +1,101 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rustc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// parseDepInfo parses the contents of a Makefile style dep-info file emitted by rustc and
+// returns the unique prerequisites of all its rules, in order of appearance.
+func parseDepInfo(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\\\n", " ")
+	var res []string
+	seen := make(map[string]bool)
+	for _, l := range strings.Split(content, "\n") {
+		l = strings.TrimSpace(l)
+		// Comments hold the environment variables and checksums the crate depends on.
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		_, prereqs, ok := strings.Cut(l, ": ")
+		if !ok {
+			continue
+		}
+		for _, p := range splitEscaped(prereqs) {
+			if !seen[p] {
+				seen[p] = true
+				res = append(res, p)
+			}
+		}
+	}
+	return res
+}
+
+// splitEscaped splits a list of space separated paths, where spaces in the paths are escaped
+// with a backslash.
+func splitEscaped(s string) []string {
+	var res []string
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == ' ':
+			sb.WriteByte(' ')
+			i++
+		case s[i] == ' ' || s[i] == '\t':
+			if sb.Len() > 0 {
+				res = append(res, sb.String())
+				sb.Reset()
+			}
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	if sb.Len() > 0 {
+		res = append(res, sb.String())
+	}
+	return res
+}
+
+// readDepInfoIfFresh reads the dependencies from the given dep-info file if it exists and none
+// of the dependencies it lists were removed or modified after it was written. Relative paths
+// are resolved against dir. Returns false if the file can't be relied upon.
+func readDepInfoIfFresh(path, dir string) ([]string, bool) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	deps := parseDepInfo(string(data))
+	if len(deps) == 0 {
+		return nil, false
+	}
+	for _, d := range deps {
+		if !filepath.IsAbs(d) {
+			d = filepath.Join(dir, d)
+		}
+		dfi, err := os.Stat(d)
+		if err != nil || dfi.ModTime().After(fi.ModTime()) {
+			return nil, false
+		}
+	}
+	return deps, true
+}
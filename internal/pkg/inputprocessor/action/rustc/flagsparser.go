@@ -0,0 +1,368 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rustc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/args"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/flags"
+)
+
+// searchPathKinds are the kinds a -L search path can be prefixed with.
+var searchPathKinds = []string{"dependency=", "crate=", "native=", "framework=", "all="}
+
+// emit is a single output type requested with --emit, optionally with an explicit path.
+type emit struct {
+	kind string
+	path string
+}
+
+// rustcState holds the rustc specific information collected while parsing a command that is
+// needed to compute its outputs and to emit its dep-info locally.
+type rustcState struct {
+	src           string
+	crateName     string
+	crateTypes    []string
+	emits         []emit
+	outDir        string
+	outFile       string
+	extraFilename string
+	target        string
+	// depInfoArgs are the expanded arguments of the command, without the flags that determine
+	// which outputs are produced and where.
+	depInfoArgs []string
+}
+
+// parseFlags is used to transform a rustc command into a CommandFlags structure. It also returns
+// the expanded arguments of the command without any output flags, which can be used to run rustc
+// with --emit=dep-info.
+func parseFlags(ctx context.Context, command []string, workingDir, execRoot string) (*flags.CommandFlags, []string, error) {
+	numArgs := len(command)
+	if numArgs < 2 {
+		return nil, nil, fmt.Errorf("insufficient number of arguments in command: %v", command)
+	}
+
+	res := &flags.CommandFlags{
+		ExecutablePath:   command[0],
+		WorkingDirectory: workingDir,
+		ExecRoot:         execRoot,
+	}
+	st := &rustcState{}
+	s := args.Scanner{
+		Args: command[1:],
+		Flags: map[string]int{
+			"--extern":            1,
+			"-L":                  1,
+			"--out-dir":           1,
+			"--emit":              1,
+			"--crate-name":        1,
+			"--crate-type":        1,
+			"-o":                  1,
+			"-C":                  1,
+			"--codegen":           1,
+			"--target":            1,
+			"--sysroot":           1,
+			"--cfg":               1,
+			"--check-cfg":         1,
+			"--edition":           1,
+			"--cap-lints":         1,
+			"-A":                  1,
+			"-W":                  1,
+			"-D":                  1,
+			"-F":                  1,
+			"--error-format":      1,
+			"--json":              1,
+			"--print":             1,
+			"--remap-path-prefix": 1,
+			"-l":                  1,
+			"-Z":                  1,
+		},
+		Joined: []args.PrefixOption{
+			{Prefix: "-o"},
+			{Prefix: "-L"},
+			{Prefix: "-C"},
+			{Prefix: "--target="},
+			{Prefix: "--sysroot="},
+			{Prefix: "--out-dir="},
+			{Prefix: "--extern="},
+			{Prefix: "--emit="},
+			{Prefix: "--crate-type="},
+			{Prefix: "--crate-name="},
+			{Prefix: "--codegen="},
+		},
+		Normalized: map[string]string{
+			"--target=":     "--target",
+			"--sysroot=":    "--sysroot",
+			"--out-dir=":    "--out-dir",
+			"--extern=":     "--extern",
+			"--emit=":       "--emit",
+			"--crate-type=": "--crate-type",
+			"--crate-name=": "--crate-name",
+			"--codegen":     "-C",
+			"--codegen=":    "-C",
+		},
+	}
+	for s.HasNext() {
+		if err := handleRustcFlags(res, st, &s); err != nil {
+			return nil, nil, err
+		}
+	}
+	if st.src != "" {
+		res.TargetFilePaths = append(res.TargetFilePaths, st.src)
+	}
+	res.OutputFilePaths, res.EmittedDependencyFile = st.outputs()
+	return res, st.depInfoArgs, nil
+}
+
+func handleRustcFlags(cmdFlags *flags.CommandFlags, st *rustcState, scanner *args.Scanner) error {
+	nextRes := scanner.ReadNextFlag()
+	if strings.HasPrefix(nextRes.Args[0], "@") {
+		argFile := nextRes.Args[0][1:]
+		cmdFlags.Dependencies = append(cmdFlags.Dependencies, argFile)
+		cmdFlags.Flags = append(cmdFlags.Flags, &flags.Flag{Value: nextRes.Args[0]})
+		if !filepath.IsAbs(argFile) {
+			argFile = filepath.Join(cmdFlags.ExecRoot, cmdFlags.WorkingDirectory, argFile)
+		}
+		fileArgs, err := readArgFile(argFile)
+		if err != nil {
+			return err
+		}
+		// The flags within the argument file are not passed along, just the argument file
+		// itself, so they are collected into a throwaway CommandFlags structure.
+		f := &flags.CommandFlags{
+			ExecRoot:         cmdFlags.ExecRoot,
+			WorkingDirectory: cmdFlags.WorkingDirectory,
+		}
+		sc := &args.Scanner{
+			Args:       fileArgs,
+			Flags:      scanner.Flags,
+			Joined:     scanner.Joined,
+			Normalized: scanner.Normalized,
+		}
+		for sc.HasNext() {
+			sc.ReadNextFlag()
+			handleRustcArg(f, st, sc)
+		}
+		cmdFlags.Dependencies = append(cmdFlags.Dependencies, f.Dependencies...)
+		cmdFlags.VirtualDirectories = append(cmdFlags.VirtualDirectories, f.VirtualDirectories...)
+		return nil
+	}
+	handleRustcArg(cmdFlags, st, scanner)
+	for _, arg := range nextRes.Args {
+		cmdFlags.Flags = append(cmdFlags.Flags, &flags.Flag{Value: arg})
+	}
+	return nil
+}
+
+// handleRustcArg records the dependencies and outputs indicated by the current flag of the
+// scanner.
+func handleRustcArg(f *flags.CommandFlags, st *rustcState, sc *args.Scanner) {
+	curr := sc.CurResult
+	flag, values := curr.NormalizedKey, curr.Values
+	// Flags that determine the outputs of the command are dropped from the arguments used to
+	// emit dep-info.
+	keep := true
+	switch flag {
+	case "--extern":
+		// The value is of the form [modifiers:]name[=path].
+		if _, path, ok := strings.Cut(values[0], "="); ok && path != "" {
+			f.Dependencies = append(f.Dependencies, path)
+		}
+	case "-L":
+		path := values[0]
+		for _, k := range searchPathKinds {
+			if strings.HasPrefix(path, k) {
+				path = strings.TrimPrefix(path, k)
+				break
+			}
+		}
+		if path != "" {
+			f.Dependencies = append(f.Dependencies, path)
+		}
+	case "--out-dir":
+		st.outDir = values[0]
+		keep = false
+	case "-o":
+		st.outFile = values[0]
+		keep = false
+	case "--emit":
+		for _, e := range strings.Split(values[0], ",") {
+			kind, path, _ := strings.Cut(e, "=")
+			st.emits = append(st.emits, emit{kind: kind, path: path})
+		}
+		keep = false
+	case "--crate-name":
+		st.crateName = values[0]
+	case "--crate-type":
+		st.crateTypes = append(st.crateTypes, strings.Split(values[0], ",")...)
+	case "-C":
+		k, v, _ := strings.Cut(values[0], "=")
+		switch k {
+		case "incremental":
+			// Incremental compilation state is only useful to the machine that produced it, so
+			// the directory is made to exist for the action but is never downloaded.
+			f.VirtualDirectories = append(f.VirtualDirectories, v)
+			keep = false
+		case "extra-filename":
+			st.extraFilename = v
+		}
+	case "--target":
+		st.target = values[0]
+		// Custom targets are specified as a path to a JSON target specification.
+		if strings.HasSuffix(values[0], ".json") {
+			f.Dependencies = append(f.Dependencies, values[0])
+		}
+	case "--sysroot":
+		f.Dependencies = append(f.Dependencies, values[0])
+	case "":
+		st.src = curr.Args[0]
+	}
+	if keep {
+		st.depInfoArgs = append(st.depInfoArgs, curr.Args...)
+	}
+}
+
+// readArgFile reads a rustc argument file, which holds a single argument per line.
+func readArgFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for _, l := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if l != "" {
+			res = append(res, l)
+		}
+	}
+	return res, nil
+}
+
+// outputs returns the output files of the command as determined by --emit, --crate-name,
+// --crate-type, --out-dir and -o, along with the dep-info file if one is emitted. When -o is
+// set, it names the linked output and its directory and file stem are used for all other
+// outputs, as rustc does.
+func (st *rustcState) outputs() ([]string, string) {
+	emits := st.emits
+	if len(emits) == 0 {
+		emits = []emit{{kind: "link"}}
+	}
+	crateTypes := st.crateTypes
+	if len(crateTypes) == 0 {
+		crateTypes = []string{"bin"}
+	}
+	outDir, stem := st.outDir, st.crateName
+	if stem == "" && st.src != "" {
+		stem = strings.ReplaceAll(strings.TrimSuffix(filepath.Base(st.src), filepath.Ext(st.src)), "-", "_")
+	}
+	stem += st.extraFilename
+	if st.outFile != "" {
+		outDir = filepath.Dir(st.outFile)
+		stem = strings.TrimSuffix(filepath.Base(st.outFile), filepath.Ext(st.outFile))
+	}
+	var outs []string
+	var depFile string
+	for _, e := range emits {
+		// -o names the output itself, unless that would be ambiguous.
+		named := st.outFile != "" && (len(emits) == 1 || e.kind == "link")
+		if e.kind == "link" && len(crateTypes) > 1 {
+			named = false
+		}
+		var paths []string
+		switch {
+		case e.path == "-":
+			// The output is written to stdout.
+		case e.path != "":
+			paths = []string{e.path}
+		case named:
+			paths = []string{st.outFile}
+		case stem == "":
+		case e.kind == "link":
+			for _, ct := range crateTypes {
+				paths = append(paths, filepath.Join(outDir, linkFilename(ct, stem, st.target)))
+			}
+		default:
+			if n := emitFilename(e.kind, stem); n != "" {
+				paths = []string{filepath.Join(outDir, n)}
+			}
+		}
+		if e.kind == "dep-info" && len(paths) > 0 {
+			depFile = paths[0]
+		}
+		outs = append(outs, paths...)
+	}
+	return outs, depFile
+}
+
+// emitFilename returns the default file name of a non-link output type.
+func emitFilename(kind, stem string) string {
+	switch kind {
+	case "asm":
+		return stem + ".s"
+	case "llvm-bc":
+		return stem + ".bc"
+	case "llvm-ir":
+		return stem + ".ll"
+	case "obj":
+		return stem + ".o"
+	case "mir":
+		return stem + ".mir"
+	case "dep-info":
+		return stem + ".d"
+	case "metadata":
+		return "lib" + stem + ".rmeta"
+	}
+	return ""
+}
+
+// linkFilename returns the default file name of the linked output of the given crate type for
+// the given target triple, or the host if no target is given.
+func linkFilename(crateType, stem, target string) string {
+	goos := runtime.GOOS
+	switch {
+	case strings.Contains(target, "windows"):
+		goos = "windows"
+	case strings.Contains(target, "apple"):
+		goos = "darwin"
+	case target != "":
+		goos = "linux"
+	}
+	switch crateType {
+	case "lib", "rlib":
+		return "lib" + stem + ".rlib"
+	case "staticlib":
+		if goos == "windows" {
+			return stem + ".lib"
+		}
+		return "lib" + stem + ".a"
+	case "dylib", "cdylib", "proc-macro":
+		switch goos {
+		case "windows":
+			return stem + ".dll"
+		case "darwin":
+			return "lib" + stem + ".dylib"
+		}
+		return "lib" + stem + ".so"
+	}
+	if goos == "windows" {
+		return stem + ".exe"
+	}
+	return stem
+}
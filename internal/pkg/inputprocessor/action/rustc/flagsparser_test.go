@@ -0,0 +1,170 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rustc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bazelbuild/reclient/internal/pkg/execroot"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/flags"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestRustcParser(t *testing.T) {
+	er, cleanup := execroot.Setup(t, nil)
+	defer cleanup()
+	tests := []struct {
+		name          string
+		cmd           []string
+		existingFiles map[string][]byte
+		want          *flags.CommandFlags
+		wantDepInfo   []string
+	}{
+		{
+			name: "cargo style lib",
+			cmd: []string{
+				"rustc",
+				"--crate-name", "foo_bar",
+				"--edition=2021",
+				"src/lib.rs",
+				"--crate-type", "lib",
+				"--emit=dep-info,metadata,link",
+				"-C", "opt-level=3",
+				"-C", "extra-filename=-0123abcd",
+				"-Cincremental=target/incremental",
+				"--out-dir", "target/deps",
+				"-L", "dependency=target/deps",
+				"--extern", "serde=target/deps/libserde-4567.rlib",
+				"--extern", "noprelude:alloc=target/deps/liballoc.rlib",
+				"--extern", "proc_macro",
+			},
+			want: &flags.CommandFlags{
+				ExecutablePath:  "rustc",
+				TargetFilePaths: []string{"src/lib.rs"},
+				Dependencies:    []string{"target/deps", "target/deps/libserde-4567.rlib", "target/deps/liballoc.rlib"},
+				OutputFilePaths: []string{
+					"target/deps/foo_bar-0123abcd.d",
+					"target/deps/libfoo_bar-0123abcd.rmeta",
+					"target/deps/libfoo_bar-0123abcd.rlib",
+				},
+				EmittedDependencyFile: "target/deps/foo_bar-0123abcd.d",
+				VirtualDirectories:    []string{"target/incremental"},
+				ExecRoot:              er,
+				Flags: []*flags.Flag{
+					{Value: "--crate-name"}, {Value: "foo_bar"},
+					{Value: "--edition=2021"},
+					{Value: "src/lib.rs"},
+					{Value: "--crate-type"}, {Value: "lib"},
+					{Value: "--emit=dep-info,metadata,link"},
+					{Value: "-C"}, {Value: "opt-level=3"},
+					{Value: "-C"}, {Value: "extra-filename=-0123abcd"},
+					{Value: "-Cincremental=target/incremental"},
+					{Value: "--out-dir"}, {Value: "target/deps"},
+					{Value: "-L"}, {Value: "dependency=target/deps"},
+					{Value: "--extern"}, {Value: "serde=target/deps/libserde-4567.rlib"},
+					{Value: "--extern"}, {Value: "noprelude:alloc=target/deps/liballoc.rlib"},
+					{Value: "--extern"}, {Value: "proc_macro"},
+				},
+			},
+			wantDepInfo: []string{
+				"--crate-name", "foo_bar",
+				"--edition=2021",
+				"src/lib.rs",
+				"--crate-type", "lib",
+				"-C", "opt-level=3",
+				"-C", "extra-filename=-0123abcd",
+				"-L", "dependency=target/deps",
+				"--extern", "serde=target/deps/libserde-4567.rlib",
+				"--extern", "noprelude:alloc=target/deps/liballoc.rlib",
+				"--extern", "proc_macro",
+			},
+		},
+		{
+			name: "argfile with explicit emit paths",
+			cmd:  []string{"rustc", "@foo.params"},
+			existingFiles: map[string][]byte{
+				"foo.params": []byte("my-bin/main.rs\n--crate-type=bin\n--emit=link=out/my-bin,dep-info=out/my-bin.d\n-Lnative=third_party/lib\n--extern=quux=out/libquux.rlib\n--target=targets/custom.json\n"),
+			},
+			want: &flags.CommandFlags{
+				ExecutablePath:        "rustc",
+				TargetFilePaths:       []string{"my-bin/main.rs"},
+				Dependencies:          []string{"foo.params", "third_party/lib", "out/libquux.rlib", "targets/custom.json"},
+				OutputFilePaths:       []string{"out/my-bin", "out/my-bin.d"},
+				EmittedDependencyFile: "out/my-bin.d",
+				ExecRoot:              er,
+				Flags:                 []*flags.Flag{{Value: "@foo.params"}},
+			},
+			wantDepInfo: []string{
+				"my-bin/main.rs",
+				"--crate-type=bin",
+				"-Lnative=third_party/lib",
+				"--extern=quux=out/libquux.rlib",
+				"--target=targets/custom.json",
+			},
+		},
+		{
+			name: "output file names other outputs",
+			cmd:  []string{"rustc", "--crate-type=rlib", "--emit", "link,dep-info", "-o", "out/libfoo.rlib", "foo.rs"},
+			want: &flags.CommandFlags{
+				ExecutablePath:        "rustc",
+				TargetFilePaths:       []string{"foo.rs"},
+				OutputFilePaths:       []string{"out/libfoo.rlib", "out/libfoo.d"},
+				EmittedDependencyFile: "out/libfoo.d",
+				ExecRoot:              er,
+				Flags: []*flags.Flag{
+					{Value: "--crate-type=rlib"},
+					{Value: "--emit"}, {Value: "link,dep-info"},
+					{Value: "-o"}, {Value: "out/libfoo.rlib"},
+					{Value: "foo.rs"},
+				},
+			},
+			wantDepInfo: []string{"--crate-type=rlib", "foo.rs"},
+		},
+		{
+			name: "crate name derived from source",
+			cmd:  []string{"rustc", "--crate-type", "staticlib,cdylib", "--target", "x86_64-unknown-linux-gnu", "my-crate.rs"},
+			want: &flags.CommandFlags{
+				ExecutablePath:  "rustc",
+				TargetFilePaths: []string{"my-crate.rs"},
+				OutputFilePaths: []string{"libmy_crate.a", "libmy_crate.so"},
+				ExecRoot:        er,
+				Flags: []*flags.Flag{
+					{Value: "--crate-type"}, {Value: "staticlib,cdylib"},
+					{Value: "--target"}, {Value: "x86_64-unknown-linux-gnu"},
+					{Value: "my-crate.rs"},
+				},
+			},
+			wantDepInfo: []string{"--crate-type", "staticlib,cdylib", "--target", "x86_64-unknown-linux-gnu", "my-crate.rs"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			execroot.AddFilesWithContent(t, er, test.existingFiles)
+			got, gotDepInfo, err := parseFlags(context.Background(), test.cmd, "", er)
+			if err != nil {
+				t.Fatalf("parseFlags(%v) returned error: %v", test.cmd, err)
+			}
+			if diff := cmp.Diff(test.want, got, cmpopts.IgnoreUnexported(flags.Flag{})); diff != "" {
+				t.Errorf("parseFlags(%v) returned diff in flags, (-want +got): %s", test.cmd, diff)
+			}
+			if diff := cmp.Diff(test.wantDepInfo, gotDepInfo); diff != "" {
+				t.Errorf("parseFlags(%v) returned diff in dep-info args, (-want +got): %s", test.cmd, diff)
+			}
+		})
+	}
+}
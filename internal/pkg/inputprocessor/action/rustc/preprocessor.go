@@ -0,0 +1,115 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rustc performs input processing given a valid rustc action.
+package rustc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor"
+	"github.com/bazelbuild/reclient/internal/pkg/pathtranslator"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+
+	log "github.com/golang/glog"
+)
+
+// Preprocessor is the preprocessor of rustc compile actions.
+type Preprocessor struct {
+	*inputprocessor.BasePreprocessor
+	// depInfoArgs are the arguments of the command without the flags that determine its
+	// outputs.
+	depInfoArgs []string
+}
+
+// ParseFlags parses the commands flags and populates the ActionSpec object with inferred
+// information.
+func (p *Preprocessor) ParseFlags() error {
+	f, depInfoArgs, err := parseFlags(p.Ctx, p.Options.Cmd, p.Options.WorkingDir, p.Options.ExecRoot)
+	if err != nil {
+		p.Err = fmt.Errorf("flag parsing failed. %v", err)
+		return p.Err
+	}
+	p.Flags = f
+	p.depInfoArgs = depInfoArgs
+	p.FlagsToActionSpec()
+	return nil
+}
+
+// ComputeSpec computes the source and crate inputs of the action from the dep-info of the
+// crate. An existing dep-info file emitted by a previous run of the command is used if it is
+// still fresh, otherwise rustc is run locally to emit it.
+func (p *Preprocessor) ComputeSpec() error {
+	s := &inputprocessor.ActionSpec{InputSpec: &command.InputSpec{}}
+	defer p.AppendSpec(s)
+
+	deps, err := p.dependencies()
+	if err != nil {
+		s.UsedShallowMode = true
+		return err
+	}
+	s.InputSpec.Inputs = pathtranslator.ListRelToExecRoot(p.Options.ExecRoot, p.Options.WorkingDir, deps)
+	return nil
+}
+
+// dependencies returns the dependencies of the crate, relative to the working directory or
+// absolute.
+func (p *Preprocessor) dependencies() ([]string, error) {
+	wd := filepath.Join(p.Options.ExecRoot, p.Options.WorkingDir)
+	if df := p.Flags.EmittedDependencyFile; df != "" {
+		if !filepath.IsAbs(df) {
+			df = filepath.Join(wd, df)
+		}
+		if deps, ok := readDepInfoIfFresh(df, wd); ok {
+			return deps, nil
+		}
+	}
+	if p.Executor == nil {
+		return nil, fmt.Errorf("no executor passed to the rustc input processor")
+	}
+	tmpDir, err := os.MkdirTemp("", "rustc-dep-info-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			log.Warningf("%v: Failed to remove %v: %v", p.Options.ExecutionID, tmpDir, err)
+		}
+	}()
+	depFile := filepath.Join(tmpDir, "dep-info.d")
+	s, err := p.Spec()
+	if err != nil {
+		return nil, err
+	}
+	args := append([]string{p.Flags.ExecutablePath}, p.depInfoArgs...)
+	args = append(args, "--emit=dep-info="+depFile)
+	_, stderr, err := p.Executor.Execute(p.Ctx, &command.Command{
+		Args:       args,
+		WorkingDir: wd,
+		InputSpec: &command.InputSpec{
+			EnvironmentVariables: s.InputSpec.EnvironmentVariables,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to emit dep-info: %v, stderr: %v", err, stderr)
+	}
+	data, err := os.ReadFile(depFile)
+	if err != nil {
+		return nil, err
+	}
+	return parseDepInfo(string(data)), nil
+}
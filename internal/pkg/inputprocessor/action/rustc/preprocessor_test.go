@@ -0,0 +1,219 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rustc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bazelbuild/reclient/internal/pkg/execroot"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const (
+	wd = "wd"
+)
+
+var (
+	strSliceCmp = cmpopts.SortSlices(func(a, b string) bool { return a < b })
+)
+
+func TestRustcPreprocessor(t *testing.T) {
+	er, cleanup := execroot.Setup(t, []string{
+		"wd/src/lib.rs",
+		"wd/src/a.rs",
+		"wd/src/b/mod.rs",
+		"wd/deps/libserde.rlib",
+		"wd/README.md",
+		"rustc",
+	})
+	defer cleanup()
+	execroot.AddDirs(t, er, []string{"wd/inc"})
+	e := &depInfoStub{content: "dep-info.d: src/lib.rs src/a.rs src/b/mod.rs ../wd/README.md\n\nsrc/lib.rs:\nsrc/a.rs:\nsrc/b/mod.rs:\n../wd/README.md:\n\n# env-dep:CARGO_PKG_NAME=foo\n"}
+	cmd := []string{"../rustc", "--crate-name", "foo", "--crate-type", "rlib", "--emit=dep-info,link", "-C", "incremental=inc", "--out-dir", "out", "--extern", "serde=deps/libserde.rlib", "src/lib.rs"}
+	pp := &Preprocessor{
+		BasePreprocessor: &inputprocessor.BasePreprocessor{
+			Ctx:      context.Background(),
+			Executor: e,
+		},
+	}
+	gotSpec, err := inputprocessor.Compute(pp, inputprocessor.Options{
+		Cmd:        cmd,
+		WorkingDir: wd,
+		ExecRoot:   er,
+	})
+	if err != nil {
+		t.Fatalf("Compute() returned error: %v", err)
+	}
+	wantSpec := &inputprocessor.ActionSpec{
+		InputSpec: &command.InputSpec{
+			Inputs: []string{
+				filepath.Clean("rustc"),
+				filepath.Clean("wd/src/lib.rs"),
+				filepath.Clean("wd/src/a.rs"),
+				filepath.Clean("wd/src/b/mod.rs"),
+				filepath.Clean("wd/deps/libserde.rlib"),
+				filepath.Clean("wd/README.md"),
+			},
+			VirtualInputs: []*command.VirtualInput{
+				{Path: filepath.Clean("wd/inc"), IsEmptyDirectory: true},
+			},
+		},
+		OutputFiles:           []string{filepath.Clean("wd/out/foo.d"), filepath.Clean("wd/out/libfoo.rlib")},
+		EmittedDependencyFile: filepath.Clean("wd/out/foo.d"),
+	}
+	if diff := cmp.Diff(wantSpec, gotSpec, strSliceCmp); diff != "" {
+		t.Errorf("Compute() returned diff in ActionSpec, (-want +got): %s", diff)
+	}
+	wantArgs := []string{"../rustc", "--crate-name", "foo", "--crate-type", "rlib", "--extern", "serde=deps/libserde.rlib", "src/lib.rs", "--emit=dep-info="}
+	if diff := cmp.Diff(wantArgs, e.args); diff != "" {
+		t.Errorf("Compute() ran rustc with diff in args, (-want +got): %s", diff)
+	}
+	if e.wd != filepath.Join(er, wd) {
+		t.Errorf("Compute() ran rustc in %v, want %v", e.wd, filepath.Join(er, wd))
+	}
+}
+
+func TestRustcPreprocessorExistingDepInfo(t *testing.T) {
+	tests := []struct {
+		name       string
+		stale      bool
+		wantInputs []string
+	}{
+		{
+			name:       "fresh",
+			wantInputs: []string{"rustc", "wd/main.rs", "wd/old.rs"},
+		},
+		{
+			name:       "stale",
+			stale:      true,
+			wantInputs: []string{"rustc", "wd/main.rs", "wd/new.rs"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			er, cleanup := execroot.Setup(t, []string{"wd/main.rs", "wd/old.rs", "wd/new.rs", "rustc"})
+			defer cleanup()
+			execroot.AddFileWithContent(t, filepath.Join(er, wd, "main.d"), []byte("main: main.rs old.rs\n"))
+			if test.stale {
+				future := time.Now().Add(time.Hour)
+				if err := os.Chtimes(filepath.Join(er, wd, "main.rs"), future, future); err != nil {
+					t.Fatalf("Chtimes() returned error: %v", err)
+				}
+			} else {
+				past := time.Now().Add(-time.Hour)
+				for _, f := range []string{"main.rs", "old.rs"} {
+					if err := os.Chtimes(filepath.Join(er, wd, f), past, past); err != nil {
+						t.Fatalf("Chtimes() returned error: %v", err)
+					}
+				}
+			}
+			e := &depInfoStub{content: "dep-info.d: main.rs new.rs\n"}
+			pp := &Preprocessor{
+				BasePreprocessor: &inputprocessor.BasePreprocessor{
+					Ctx:      context.Background(),
+					Executor: e,
+				},
+			}
+			gotSpec, err := inputprocessor.Compute(pp, inputprocessor.Options{
+				Cmd:        []string{"../rustc", "--emit=link,dep-info", "main.rs"},
+				WorkingDir: wd,
+				ExecRoot:   er,
+			})
+			if err != nil {
+				t.Fatalf("Compute() returned error: %v", err)
+			}
+			var want []string
+			for _, i := range test.wantInputs {
+				want = append(want, filepath.Clean(i))
+			}
+			if diff := cmp.Diff(want, gotSpec.InputSpec.Inputs, strSliceCmp); diff != "" {
+				t.Errorf("Compute() returned diff in inputs, (-want +got): %s", diff)
+			}
+			if ran := e.args != nil; ran != test.stale {
+				t.Errorf("Compute() ran rustc = %v, want %v", ran, test.stale)
+			}
+		})
+	}
+}
+
+func TestRustcPreprocessorDepInfoFailure(t *testing.T) {
+	er, cleanup := execroot.Setup(t, []string{"wd/main.rs", "wd/mod.rs", "rustc"})
+	defer cleanup()
+	pp := &Preprocessor{
+		BasePreprocessor: &inputprocessor.BasePreprocessor{
+			Ctx:      context.Background(),
+			Executor: &depInfoStub{err: errors.New("rustc failed")},
+		},
+	}
+	gotSpec, err := inputprocessor.Compute(pp, inputprocessor.Options{
+		Cmd:             []string{"../rustc", "main.rs"},
+		WorkingDir:      wd,
+		ExecRoot:        er,
+		ShallowFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("Compute() returned error: %v", err)
+	}
+	if !gotSpec.UsedShallowMode {
+		t.Errorf("Compute() UsedShallowMode = false, want true")
+	}
+	want := []string{filepath.Clean("rustc"), filepath.Clean("wd/main.rs")}
+	if diff := cmp.Diff(want, gotSpec.InputSpec.Inputs, strSliceCmp); diff != "" {
+		t.Errorf("Compute() returned diff in inputs, (-want +got): %s", diff)
+	}
+}
+
+func TestParseDepInfo(t *testing.T) {
+	content := "out/foo.d: src/lib.rs src/with\\ space.rs \\\n  /abs/gen.rs\r\n\nout/libfoo.rlib: src/lib.rs src/with\\ space.rs /abs/gen.rs\n\nsrc/lib.rs:\n\n# env-dep:OUT_DIR=/abs\n"
+	want := []string{"src/lib.rs", "src/with space.rs", "/abs/gen.rs"}
+	if diff := cmp.Diff(want, parseDepInfo(content)); diff != "" {
+		t.Errorf("parseDepInfo() returned diff, (-want +got): %s", diff)
+	}
+}
+
+// depInfoStub is an executor that writes the given dep-info content to the path passed with
+// --emit=dep-info=.
+type depInfoStub struct {
+	content string
+	err     error
+	args    []string
+	wd      string
+}
+
+func (e *depInfoStub) Execute(ctx context.Context, cmd *command.Command) (string, string, error) {
+	e.wd = cmd.WorkingDir
+	for _, a := range cmd.Args {
+		if strings.HasPrefix(a, "--emit=dep-info=") {
+			if e.err == nil {
+				if err := os.WriteFile(strings.TrimPrefix(a, "--emit=dep-info="), []byte(e.content), 0644); err != nil {
+					return "", "", err
+				}
+			}
+			a = "--emit=dep-info="
+		}
+		e.args = append(e.args, a)
+	}
+	return "", "", e.err
+}
@@ -341,7 +341,8 @@ func (c *BasePreprocessor) FilterVirtualInputs() {
 						c.FileStatCache.Store(path, cache)
 					}
 				}
-				stat = cache.(fs.FileInfo)
+				// Virtual inputs need not exist, in which case there is no file info.
+				stat, _ = cache.(fs.FileInfo)
 				err = e
 			} else {
 				stat, err = os.Stat(path)
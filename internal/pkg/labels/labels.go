@@ -60,6 +60,8 @@ const (
 	ClangCL = "clang-cl"
 	// NaCl for compiling c/c++ with nacl clang (different flag semantics)
 	NaCl = "nacl"
+	// Gcc for compiling c/c++ with gcc.
+	Gcc = "gcc"
 	// Javac for compiling java files.
 	Javac = "javac"
 	// R8 for compiling class files into dex files with further optimizations.
@@ -76,6 +78,14 @@ const (
 	SignAPKJAR = "signapkjar"
 	// TsCompiler for compiling typescript files.
 	TsCompiler = "tsc"
+	// Rustc for compiling rust crates.
+	Rustc = "rustc"
+	// Kotlinc for compiling kotlin files.
+	Kotlinc = "kotlinc"
+	// GoCompiler for compiling go packages with go tool compile.
+	GoCompiler = "gc"
+	// Protoc for generating code from protocol buffer definitions.
+	Protoc = "protoc"
 
 	// Languages
 
@@ -85,6 +95,14 @@ const (
 	Java = "java"
 	// Ts indicates the language is typescript.
 	Ts = "typescript"
+	// Rust indicates the language is rust.
+	Rust = "rust"
+	// Kotlin indicates the language is kotlin.
+	Kotlin = "kotlin"
+	// Go indicates the language is go.
+	Go = "go"
+	// Proto indicates the language is protocol buffers.
+	Proto = "proto"
 
 	// Action Tools
 
@@ -95,6 +113,9 @@ const (
 	// LLVMTool indicates that the tool being used for this action is llvm.
 	// This is currently used for archive actions, where llvm-ar is used.
 	LLVMTool = "llvm"
+	// GoTool indicates that the tool being used for this action is the go toolchain.
+	// This is currently used for link actions, where go tool link is used.
+	GoTool = "go"
 )
 
 var (
@@ -136,6 +157,15 @@ func ClangCLCppLabels() Labels {
 	}
 }
 
+// GccCppLabels is the set of labels identifying a cpp compile with gcc.
+func GccCppLabels() Labels {
+	return Labels{
+		ActionType: Compile,
+		Compiler:   Gcc,
+		Lang:       Cpp,
+	}
+}
+
 // NaClLabels is the set of labels identifying a cpp compile with native client compilers.
 func NaClLabels() Labels {
 	return Labels{
@@ -231,6 +261,50 @@ func TscLabels() Labels {
 	}
 }
 
+// RustcLabels is the set of labels identifying a rust compile with rustc.
+func RustcLabels() Labels {
+	return Labels{
+		ActionType: Compile,
+		Compiler:   Rustc,
+		Lang:       Rust,
+	}
+}
+
+// KotlincLabels is the set of labels identifying a kotlin compile with kotlinc.
+func KotlincLabels() Labels {
+	return Labels{
+		ActionType: Compile,
+		Compiler:   Kotlinc,
+		Lang:       Kotlin,
+	}
+}
+
+// GoCompileLabels is the set of labels identifying a go compile with go tool compile.
+func GoCompileLabels() Labels {
+	return Labels{
+		ActionType: Compile,
+		Compiler:   GoCompiler,
+		Lang:       Go,
+	}
+}
+
+// GoLinkLabels is the set of labels identifying a go link with go tool link.
+func GoLinkLabels() Labels {
+	return Labels{
+		ActionType: Link,
+		ActionTool: GoTool,
+	}
+}
+
+// ProtocLabels is the set of labels identifying a protocol buffer compile with protoc.
+func ProtocLabels() Labels {
+	return Labels{
+		ActionType: Compile,
+		Compiler:   Protoc,
+		Lang:       Proto,
+	}
+}
+
 // FromMap converts a map of labels to a Labels struct.
 func FromMap(l map[string]string) Labels {
 	res := Labels{}
@@ -283,14 +357,20 @@ func ToKey(labels map[string]string) string {
 // Digest of labels of various action types for quick reference:
 // [compiler=clang,lang=cpp,type=compile]=1c2a12e4
 // [compiler=clang-cl,lang=cpp,type=compile]=f1d5b747
+// [compiler=gcc,lang=cpp,type=compile]=372755a9
 // [tool=clang,type=link]=c8d5e900
 // [lang=cpp,tool=clang-tidy,type=lint]=ef9b9dea
 // [tool=header-abi-dumper,type=abi-dump]=480a02e7
 // [compiler=javac,lang=java,type=compile]=2c59b32a
+// [compiler=kotlinc,lang=kotlin,type=compile]=b8d4ecd2
 // [compiler=r8,type=compile]=fc0915a4
 // [compiler=d8,type=compile]=4be622a4
 // [compiler=metalava,lang=java,type=compile]=44779548
 // [compiler=signapkjar,type=apksigning]=c5a25a91
+// [compiler=rustc,lang=rust,type=compile]=df8cbc05
+// [compiler=gc,lang=go,type=compile]=099c4bf2
+// [tool=go,type=link]=ca87d4f0
+// [compiler=protoc,lang=proto,type=compile]=00588a26
 // [type=tool]=8ea55c85
 func ToDigest(l map[string]string) (string, error) {
 	labelsKey := ToKey(l)
@@ -0,0 +1,310 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package localcas implements an on-disk content addressable store of action outputs, from which
+// outputs with the same contents are materialized without downloading or storing them again.
+package localcas
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bazelbuild/reclient/internal/pkg/diskcache"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/digest"
+
+	log "github.com/golang/glog"
+)
+
+const (
+	// dirName is the name of the directory of the CAS under the cache directory of reproxy.
+	dirName = "reproxy.cas"
+	// tmpPrefix is the prefix of the files of blobs being written.
+	tmpPrefix = "tmp-"
+	// execSuffix is the suffix of the names of executable blobs. Hardlinks share the mode of
+	// the blob, so executable and non-executable outputs with the same contents are stored as
+	// separate blobs.
+	execSuffix = ".x"
+
+	regularMode    = 0644
+	executableMode = 0755
+	// readOnly are the permission bits removed from blobs, so that outputs hardlinked to them are
+	// not written in place.
+	readOnly = 0222
+)
+
+// CAS is an on-disk content addressable store of output files. Outputs are materialized from the
+// CAS as clones (reflinks) of its blobs where the file system supports it, or as copies. With
+// hardlinks enabled, outputs are hardlinks to the blobs instead, so that outputs with the same
+// contents share disk space. Once the total size of the blobs exceeds the capacity of the CAS, the
+// least recently used blobs are garbage collected.
+//
+// The modification time of a blob is never changed by the CAS, since it is shared by all outputs
+// hardlinked to it. Recency of use is tracked in memory instead, and an output is only hardlinked to
+// a blob modified after the time it must be newer than. Blobs are read-only, and a blob whose size
+// or modification time differ from when it was added is considered modified and removed.
+type CAS struct {
+	dir       string
+	hardlinks bool
+
+	mu  sync.Mutex
+	lru *diskcache.LRU
+}
+
+// New creates a CAS in cacheDir holding at most maxBytes, loading the blobs already stored in
+// cacheDir.
+func New(cacheDir string, maxBytes int64, hardlinks bool) (*CAS, error) {
+	dir := filepath.Join(cacheDir, dirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create CAS directory %v: %v", dir, err)
+	}
+	var loaded []*diskcache.Item
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), tmpPrefix) {
+			// Leftovers of writes interrupted by a shutdown.
+			os.Remove(path)
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		loaded = append(loaded, &diskcache.Item{Name: d.Name(), Size: fi.Size(), ModTime: fi.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CAS directory %v: %v", dir, err)
+	}
+	c := &CAS{dir: dir, hardlinks: hardlinks}
+	c.lru = diskcache.NewLRU(maxBytes, c.removeBlob)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Load(loaded)
+	log.Infof("Loaded %v blobs of %v bytes from CAS %v", c.lru.Len(), c.lru.Size(), dir)
+	return c, nil
+}
+
+// Hardlinks returns whether outputs are materialized as hardlinks to the blobs of c.
+func (c *CAS) Hardlinks() bool {
+	return c != nil && c.hardlinks
+}
+
+func blobName(dg digest.Digest, executable bool) string {
+	name := dg.Hash + "_" + strconv.FormatInt(dg.Size, 10)
+	if executable {
+		name += execSuffix
+	}
+	return name
+}
+
+func (c *CAS) blobPath(name string) string {
+	return filepath.Join(c.dir, name[:2], name)
+}
+
+// use marks the blob as recently used and returns its file info if it is in the CAS and was not
+// modified since it was added. Modified blobs are removed.
+func (c *CAS) use(name string) (fs.FileInfo, error) {
+	c.mu.Lock()
+	it := c.lru.Use(name)
+	c.mu.Unlock()
+	if it == nil {
+		return nil, nil
+	}
+	fi, err := os.Stat(c.blobPath(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		// Removed by something other than the CAS.
+		c.remove(name)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() != it.Size || !fi.ModTime().Equal(it.ModTime) {
+		log.Warningf("CAS blob %v was modified since it was added, removing it", name)
+		c.remove(name)
+		return nil, nil
+	}
+	return fi, nil
+}
+
+// Materialize creates the output file at path with the contents of the blob with digest dg, and
+// returns whether the blob was in the CAS. The output is modified after notBefore.
+func (c *CAS) Materialize(dg digest.Digest, executable bool, path string, notBefore time.Time) (bool, error) {
+	if c == nil {
+		return false, nil
+	}
+	name := blobName(dg, executable)
+	fi, err := c.use(name)
+	if fi == nil || err != nil {
+		return false, err
+	}
+	src := c.blobPath(name)
+	if err := diskcache.PrepareDst(path); err != nil {
+		return false, err
+	}
+	// Hardlinks share the modification time of the blob, so an output which must be newer than
+	// the blob is cloned instead.
+	if c.hardlinks && fi.ModTime().After(notBefore) {
+		if err := os.Link(src, path); err == nil {
+			return true, nil
+		}
+		// Fall back to cloning, e.g. if the output is on another device.
+	}
+	mode := os.FileMode(regularMode)
+	if executable {
+		mode = executableMode
+	}
+	if err := cloneFile(src, path, mode); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Add stores the contents of the output file at path, with digest dg, in the CAS. With hardlinks
+// enabled, an output with the same contents as an existing blob modified after notBefore is
+// replaced by a hardlink to the blob.
+func (c *CAS) Add(path string, dg digest.Digest, executable bool, notBefore time.Time) error {
+	if c == nil {
+		return nil
+	}
+	name := blobName(dg, executable)
+	dst := c.blobPath(name)
+	fi, err := c.use(name)
+	if err != nil {
+		return err
+	}
+	if fi != nil {
+		if !c.hardlinks || !fi.ModTime().After(notBefore) {
+			// The output keeps its own, more recent, modification time.
+			return nil
+		}
+		return replaceWithLink(dst, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	mode := os.FileMode(regularMode)
+	if executable {
+		mode = executableMode
+	}
+	if c.hardlinks {
+		if err := os.Link(path, dst); err != nil && !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+		if err := os.Chmod(dst, mode&^readOnly); err != nil {
+			return err
+		}
+	} else {
+		f, err := os.CreateTemp(filepath.Dir(dst), tmpPrefix)
+		if err != nil {
+			return err
+		}
+		f.Close()
+		tmp := f.Name()
+		if err := cloneFile(path, tmp, mode); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+		// The mode of an existing file is not changed when opening it.
+		if err := os.Chmod(tmp, mode&^readOnly); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+		if err := os.Rename(tmp, dst); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	fi, err = os.Stat(dst)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Add(&diskcache.Item{Name: name, Size: fi.Size(), ModTime: fi.ModTime()})
+	return nil
+}
+
+// Unshare replaces the output file at path, with digest dg, by a copy if it is a hardlink to a
+// blob, so that writing the output in place doesn't modify the blob.
+func (c *CAS) Unshare(path string, dg digest.Digest, executable bool) error {
+	if c == nil || !c.hardlinks {
+		return nil
+	}
+	ofi, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	bfi, err := os.Stat(c.blobPath(blobName(dg, executable)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !os.SameFile(ofi, bfi) {
+		return nil
+	}
+	tmp := filepath.Join(filepath.Dir(path), tmpPrefix+filepath.Base(path))
+	// The copy is writable, unlike the blob.
+	if err := diskcache.CopyFile(path, tmp, ofi.Mode().Perm()|0200); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chtimes(tmp, ofi.ModTime(), ofi.ModTime()); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (c *CAS) remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Remove(name)
+}
+
+// removeBlob removes a blob evicted from the CAS. Outputs hardlinked to removed blobs keep their
+// contents.
+func (c *CAS) removeBlob(name string) {
+	if err := os.Remove(c.blobPath(name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Warningf("Failed to remove CAS blob %v: %v", name, err)
+	}
+}
+
+// replaceWithLink atomically replaces the file at path by a hardlink to src.
+func replaceWithLink(src, path string) error {
+	tmp := filepath.Join(filepath.Dir(path), tmpPrefix+filepath.Base(path))
+	os.Remove(tmp)
+	if err := os.Link(src, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
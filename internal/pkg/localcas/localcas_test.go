@@ -0,0 +1,267 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localcas
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/digest"
+)
+
+func writeFile(t *testing.T, path, content string) digest.Digest {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll(%v) failed: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile(%v) failed: %v", path, err)
+	}
+	return digest.NewFromBlob([]byte(content))
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%v) failed: %v", path, err)
+	}
+	return string(blob)
+}
+
+func sameFile(t *testing.T, a, b string) bool {
+	t.Helper()
+	afi, err := os.Stat(a)
+	if err != nil {
+		t.Fatalf("Stat(%v) failed: %v", a, err)
+	}
+	bfi, err := os.Stat(b)
+	if err != nil {
+		t.Fatalf("Stat(%v) failed: %v", b, err)
+	}
+	return os.SameFile(afi, bfi)
+}
+
+func TestAddMaterialize(t *testing.T) {
+	for _, hardlinks := range []bool{false, true} {
+		c, err := New(t.TempDir(), 1<<20, hardlinks)
+		if err != nil {
+			t.Fatalf("New(hardlinks=%v) failed: %v", hardlinks, err)
+		}
+		out := t.TempDir()
+		dg := writeFile(t, filepath.Join(out, "a.o"), "object")
+		if ok, err := c.Materialize(dg, false, filepath.Join(out, "b.o"), time.Time{}); ok || err != nil {
+			t.Errorf("Materialize(hardlinks=%v) before Add = %v, %v, want false, nil", hardlinks, ok, err)
+		}
+		if err := c.Add(filepath.Join(out, "a.o"), dg, false, time.Time{}); err != nil {
+			t.Fatalf("Add(hardlinks=%v) failed: %v", hardlinks, err)
+		}
+		if ok, err := c.Materialize(dg, true, filepath.Join(out, "b.o"), time.Time{}); ok || err != nil {
+			t.Errorf("Materialize(hardlinks=%v) of executable = %v, %v, want false, nil", hardlinks, ok, err)
+		}
+		writeFile(t, filepath.Join(out, "gen", "b.o"), "stale")
+		if ok, err := c.Materialize(dg, false, filepath.Join(out, "gen", "b.o"), time.Time{}); !ok || err != nil {
+			t.Fatalf("Materialize(hardlinks=%v) = %v, %v, want true, nil", hardlinks, ok, err)
+		}
+		if got := readFile(t, filepath.Join(out, "gen", "b.o")); got != "object" {
+			t.Errorf("Materialize(hardlinks=%v) created file with contents %q, want %q", hardlinks, got, "object")
+		}
+		if got := sameFile(t, filepath.Join(out, "a.o"), filepath.Join(out, "gen", "b.o")); got != hardlinks {
+			t.Errorf("Materialize(hardlinks=%v) created a hardlink = %v, want %v", hardlinks, got, hardlinks)
+		}
+	}
+}
+
+func TestAddDeduplicatesWithHardlinks(t *testing.T) {
+	c, err := New(t.TempDir(), 1<<20, true)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	out := t.TempDir()
+	dg := writeFile(t, filepath.Join(out, "a.o"), "object")
+	writeFile(t, filepath.Join(out, "b.o"), "object")
+	for _, f := range []string{"a.o", "b.o"} {
+		if err := c.Add(filepath.Join(out, f), dg, false, time.Time{}); err != nil {
+			t.Fatalf("Add(%v) failed: %v", f, err)
+		}
+	}
+	if !sameFile(t, filepath.Join(out, "a.o"), filepath.Join(out, "b.o")) {
+		t.Errorf("Add() did not replace an output with the same contents as a blob by a hardlink")
+	}
+}
+
+func TestHardlinksKeepBlobMtime(t *testing.T) {
+	c, err := New(t.TempDir(), 1<<20, true)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	out := t.TempDir()
+	a := filepath.Join(out, "a.o")
+	dg := writeFile(t, a, "object")
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(a, old, old); err != nil {
+		t.Fatalf("Chtimes(%v) failed: %v", a, err)
+	}
+	if err := c.Add(a, dg, false, time.Time{}); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	// The blob is older than the output must be, so the output is cloned.
+	notBefore := time.Now().Add(-time.Minute)
+	b := filepath.Join(out, "b.o")
+	if ok, err := c.Materialize(dg, false, b, notBefore); !ok || err != nil {
+		t.Fatalf("Materialize() = %v, %v, want true, nil", ok, err)
+	}
+	if sameFile(t, a, b) {
+		t.Errorf("Materialize() hardlinked an output to a blob older than %v", notBefore)
+	}
+	cOut := filepath.Join(out, "c.o")
+	writeFile(t, cOut, "object")
+	if err := c.Add(cOut, dg, false, notBefore); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if sameFile(t, a, cOut) {
+		t.Errorf("Add() replaced an output by a hardlink to a blob older than %v", notBefore)
+	}
+	fi, err := os.Stat(a)
+	if err != nil {
+		t.Fatalf("Stat(%v) failed: %v", a, err)
+	}
+	if !fi.ModTime().Equal(old) {
+		t.Errorf("Modification time of an output hardlinked to a blob = %v, want %v", fi.ModTime(), old)
+	}
+}
+
+func TestUnshare(t *testing.T) {
+	c, err := New(t.TempDir(), 1<<20, true)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	out := t.TempDir()
+	path := filepath.Join(out, "a.o")
+	dg := writeFile(t, path, "object")
+	if err := c.Add(path, dg, false, time.Time{}); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if err := c.Unshare(path, dg, false); err != nil {
+		t.Fatalf("Unshare() failed: %v", err)
+	}
+	// Writing the output in place must not modify the blob.
+	writeFile(t, path, "modified")
+	if ok, err := c.Materialize(dg, false, filepath.Join(out, "b.o"), time.Time{}); !ok || err != nil {
+		t.Fatalf("Materialize() = %v, %v, want true, nil", ok, err)
+	}
+	if got := readFile(t, filepath.Join(out, "b.o")); got != "object" {
+		t.Errorf("Materialize() after Unshare() created file with contents %q, want %q", got, "object")
+	}
+}
+
+func TestBlobsAreReadOnly(t *testing.T) {
+	for _, hardlinks := range []bool{false, true} {
+		c, err := New(t.TempDir(), 1<<20, hardlinks)
+		if err != nil {
+			t.Fatalf("New(hardlinks=%v) failed: %v", hardlinks, err)
+		}
+		path := filepath.Join(t.TempDir(), "a.o")
+		dg := writeFile(t, path, "object")
+		if err := c.Add(path, dg, true, time.Time{}); err != nil {
+			t.Fatalf("Add(hardlinks=%v) failed: %v", hardlinks, err)
+		}
+		fi, err := os.Stat(c.blobPath(blobName(dg, true)))
+		if err != nil {
+			t.Fatalf("Stat() of blob failed: %v", err)
+		}
+		if got, want := fi.Mode().Perm(), os.FileMode(0555); got != want {
+			t.Errorf("Add(hardlinks=%v) created blob with mode %v, want %v", hardlinks, got, want)
+		}
+	}
+}
+
+func TestMaterializeDropsModifiedBlob(t *testing.T) {
+	c, err := New(t.TempDir(), 1<<20, true)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	out := t.TempDir()
+	path := filepath.Join(out, "a.o")
+	dg := writeFile(t, path, "object")
+	if err := c.Add(path, dg, false, time.Time{}); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	// Writing the output in place without unsharing it modifies the blob.
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatalf("Chmod(%v) failed: %v", path, err)
+	}
+	writeFile(t, path, "modified object")
+	if ok, err := c.Materialize(dg, false, filepath.Join(out, "b.o"), time.Time{}); ok || err != nil {
+		t.Errorf("Materialize() of modified blob = %v, %v, want false, nil", ok, err)
+	}
+	if n := c.lru.Len(); n != 0 {
+		t.Errorf("CAS holds %v blobs after Materialize() of modified blob, want 0", n)
+	}
+}
+
+func TestGC(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, 10, false)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	out := t.TempDir()
+	var dgs []digest.Digest
+	for _, content := range []string{"aaaa", "bbbb", "cccc"} {
+		path := filepath.Join(out, content)
+		dg := writeFile(t, path, content)
+		if err := c.Add(path, dg, false, time.Time{}); err != nil {
+			t.Fatalf("Add(%v) failed: %v", content, err)
+		}
+		dgs = append(dgs, dg)
+		if content == "bbbb" {
+			// Make the first blob the most recently used one.
+			if ok, _ := c.Materialize(dgs[0], false, filepath.Join(out, "copy"), time.Time{}); !ok {
+				t.Fatalf("Materialize(aaaa) = false, want true")
+			}
+		}
+	}
+	for i, want := range []bool{true, false, true} {
+		if ok, _ := c.Materialize(dgs[i], false, filepath.Join(out, "copy"), time.Time{}); ok != want {
+			t.Errorf("Materialize(%v) = %v, want %v", i, ok, want)
+		}
+	}
+	// Blobs are loaded from disk, removing blobs over the capacity.
+	c, err = New(dir, 4, false)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if n := c.lru.Len(); n != 1 {
+		t.Errorf("New() loaded %v blobs, want 1", n)
+	}
+}
+
+func TestNilCAS(t *testing.T) {
+	var c *CAS
+	path := filepath.Join(t.TempDir(), "a.o")
+	dg := writeFile(t, path, "object")
+	if err := c.Add(path, dg, false, time.Time{}); err != nil {
+		t.Errorf("Add() on nil CAS failed: %v", err)
+	}
+	if ok, err := c.Materialize(dg, false, path, time.Time{}); ok || err != nil {
+		t.Errorf("Materialize() on nil CAS = %v, %v, want false, nil", ok, err)
+	}
+	if err := c.Unshare(path, dg, false); err != nil {
+		t.Errorf("Unshare() on nil CAS failed: %v", err)
+	}
+}
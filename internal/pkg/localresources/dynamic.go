@@ -0,0 +1,121 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localresources
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"time"
+
+	log "github.com/golang/glog"
+)
+
+const (
+	// CapacityCPUs is the name of the stat of the number of CPUs managed by a dynamic Manager.
+	CapacityCPUs = "LOCAL_CAPACITY_cpus"
+	// CapacityRAM is the name of the stat of the RAM in MBs managed by a dynamic Manager.
+	CapacityRAM = "LOCAL_CAPACITY_RAM_mbs"
+
+	// ramStepMBs is the granularity of the RAM capacity of a dynamic Manager, so that small
+	// fluctuations of the available memory don't change the capacity.
+	ramStepMBs = 256
+)
+
+// LoadSampler samples the system-wide load of the machine.
+type LoadSampler interface {
+	// CPUPercent returns the system-wide CPU utilization in percent since the previous call.
+	CPUPercent() (float64, error)
+	// AvailableRAMMBs returns the amount of memory available to new processes in MBs.
+	AvailableRAMMBs() (int64, error)
+}
+
+// DynamicBounds are the bounds of the capacity of a dynamic Manager.
+type DynamicBounds struct {
+	MinCPUs   int64
+	MaxCPUs   int64
+	MinRAMMBs int64
+	MaxRAMMBs int64
+}
+
+// FractionalBounds returns bounds between the given fractions of the machine's CPUs and RAM.
+func FractionalBounds(minFraction, maxFraction float64) DynamicBounds {
+	numCPU := float64(runtime.NumCPU())
+	ramMBs := float64(TotalRAMMBs())
+	return DynamicBounds{
+		MinCPUs:   max(1, int64(numCPU*minFraction)),
+		MaxCPUs:   max(1, int64(numCPU*maxFraction)),
+		MinRAMMBs: max(1, int64(ramMBs*minFraction)),
+		MaxRAMMBs: max(1, int64(ramMBs*maxFraction)),
+	}
+}
+
+// RunDynamic resizes m every interval to the resources of the machine that are not used by other
+// processes, within the given bounds, until ctx is done. onChange, if not nil, is called with
+// every new capacity.
+func (m *Manager) RunDynamic(ctx context.Context, interval time.Duration, b DynamicBounds, s LoadSampler, onChange func(cpus, ramMBs int64)) {
+	if m == nil {
+		return
+	}
+	numCPU := float64(runtime.NumCPU())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	// The first CPU sample covers the time since boot.
+	s.CPUPercent()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cpuPct, err := s.CPUPercent()
+		if err != nil {
+			log.Warningf("Failed to sample system CPU utilization: %v", err)
+			continue
+		}
+		availRAM, err := s.AvailableRAMMBs()
+		if err != nil {
+			log.Warningf("Failed to sample available system memory: %v", err)
+			continue
+		}
+		cpus, ramMBs := m.dynamicCapacity(numCPU, cpuPct, availRAM, b)
+		curCPUs, curRAMMBs := m.Capacity()
+		if cpus == curCPUs && ramMBs == curRAMMBs {
+			continue
+		}
+		log.V(1).Infof("Resizing local resources from %v CPUs and %v MB RAM to %v CPUs and %v MB RAM", curCPUs, curRAMMBs, cpus, ramMBs)
+		m.SetCapacity(cpus, ramMBs)
+		if onChange != nil {
+			onChange(cpus, ramMBs)
+		}
+	}
+}
+
+// dynamicCapacity returns the capacity of m given the sampled system-wide load. The resources
+// reserved by m are assumed to be in use by its own requests rather than by other processes.
+func (m *Manager) dynamicCapacity(numCPU, cpuPct float64, availRAMMBs int64, b DynamicBounds) (int64, int64) {
+	m.mu.Lock()
+	reservedCPUs := m.totalCPUs - m.freeCPUs
+	reservedRAMMBs := m.totalRAMMBs - m.freeRAMMBs
+	m.mu.Unlock()
+	otherCPUs := math.Max(0, numCPU*cpuPct/100-float64(reservedCPUs))
+	cpus := int64(math.Round(numCPU - otherCPUs))
+	ramMBs := (availRAMMBs + reservedRAMMBs) / ramStepMBs * ramStepMBs
+	return clamp(cpus, b.MinCPUs, b.MaxCPUs), clamp(ramMBs, b.MinRAMMBs, b.MaxRAMMBs)
+}
+
+func clamp(v, lo, hi int64) int64 {
+	return max(lo, min(v, hi))
+}
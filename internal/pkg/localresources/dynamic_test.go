@@ -0,0 +1,182 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localresources
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeLoadSampler struct {
+	cpuPct   float64
+	availRAM int64
+}
+
+func (s *fakeLoadSampler) CPUPercent() (float64, error) {
+	return s.cpuPct, nil
+}
+
+func (s *fakeLoadSampler) AvailableRAMMBs() (int64, error) {
+	return s.availRAM, nil
+}
+
+func TestSetCapacity_Grow(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(1, 512)
+	rel, err := m.Lock(ctx, 1, 512)
+	if err != nil {
+		t.Fatalf("Lock(%v, %v) returned err: %v", 1, 512, err)
+	}
+	defer rel()
+	granted := make(chan struct{})
+	go func() {
+		rel, err := m.Lock(ctx, 1, 512)
+		if err != nil {
+			t.Errorf("Lock(%v, %v) returned err: %v", 1, 512, err)
+			return
+		}
+		close(granted)
+		rel()
+	}()
+	waitForWaiters(t, m, 1)
+	m.SetCapacity(2, 1024)
+	select {
+	case <-granted:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Lock(%v, %v) was not granted after the capacity grew", 1, 512)
+	}
+}
+
+func TestSetCapacity_Shrink(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(4, 2048)
+	rel, err := m.Lock(ctx, 2, 1024)
+	if err != nil {
+		t.Fatalf("Lock(%v, %v) returned err: %v", 2, 1024, err)
+	}
+	m.SetCapacity(2, 1024)
+	granted := make(chan struct{})
+	go func() {
+		// Capped to the new capacity, granted once the running request is released.
+		rel, err := m.Lock(ctx, 4, 2048)
+		if err != nil {
+			t.Errorf("Lock(%v, %v) returned err: %v", 4, 2048, err)
+			return
+		}
+		close(granted)
+		rel()
+	}()
+	waitForWaiters(t, m, 1)
+	rel()
+	select {
+	case <-granted:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Lock(%v, %v) was not granted after resources were released", 4, 2048)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.freeCPUs != 2 || m.freeRAMMBs != 1024 {
+		t.Errorf("Free resources after all requests were released = %v CPUs, %v MB RAM, want 2 CPUs, 1024 MB RAM", m.freeCPUs, m.freeRAMMBs)
+	}
+}
+
+func TestDynamicCapacity(t *testing.T) {
+	b := DynamicBounds{MinCPUs: 2, MaxCPUs: 6, MinRAMMBs: 1024, MaxRAMMBs: 8192}
+	tests := []struct {
+		name        string
+		reserved    int64
+		reservedRAM int64
+		cpuPct      float64
+		availRAM    int64
+		wantCPUs    int64
+		wantRAMMBs  int64
+	}{
+		{
+			name:       "idle",
+			cpuPct:     0,
+			availRAM:   16384,
+			wantCPUs:   6,
+			wantRAMMBs: 8192,
+		},
+		{
+			name:       "busy",
+			cpuPct:     100,
+			availRAM:   100,
+			wantCPUs:   2,
+			wantRAMMBs: 1024,
+		},
+		{
+			name:       "partially loaded",
+			cpuPct:     50,
+			availRAM:   3000,
+			wantCPUs:   4,
+			wantRAMMBs: 2816,
+		},
+		{
+			name:        "load from own requests",
+			reserved:    4,
+			reservedRAM: 2048,
+			cpuPct:      50,
+			availRAM:    1000,
+			wantCPUs:    6,
+			wantRAMMBs:  2816,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewManager(8, 8192)
+			m.freeCPUs -= tc.reserved
+			m.freeRAMMBs -= tc.reservedRAM
+			cpus, ramMBs := m.dynamicCapacity(8, tc.cpuPct, tc.availRAM, b)
+			if cpus != tc.wantCPUs || ramMBs != tc.wantRAMMBs {
+				t.Errorf("dynamicCapacity() = %v, %v, want %v, %v", cpus, ramMBs, tc.wantCPUs, tc.wantRAMMBs)
+			}
+		})
+	}
+}
+
+func TestRunDynamic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewManager(1, 512)
+	b := DynamicBounds{MinCPUs: 1, MaxCPUs: 1, MinRAMMBs: 1024, MaxRAMMBs: 1024}
+	type capacity struct{ cpus, ramMBs int64 }
+	changes := make(chan capacity, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.RunDynamic(ctx, 10*time.Millisecond, b, &fakeLoadSampler{availRAM: 4096}, func(cpus, ramMBs int64) {
+			select {
+			case changes <- capacity{cpus, ramMBs}:
+			default:
+			}
+		})
+	}()
+	select {
+	case got := <-changes:
+		if want := (capacity{1, 1024}); got != want {
+			t.Errorf("RunDynamic() changed capacity to %+v, want %+v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("RunDynamic() did not change the capacity")
+	}
+	if cpus, ramMBs := m.Capacity(); cpus != 1 || ramMBs != 1024 {
+		t.Errorf("Capacity() = %v, %v, want 1, 1024", cpus, ramMBs)
+	}
+	cancel()
+	<-done
+}
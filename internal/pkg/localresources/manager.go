@@ -18,21 +18,38 @@ package localresources
 import (
 	"context"
 	"runtime"
-
-	"golang.org/x/sync/semaphore"
+	"sync"
+	"time"
 
 	log "github.com/golang/glog"
 )
 
-// Manager is the manager of local resources.
+// DefaultPriorityAgingPeriod is the default amount of time a request waits for resources before
+// its priority is raised by one, so that low priority requests are not starved.
+const DefaultPriorityAgingPeriod = 10 * time.Second
+
+// Manager is the manager of local resources. Resources are granted to waiting requests in order
+// of priority, and in order of arrival among requests of the same priority.
 type Manager struct {
-	cpus *semaphore.Weighted
-	ram  *semaphore.Weighted
+	mu          sync.Mutex
+	freeCPUs    int64
+	freeRAMMBs  int64
+	waiters     []*waiter
+	agingPeriod time.Duration
 
 	totalCPUs   int64
 	totalRAMMBs int64
 }
 
+type waiter struct {
+	cpus     int64
+	ramMBs   int64
+	priority int32
+	enqueued time.Time
+	// ready is closed once the requested resources are granted.
+	ready chan struct{}
+}
+
 // NewDefaultManager retrieves a Manager with default local resources.
 func NewDefaultManager() *Manager {
 	numCPU := int64(runtime.NumCPU())
@@ -51,8 +68,9 @@ func NewFractionalDefaultManager(fraction float64) *Manager {
 // NewManager is used to initialize the manager with non default local resources.
 func NewManager(cpus, ramMBs int64) *Manager {
 	return &Manager{
-		cpus:        semaphore.NewWeighted(max(1, cpus)),
-		ram:         semaphore.NewWeighted(max(1, ramMBs)),
+		freeCPUs:    max(1, cpus),
+		freeRAMMBs:  max(1, ramMBs),
+		agingPeriod: DefaultPriorityAgingPeriod,
 		totalCPUs:   max(1, cpus),
 		totalRAMMBs: max(1, ramMBs),
 	}
@@ -60,25 +78,124 @@ func NewManager(cpus, ramMBs int64) *Manager {
 
 // Lock locks the desired resources and returns a function to release them.
 func (m *Manager) Lock(ctx context.Context, cpus, ramMBs int64) (func(), error) {
+	return m.LockWithPriority(ctx, 0, cpus, ramMBs)
+}
+
+// LockWithPriority locks the desired resources ahead of waiting requests with a lower priority
+// and returns a function to release them. The priority of a request is raised by one for every
+// aging period it waits.
+func (m *Manager) LockWithPriority(ctx context.Context, priority int32, cpus, ramMBs int64) (func(), error) {
 	if m == nil {
 		return func() {}, nil
 	}
+	w := &waiter{cpus: cpus, ramMBs: ramMBs, priority: priority, enqueued: time.Now(), ready: make(chan struct{})}
+	// The requested resources may be capped while waiting if the capacity shrinks, so the
+	// resources of the waiter are released rather than the requested ones.
+	release := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.freeCPUs += w.cpus
+		m.freeRAMMBs += w.ramMBs
+		m.grantLocked()
+	}
+	m.mu.Lock()
 	if cpus > m.totalCPUs || ramMBs > m.totalRAMMBs {
 		log.Warningf("Capping request to available system resources, cpu-max=%v(req=%v), ramMBs-max=%v(req=%v)", m.totalCPUs, cpus, m.totalRAMMBs, ramMBs)
-		cpus = min(cpus, m.totalCPUs)
-		ramMBs = min(ramMBs, m.totalRAMMBs)
+		w.cpus = min(cpus, m.totalCPUs)
+		w.ramMBs = min(ramMBs, m.totalRAMMBs)
+	}
+	if len(m.waiters) == 0 && w.cpus <= m.freeCPUs && w.ramMBs <= m.freeRAMMBs {
+		m.freeCPUs -= w.cpus
+		m.freeRAMMBs -= w.ramMBs
+		m.mu.Unlock()
+		return release, nil
+	}
+	m.waiters = append(m.waiters, w)
+	// The new waiter may take precedence over waiters that don't fit in the free resources.
+	m.grantLocked()
+	m.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return release, nil
+	case <-ctx.Done():
+		m.mu.Lock()
+		select {
+		case <-w.ready:
+			// The resources were granted concurrently with the cancelation.
+			m.mu.Unlock()
+			release()
+		default:
+			m.removeLocked(w)
+			// Removing the waiter may allow the next one to proceed.
+			m.grantLocked()
+			m.mu.Unlock()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Capacity returns the total CPUs and RAM in MBs currently managed by m.
+func (m *Manager) Capacity() (int64, int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.totalCPUs, m.totalRAMMBs
+}
+
+// SetCapacity changes the total CPUs and RAM in MBs managed by m. Resources held by running
+// requests are not revoked if the capacity shrinks, but no more requests are granted until
+// enough of them are released.
+func (m *Manager) SetCapacity(cpus, ramMBs int64) {
+	cpus, ramMBs = max(1, cpus), max(1, ramMBs)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.freeCPUs += cpus - m.totalCPUs
+	m.freeRAMMBs += ramMBs - m.totalRAMMBs
+	m.totalCPUs, m.totalRAMMBs = cpus, ramMBs
+	// Waiters requesting more than the new capacity would never be granted.
+	for _, w := range m.waiters {
+		w.cpus = min(w.cpus, cpus)
+		w.ramMBs = min(w.ramMBs, ramMBs)
 	}
-	if err := m.cpus.Acquire(ctx, cpus); err != nil {
-		return nil, err
+	m.grantLocked()
+}
+
+// grantLocked grants resources to waiters in order of their effective priority until the next
+// waiter does not fit in the free resources. Must be called with mu held.
+func (m *Manager) grantLocked() {
+	now := time.Now()
+	for len(m.waiters) > 0 {
+		next := m.waiters[0]
+		for _, w := range m.waiters[1:] {
+			if m.effectivePriority(w, now) > m.effectivePriority(next, now) {
+				next = w
+			}
+		}
+		if next.cpus > m.freeCPUs || next.ramMBs > m.freeRAMMBs {
+			return
+		}
+		m.freeCPUs -= next.cpus
+		m.freeRAMMBs -= next.ramMBs
+		m.removeLocked(next)
+		close(next.ready)
 	}
-	if err := m.ram.Acquire(ctx, ramMBs); err != nil {
-		m.cpus.Release(cpus)
-		return nil, err
+}
+
+func (m *Manager) effectivePriority(w *waiter, now time.Time) int64 {
+	p := int64(w.priority)
+	if m.agingPeriod > 0 {
+		p += int64(now.Sub(w.enqueued) / m.agingPeriod)
+	}
+	return p
+}
+
+func (m *Manager) removeLocked(w *waiter) {
+	for i, o := range m.waiters {
+		if o == w {
+			m.waiters = append(m.waiters[:i], m.waiters[i+1:]...)
+			return
+		}
 	}
-	return func() {
-		m.cpus.Release(cpus)
-		m.ram.Release(ramMBs)
-	}, nil
 }
 
 func min(a int64, b int64) int64 {
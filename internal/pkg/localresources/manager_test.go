@@ -65,3 +65,136 @@ func TestMinimumCpus(t *testing.T) {
 		t.Errorf("Expected 1 totalCPUs, got %v", mgr.totalCPUs)
 	}
 }
+
+// waitForWaiters waits until the manager has n waiting requests.
+func waitForWaiters(t *testing.T, m *Manager, n int) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		m.mu.Lock()
+		got := len(m.waiters)
+		m.mu.Unlock()
+		if got == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Manager did not reach %v waiters", n)
+}
+
+func TestLockWithPriority_Order(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(1, 512)
+	m.agingPeriod = 0
+	rel, err := m.Lock(ctx, 1, 512)
+	if err != nil {
+		t.Fatalf("Lock(%v, %v) returned err: %v", 1, 512, err)
+	}
+	var mu sync.Mutex
+	var got []int32
+	wg := &sync.WaitGroup{}
+	for i, p := range []int32{0, 10, 5} {
+		wg.Add(1)
+		go func(p int32) {
+			defer wg.Done()
+			rel, err := m.LockWithPriority(ctx, p, 1, 512)
+			if err != nil {
+				t.Errorf("LockWithPriority(%v) returned err: %v", p, err)
+				return
+			}
+			mu.Lock()
+			got = append(got, p)
+			mu.Unlock()
+			rel()
+		}(p)
+		waitForWaiters(t, m, i+1)
+	}
+	rel()
+	wg.Wait()
+	want := []int32{10, 5, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Resources granted in priority order %v, want %v", got, want)
+		}
+	}
+}
+
+func TestLockWithPriority_Aging(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(1, 512)
+	m.agingPeriod = 50 * time.Millisecond
+	rel, err := m.Lock(ctx, 1, 512)
+	if err != nil {
+		t.Fatalf("Lock(%v, %v) returned err: %v", 1, 512, err)
+	}
+	lowGranted := make(chan struct{})
+	go func() {
+		rel, err := m.LockWithPriority(ctx, 0, 1, 512)
+		if err != nil {
+			t.Errorf("LockWithPriority(0) returned err: %v", err)
+			return
+		}
+		close(lowGranted)
+		rel()
+	}()
+	waitForWaiters(t, m, 1)
+	// Age the low priority request past a priority of 1.
+	time.Sleep(200 * time.Millisecond)
+	highGranted := make(chan struct{})
+	go func() {
+		rel, err := m.LockWithPriority(ctx, 1, 1, 512)
+		if err != nil {
+			t.Errorf("LockWithPriority(1) returned err: %v", err)
+			return
+		}
+		close(highGranted)
+		rel()
+	}()
+	waitForWaiters(t, m, 2)
+	rel()
+	select {
+	case <-lowGranted:
+	case <-highGranted:
+		t.Errorf("LockWithPriority(1) was granted before the aged LockWithPriority(0)")
+	}
+	<-lowGranted
+	<-highGranted
+}
+
+func TestLockWithPriority_CanceledWaiterUnblocksOthers(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(2, 512)
+	m.agingPeriod = 0
+	rel, err := m.Lock(ctx, 1, 256)
+	if err != nil {
+		t.Fatalf("Lock(%v, %v) returned err: %v", 1, 256, err)
+	}
+	defer rel()
+	cCtx, cancel := context.WithCancel(ctx)
+	errCh := make(chan error)
+	go func() {
+		// Doesn't fit until rel is called, blocking lower priority requests.
+		_, err := m.LockWithPriority(cCtx, 10, 2, 256)
+		errCh <- err
+	}()
+	waitForWaiters(t, m, 1)
+	granted := make(chan struct{})
+	go func() {
+		rel, err := m.LockWithPriority(ctx, 0, 1, 256)
+		if err != nil {
+			t.Errorf("LockWithPriority(0) returned err: %v", err)
+			return
+		}
+		close(granted)
+		rel()
+	}()
+	waitForWaiters(t, m, 2)
+	cancel()
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Errorf("LockWithPriority(10) returned err: %v, want context canceled", err)
+	}
+	select {
+	case <-granted:
+	case <-time.After(5 * time.Second):
+		t.Errorf("LockWithPriority(0) was not granted after the blocking request was canceled")
+	}
+}
@@ -1,10 +1,13 @@
 package usage
 
 import (
+	"fmt"
 	"os"
 	"time"
 
 	log "github.com/golang/glog"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/mem"
 	"github.com/shirou/gopsutil/v3/process"
 )
 
@@ -72,3 +75,27 @@ func Sample(uSampler Sampler) map[string]int64 {
 	res[MemRes] = int64(mInfo.RSS / mb)
 	return res
 }
+
+// SystemSampler samples the system-wide CPU and memory usage of the machine.
+type SystemSampler struct{}
+
+// CPUPercent returns the system-wide CPU utilization in percent since the previous call.
+func (SystemSampler) CPUPercent() (float64, error) {
+	pcts, err := cpu.Percent(0, false)
+	if err != nil {
+		return 0, err
+	}
+	if len(pcts) == 0 {
+		return 0, fmt.Errorf("no CPU utilization reported")
+	}
+	return pcts[0], nil
+}
+
+// AvailableRAMMBs returns the amount of memory available to new processes in MBs.
+func (SystemSampler) AvailableRAMMBs() (int64, error) {
+	vm, err := mem.VirtualMemory()
+	if err != nil {
+		return 0, err
+	}
+	return int64(vm.Available / mb), nil
+}
@@ -88,6 +88,8 @@ type Logger struct {
 	runningActions     int32
 	peakRunningActions int32
 	completedActions   map[lpb.CompletionStatus]int32
+	// invStats are the stats of tracked invocation IDs, aggregated separately from stats.
+	invStats map[string]*stats.Stats
 
 	mu               sync.RWMutex
 	open             bool
@@ -146,6 +148,9 @@ func (e *endActionEvent) apply(l *Logger) {
 	// Process any mismatches to be ignored for this log record.
 	l.mi.ProcessLogRecord(e.lr.LogRecord)
 	l.stats.AddRecord(e.lr.LogRecord)
+	if st, ok := l.invStats[e.lr.GetCommand().GetIdentifiers().GetInvocationId()]; ok {
+		st.AddRecord(e.lr.LogRecord)
+	}
 	e.lr.open = false
 	l.completedActions[e.lr.CompletionStatus]++
 	l.runningActions--
@@ -187,6 +192,32 @@ func (s *summarizeActionsEvent) apply(l *Logger) {
 	}
 }
 
+type trackInvocationEvent struct {
+	invocationID string
+}
+
+func (e *trackInvocationEvent) apply(l *Logger) {
+	if _, ok := l.invStats[e.invocationID]; !ok {
+		l.invStats[e.invocationID] = stats.New()
+	}
+}
+
+type finishInvocationEvent struct {
+	invocationID string
+	out          chan<- *spb.Stats
+}
+
+func (e *finishInvocationEvent) apply(l *Logger) {
+	st, ok := l.invStats[e.invocationID]
+	if !ok {
+		e.out <- nil
+		return
+	}
+	delete(l.invStats, e.invocationID)
+	st.FinalizeAggregate(nil)
+	e.out <- st.ToProto()
+}
+
 // LogRecord wraps proxy.LogRecord while tracking if the command has been ended yet for logging purposes.
 type LogRecord struct {
 	*lpb.LogRecord
@@ -234,15 +265,29 @@ func getCompletionStatus(rec *LogRecord, execStrategy ppb.ExecutionStrategy_Valu
 			}
 			return lpb.CompletionStatus_STATUS_REMOTE_EXECUTION
 		case ppb.ExecutionStrategy_REMOTE_LOCAL_FALLBACK:
+			if rec.GetLocalMetadata().GetLoadShed() {
+				return lpb.CompletionStatus_STATUS_LOAD_SHED
+			}
 			if rec.GetLocalMetadata().GetExecutedLocally() {
 				return lpb.CompletionStatus_STATUS_LOCAL_FALLBACK
 			}
 			return lpb.CompletionStatus_STATUS_REMOTE_EXECUTION
 		case ppb.ExecutionStrategy_RACING:
+			if rec.GetLocalMetadata().GetLoadShed() {
+				return lpb.CompletionStatus_STATUS_LOAD_SHED
+			}
 			if rec.GetLocalMetadata().GetExecutedLocally() {
 				return lpb.CompletionStatus_STATUS_RACING_LOCAL
 			}
 			return lpb.CompletionStatus_STATUS_RACING_REMOTE
+		case ppb.ExecutionStrategy_REMOTE_HEDGED:
+			switch {
+			case rec.GetRemoteMetadata().GetHedgeWon():
+				return lpb.CompletionStatus_STATUS_HEDGED_SECONDARY
+			case rec.GetRemoteMetadata().GetHedged():
+				return lpb.CompletionStatus_STATUS_HEDGED_PRIMARY
+			}
+			return lpb.CompletionStatus_STATUS_REMOTE_EXECUTION
 		}
 	}
 	return lpb.CompletionStatus_STATUS_UNKNOWN
@@ -342,6 +387,7 @@ func newLogger(format Format, recs, info *os.File, s stats.StatCollector, mi *ig
 		e:                e,
 		open:             true,
 		completedActions: make(map[lpb.CompletionStatus]int32),
+		invStats:         make(map[string]*stats.Stats),
 		u:                u,
 	}
 	l.startBackgroundProcess()
@@ -433,9 +479,6 @@ func (l *Logger) collectResourceUsageSamples(samples map[string]int64) {
 	if l == nil {
 		return
 	}
-	if l.resourceUsage == nil {
-		l.resourceUsage = make(map[string][]int64)
-	}
 	// Add current peak running actions num into samples map, so that it get
 	// recorded into reproxy.INFO together with cpu/mem usage data.
 	if samples == nil {
@@ -447,13 +490,28 @@ func (l *Logger) collectResourceUsageSamples(samples map[string]int64) {
 	// of resource usage by a plotter.
 	log.Infof("Resource Usage: %v", samples)
 	delete(samples, unixTime)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.resourceUsage == nil {
+		l.resourceUsage = make(map[string][]int64)
+	}
 	for k, v := range samples {
-		if _, ok := l.resourceUsage[k]; ok {
-			l.resourceUsage[k] = append(l.resourceUsage[k], v)
-		} else {
-			l.resourceUsage[k] = []int64{v}
-		}
+		l.resourceUsage[k] = append(l.resourceUsage[k], v)
+	}
+}
+
+// AddStatSampleToProxyInfo adds a sample of a reproxy level time series. The samples are
+// summarized in the stats of the ProxyInfo object when the logger is closed.
+func (l *Logger) AddStatSampleToProxyInfo(key string, value int64) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.resourceUsage == nil {
+		l.resourceUsage = make(map[string][]int64)
 	}
+	l.resourceUsage[key] = append(l.resourceUsage[key], value)
 }
 
 // IncrementMetricIntToProxyInfo will increment a reproxy level event to the ProxyInfo object.
@@ -585,6 +643,35 @@ func (l *Logger) Log(rec *LogRecord) {
 	}
 }
 
+// TrackInvocation starts aggregating the stats of the actions of the given invocation ID
+// separately, until FinishInvocation is called for it.
+func (l *Logger) TrackInvocation(invocationID string) {
+	if l == nil {
+		return
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.open {
+		l.ch <- &trackInvocationEvent{invocationID: invocationID}
+	}
+}
+
+// FinishInvocation stops tracking the given invocation ID and returns the aggregated stats of its
+// actions logged so far. Returns nil if the invocation ID is not tracked or the logger is closed.
+func (l *Logger) FinishInvocation(invocationID string) *spb.Stats {
+	if l == nil {
+		return nil
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if !l.open {
+		return nil
+	}
+	out := make(chan *spb.Stats, 1)
+	l.ch <- &finishInvocationEvent{invocationID: invocationID, out: out}
+	return <-out
+}
+
 // CloseAndAggregate deactivates the logger and waits for pending records to finish logging.
 // The log file is then closed. Any subsequent Log calls will be discarded.
 // Finally, aggregated build stats are generated and returned.
@@ -603,7 +690,9 @@ func (l *Logger) CloseAndAggregate() *spb.Stats {
 		l.cancelSamplerCtx()
 	}
 	l.wg.Wait()
+	l.mu.Lock()
 	l.info.Stats = append(l.info.Stats, summarize(l.resourceUsage)...)
+	l.mu.Unlock()
 	l.writeProxyInfo()
 	l.stats.FinalizeAggregate([]*lpb.ProxyInfo{l.info})
 	return l.stats.ToProto()
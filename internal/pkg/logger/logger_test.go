@@ -569,6 +569,35 @@ func TestStatManagement(t *testing.T) {
 	}
 }
 
+func TestInvocationStats(t *testing.T) {
+	execRoot := t.TempDir()
+	s := &stubStats{}
+	logger, err := New(TextFormat, execRoot, s, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create new Logger: %v", err)
+	}
+	defer logger.CloseAndAggregate()
+	logger.TrackInvocation("inv1")
+	for _, invID := range []string{"inv1", "inv2", "inv1"} {
+		r := logger.LogActionStart()
+		r.Command = &cpb.Command{Identifiers: &cpb.Identifiers{InvocationId: invID}}
+		r.Result = &cpb.CommandResult{Status: cpb.CommandResultStatus_SUCCESS}
+		logger.Log(r)
+	}
+	if got := logger.FinishInvocation("inv1").GetNumRecords(); got != 2 {
+		t.Errorf("FinishInvocation(inv1) returned stats of %v records, want 2", got)
+	}
+	if got := logger.FinishInvocation("inv1"); got != nil {
+		t.Errorf("FinishInvocation(inv1) of a finished invocation returned %v, want nil", got)
+	}
+	if got := logger.FinishInvocation("inv2"); got != nil {
+		t.Errorf("FinishInvocation(inv2) of an untracked invocation returned %v, want nil", got)
+	}
+	if len(s.recs) != 3 {
+		t.Errorf("Logger added %v records to its stats, want 3", len(s.recs))
+	}
+}
+
 // TestExportMetric tests if Logger is calling the correct metrics handling
 // functions when appropriate. This uses a stub struct for Exporter.
 func TestExportMetrics(t *testing.T) {
@@ -711,3 +740,13 @@ func TestLogger_CollectResourceUsageSamples(t *testing.T) {
 		})
 	}
 }
+
+func TestLogger_AddStatSampleToProxyInfo(t *testing.T) {
+	l := &Logger{resourceUsage: map[string][]int64{"cpu": {3}}}
+	l.AddStatSampleToProxyInfo("capacity", 4)
+	l.AddStatSampleToProxyInfo("capacity", 2)
+	want := map[string][]int64{"cpu": {3}, "capacity": {4, 2}}
+	if diff := cmp.Diff(want, l.resourceUsage); diff != "" {
+		t.Errorf("AddStatSampleToProxyInfo() generated diff: (-want +got)\n%s", diff)
+	}
+}
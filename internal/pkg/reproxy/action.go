@@ -23,10 +23,14 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/bazelbuild/reclient/internal/pkg/actioncache"
 	"github.com/bazelbuild/reclient/internal/pkg/deps"
 	"github.com/bazelbuild/reclient/internal/pkg/event"
+	"github.com/bazelbuild/reclient/internal/pkg/filetrace"
+	"github.com/bazelbuild/reclient/internal/pkg/localcas"
 	"github.com/bazelbuild/reclient/internal/pkg/logger"
 	"github.com/bazelbuild/reclient/internal/pkg/pathtranslator"
 	"github.com/bazelbuild/reclient/pkg/inputprocessor"
@@ -50,6 +54,15 @@ const (
 	// Percentile download latency at which any action taking longer is considered an outlier that
 	// should be raced with local execution.
 	downloadPercentileCutoff = 90
+	// Percentile latencies used to compare the expected remote and local execution times of an
+	// action on a cache miss.
+	remotePercentileCutoff = 50
+	localPercentileCutoff  = 50
+	// Percentile remote latency after which a hedged remote execution is started.
+	hedgePercentileCutoff = 90
+	// Learned cache hit probability below which local execution is started without waiting for
+	// the cache lookup.
+	minCacheHitProbability = 0.1
 
 	tmpBaseDir = ".reproxy_tmp"
 )
@@ -60,6 +73,7 @@ const (
 	testOnlyBlockRemoteExecKey testOnlyCtxKey = iota
 	testOnlyBlockLocalExecKey
 	testOnlyBlockFallbackKey
+	testOnlyBlockHedgedPrimaryKey
 )
 
 type action struct {
@@ -77,6 +91,9 @@ type action struct {
 	lOpt                   *ppb.LocalExecutionOptions
 	execStrategy           ppb.ExecutionStrategy_Value
 	compare                bool
+	remoteVerify           bool
+	hedge                  bool
+	hedgeDelay             time.Duration
 	numRetriesIfMismatched int
 	numLocalReruns         int
 	numRemoteReruns        int
@@ -88,6 +105,28 @@ type action struct {
 	downloadRegex          string
 	downloadTmp            string
 	atomicDownloads        bool
+	// done is closed once the action is no longer registered as active with the server.
+	done chan bool
+	// traceInputs enables tracing of the files read by local execution of the action.
+	traceInputs bool
+	// dynamicHoldoff enables deciding when to start local execution during racing based on the
+	// learned cache hit and latency models of the action.
+	dynamicHoldoff bool
+	// loadShedder is notified of the outcome of remote executions of the action.
+	loadShedder *loadShedder
+	// actionCache is the on-disk cache local results of the action are looked up in and stored
+	// in, if any.
+	actionCache *actioncache.Cache
+	// cas is the local content-addressable store outputs of the action are deduplicated through,
+	// if any.
+	cas *localcas.CAS
+	// received is the time the request of the action was received. Its inputs are older, so
+	// outputs must be modified after it.
+	received time.Time
+	// stream streams the output of non-racing local executions of the action, if not nil.
+	stream *outputStream
+	// inv is the state of the invocation the action belongs to.
+	inv *invocation
 
 	// Below parameters are computed by struct functions.
 	execContext   *rexec.Context
@@ -97,9 +136,24 @@ type action struct {
 	res           *command.Result
 	rawInOutFiles []string
 	digest        string
+	// undeclaredInputs are the files read by a traced local execution that were not declared
+	// as inputs of the action.
+	undeclaredInputs []string
+	// cmdKey is the command digest prefix the learned forecast models of the action are keyed by.
+	cmdKey string
+	// inputsProcessed is whether the inputs of the action were processed by the input processor.
+	inputsProcessed bool
+	// actionCacheDg is the digest of the action in the on-disk action cache, once computed.
+	actionCacheDg digest.Digest
+	// skippedOutputs are the outputs of a remote execution of the action that were not
+	// downloaded.
+	skippedOutputs map[string]*client.TreeOutput
 }
 
 func (a *action) runLocal(ctx context.Context, pool *LocalPool) {
+	if a.getActionCacheResult(ctx) {
+		return
+	}
 	// command is duplicated here since we append all local env variables of
 	// rewrapper to the command during execution (but these variables shouldn't
 	// become a part of the action-cache key).
@@ -109,18 +163,46 @@ func (a *action) runLocal(ctx context.Context, pool *LocalPool) {
 		*cmd = *a.cmd
 		cmd.Args = append([]string{a.lOpt.GetWrapper()}, a.cmd.Args...)
 	}
-	if a.cmd.InputSpec != nil {
+	// Commands running in a container only get the environment of the action, since the
+	// environment of rewrapper refers to the host toolchain.
+	if a.cmd.InputSpec != nil && !a.runsInContainer() {
 		cmd.InputSpec.EnvironmentVariables = sliceToMap(a.cmdEnvironment, "=")
 	}
+	var tr *filetrace.Tracer
+	if a.traceInputs && a.lOpt.GetPlatform() == ppb.LocalExecutionOptions_UNSPECIFIED {
+		if tr = filetrace.New(); tr != nil {
+			cmd.Args = tr.Wrap(cmd.Args)
+			defer tr.Close()
+		}
+	}
 
 	log.V(2).Infof("%v: Executing locally...\n%s", cmd.Identifiers.ExecutionID, strings.Join(cmd.Args, " "))
-	exitCode, err := pool.Run(ctx, ctx, cmd, a.lbls, a.oe, a.rec)
+	a.unshareOutputs()
+	exitCode, err := pool.Run(ctx, ctx, cmd, a.lbls, a.lOpt, a.stream.wrap(a.oe), a.rec)
 	a.res = command.NewResultFromExitCode(exitCode)
 	if exitCode == 0 && err != nil {
 		a.res = command.NewLocalErrorResult(err)
 	}
 	a.rec.LocalMetadata.ExecutedLocally = true
 	a.rec.LocalMetadata.Result = command.ResultToProto(a.res)
+	a.updateActionCache(ctx)
+	if a.res.Status == command.SuccessResultStatus {
+		a.addOutputsToCAS()
+	}
+	if tr != nil {
+		acc, err := tr.Accesses()
+		if err != nil {
+			log.Warningf("%v: Failed to read file access trace: %v", a.cmd.Identifiers.ExecutionID, err)
+			return
+		}
+		a.undeclaredInputs = undeclaredReads(a.cmd, filetrace.Read(acc))
+	}
+}
+
+// runsInContainer returns whether local execution of the action happens inside the container
+// image of the action rather than directly on the host.
+func (a *action) runsInContainer() bool {
+	return a.lOpt.GetPlatform() == ppb.LocalExecutionOptions_DOCKER
 }
 
 func (a *action) runRemote(ctx context.Context, client *rexec.Client) {
@@ -149,20 +231,34 @@ func (a *action) runRemote(ctx context.Context, client *rexec.Client) {
 	}
 	var res *command.Result
 	var meta *command.Metadata
+	var hedged, hedgeWon bool
+	from := time.Now()
 	defer func() {
+		a.loadShedder.recordRemote(res, time.Since(from))
 		a.digest = meta.ActionDigest.String()
 		a.rec.RemoteMetadata = logger.CommandRemoteMetadataToProto(meta)
 		a.rec.RemoteMetadata.Result = command.ResultToProto(res)
+		a.rec.RemoteMetadata.Hedged = hedged
+		a.rec.RemoteMetadata.HedgeWon = hedgeWon
 		a.res = res
 	}()
-	ec, err := client.NewContext(ctx, cmd, opts, a.oe)
+	var ec *rexec.Context
+	var err error
+	if a.hedge {
+		var h *hedgedExecution
+		if h, err = a.executeRemoteHedged(ctx, client, cmd, opts); err == nil {
+			defer h.release()
+			ec, hedged, hedgeWon = h.ec, h.hedged, h.hedgeWon
+		}
+	} else if ec, err = client.NewContext(ctx, cmd, opts, a.oe); err == nil {
+		if ec.GetCachedResult(); ec.Result == nil {
+			ec.ExecuteRemotely()
+		}
+	}
 	if err != nil {
 		res, meta = command.NewLocalErrorResult(err), &command.Metadata{}
 		return
 	}
-	if ec.GetCachedResult(); ec.Result == nil {
-		ec.ExecuteRemotely()
-	}
 	res, meta = ec.Result, ec.Metadata
 	if !res.IsOk() {
 		return
@@ -173,16 +269,18 @@ func (a *action) runRemote(ctx context.Context, client *rexec.Client) {
 			a.cmd.Identifiers.ExecutionID, err)
 		return
 	}
-	outs = a.excludeOutputsViaFilter(outs)
+	filtered := a.excludeOutputsViaFilter(outs)
+	a.skippedOutputs = skippedOutputs(outs, filtered)
+	outs = filtered
 	if excludeUnchanged {
 		outs = a.excludeUnchangedOutputs(outs, a.cmd.ExecRoot)
-		ec.DownloadSpecifiedOutputs(outs, outDir)
+		a.downloadSpecifiedOutputs(ec, outs, outDir)
 		res, meta = ec.Result, ec.Metadata
 		if ec.Result.Err != nil {
 			return
 		}
 	} else {
-		ec.DownloadSpecifiedOutputs(outs, outDir)
+		a.downloadSpecifiedOutputs(ec, outs, outDir)
 		res, meta = ec.Result, ec.Metadata
 		if ec.Result.Err != nil {
 			return
@@ -200,6 +298,113 @@ func (a *action) runRemote(ctx context.Context, client *rexec.Client) {
 	res, meta = ec.Result, ec.Metadata
 }
 
+// hedgedExecution is the outcome of a hedged remote execution.
+type hedgedExecution struct {
+	// ec is the execution context whose result is used.
+	ec *rexec.Context
+	// hedged is whether a second remote execution was started.
+	hedged bool
+	// hedgeWon is whether ec is the context of the second remote execution.
+	hedgeWon bool
+	// release cancels the context of ec once its outputs are no longer needed.
+	release func()
+}
+
+// executeRemoteHedged looks up the action in the remote cache and executes it remotely on a cache
+// miss. If the execution does not complete within the forecast remote execution latency of the
+// action, or within the default hedge delay until that latency is learned, a second remote
+// execution that does not accept cached results is started. Whichever successfully completes
+// first is used and the other is canceled.
+func (a *action) executeRemoteHedged(ctx context.Context, client *rexec.Client, cmd *command.Command, opts *command.ExecutionOptions) (*hedgedExecution, error) {
+	pCtx, pCancel := context.WithCancel(ctx)
+	pOE := outerr.NewRecordingOutErr()
+	primary, err := client.NewContext(pCtx, cmd, opts, pOE)
+	if err != nil {
+		pCancel()
+		return nil, err
+	}
+	h := &hedgedExecution{ec: primary, release: pCancel}
+	primary.GetCachedResult()
+	var delay time.Duration
+	ok := false
+	if primary.Result == nil {
+		if a.forecast != nil {
+			delay, ok = a.forecast.PercentileExecuteLatency(a, hedgePercentileCutoff)
+		}
+		if !ok && a.hedgeDelay > 0 {
+			delay, ok = a.hedgeDelay, true
+		}
+	}
+	if !ok {
+		if primary.Result == nil {
+			primary.ExecuteRemotely()
+		}
+		copyOutErr(a.oe, pOE)
+		return h, nil
+	}
+	from := time.Now()
+	done := make(chan *rexec.Context, 2)
+	go func() {
+		primary.ExecuteRemotely()
+		if v := ctx.Value(testOnlyBlockHedgedPrimaryKey); v != nil {
+			v.(func(context.Context))(pCtx)
+		}
+		done <- primary
+	}()
+	select {
+	case <-done:
+		copyOutErr(a.oe, pOE)
+		return h, nil
+	case <-time.After(delay):
+	}
+	hedgeFrom := a.rec.RecordEventTime(event.HedgeHoldoff, from)
+	log.V(1).Infof("%v: Remote execution did not complete within %v, starting hedged remote execution", a.cmd.Identifiers.ExecutionID, delay)
+	sOpts := *opts
+	sOpts.AcceptCached = false
+	sCtx, sCancel := context.WithCancel(ctx)
+	sOE := outerr.NewRecordingOutErr()
+	secondary, err := client.NewContext(sCtx, cmd, &sOpts, sOE)
+	if err != nil {
+		sCancel()
+		log.Warningf("%v: Failed to create hedged execution context: %v", a.cmd.Identifiers.ExecutionID, err)
+		<-done
+		copyOutErr(a.oe, pOE)
+		return h, nil
+	}
+	h.hedged = true
+	go func() {
+		secondary.ExecuteRemotely()
+		done <- secondary
+	}()
+	winner := <-done
+	if !winner.Result.IsOk() {
+		// Give the other execution a chance to succeed.
+		if other := <-done; other.Result.IsOk() {
+			winner = other
+		}
+	}
+	if winner == secondary {
+		pCancel()
+		h.ec, h.hedgeWon, h.release = secondary, true, sCancel
+		copyOutErr(a.oe, sOE)
+	} else {
+		sCancel()
+		copyOutErr(a.oe, pOE)
+	}
+	a.rec.RecordEventTime(event.HedgedRemoteExecution, hedgeFrom)
+	log.V(1).Infof("%v: Hedged remote execution won: %v", a.cmd.Identifiers.ExecutionID, h.hedgeWon)
+	return h, nil
+}
+
+func copyOutErr(dst outerr.OutErr, src *outerr.RecordingOutErr) {
+	if out := src.Stdout(); len(out) > 0 {
+		dst.WriteOut(out)
+	}
+	if errOut := src.Stderr(); len(errOut) > 0 {
+		dst.WriteErr(errOut)
+	}
+}
+
 func (a *action) createTmpDir() (string, func(), error) {
 	base := a.downloadTmp
 	if a.downloadTmp == "" {
@@ -261,6 +466,8 @@ type raceResult struct {
 	res *command.Result
 	oe  outerr.OutErr
 	t   resultType
+	// skipped are the outputs of a remote result that were not downloaded.
+	skipped map[string]*client.TreeOutput
 }
 
 func (a *action) race(ctx context.Context, client *rexec.Client, pool *LocalPool, numFallbacks *windowedCount, maxHoldoff time.Duration) {
@@ -355,6 +562,7 @@ func (a *action) race(ctx context.Context, client *rexec.Client, pool *LocalPool
 		a.rec.RemoteMetadata = logger.CommandRemoteMetadataToProto(a.execContext.Metadata)
 		a.rec.RemoteMetadata.Result = command.ResultToProto(winner.res)
 		a.res = winner.res
+		a.skippedOutputs = winner.skipped
 	}
 	if winner.t == local {
 		log.V(2).Infof("%v: Using local result", a.cmd.Identifiers.ExecutionID)
@@ -369,7 +577,7 @@ func (a *action) race(ctx context.Context, client *rexec.Client, pool *LocalPool
 
 // runRemoteRace runs the remote part of the race. lCh is used to start local
 // execution when remote execution is expected to take time.
-func (a *action) runRemoteRace(ctx, cCtx context.Context, client *rexec.Client, lCh chan<- bool, tmpDir string, maxHoldoff time.Duration) raceResult {
+func (a *action) runRemoteRace(ctx, cCtx context.Context, client *rexec.Client, lCh chan<- bool, tmpDir string, maxHoldoff time.Duration) (rr raceResult) {
 	opts := execOptionsFromProto(a.rOpt)
 	opts.DownloadOutputs = false // We want to download them to tmpDir instead of execRoot.
 	rcmd := a.cmd
@@ -382,12 +590,23 @@ func (a *action) runRemoteRace(ctx, cCtx context.Context, client *rexec.Client,
 	rOE := outerr.NewRecordingOutErr()
 	// Use the non-cancellable context since we don't want to abort the remote execution
 	// attempt even if local wins. This helps get cache hits for subsequent builds
+	var startLocal sync.Once
+	signalLocal := func() { startLocal.Do(func() { close(lCh) }) }
 	var err error
 	if a.execContext, err = client.NewContext(ctx, rcmd, opts, rOE); err != nil {
 		log.Warningf("%v: Failed to create execution context: %v", a.cmd.Identifiers.ExecutionID, err)
-		close(lCh)
+		signalLocal()
 		return raceResult{t: canceled, res: command.NewLocalErrorResult(err)}
 	}
+	from := time.Now()
+	defer func() { a.loadShedder.recordRemote(a.execContext.Result, time.Since(from)) }()
+	if a.dynamicHoldoff {
+		if p, ok := a.forecast.CacheHitProbability(a); ok && p < minCacheHitProbability {
+			// The action is unlikely to be a cache hit, so don't wait for the cache lookup.
+			log.V(2).Infof("%v: Cache hit probability %.2f, starting local execution", a.cmd.Identifiers.ExecutionID, p)
+			signalLocal()
+		}
+	}
 	a.execContext.GetCachedResult()
 
 	// Always record command and action digests, regardless of race result.
@@ -401,7 +620,18 @@ func (a *action) runRemoteRace(ctx, cCtx context.Context, client *rexec.Client,
 	if a.execContext.Result == nil {
 		// If action is a cache miss, start remote execution and local execution.
 		log.V(2).Infof("%v: Cache miss, starting race", a.cmd.Identifiers.ExecutionID)
-		close(lCh)
+		if sl := a.missHoldoff(maxHoldoff); sl > 0 {
+			log.V(2).Infof("%v: Holding off local execution for %v", a.cmd.Identifiers.ExecutionID, sl)
+			t := time.AfterFunc(sl, signalLocal)
+			defer func() {
+				// Local execution is still held off, start it if remote did not win.
+				if t.Stop() && rr.t != remote {
+					signalLocal()
+				}
+			}()
+		} else {
+			signalLocal()
+		}
 		a.execContext.ExecuteRemotely()
 		log.V(2).Infof("%v: Executed remotely: %+v", a.cmd.Identifiers.ExecutionID, a.execContext.Result)
 		select {
@@ -427,7 +657,7 @@ func (a *action) runRemoteRace(ctx, cCtx context.Context, client *rexec.Client,
 			}
 			time.Sleep(sl)
 			log.V(2).Infof("%v: Hold off of %v done, will signal local execution", sl, a.cmd.Identifiers.ExecutionID)
-			close(lCh)
+			signalLocal()
 		}()
 	} else {
 		// If a.execContext.GetCachedResult() must have returned a result, which is
@@ -435,7 +665,7 @@ func (a *action) runRemoteRace(ctx, cCtx context.Context, client *rexec.Client,
 		// remote error or a local error (say, input processing fail). In this case,
 		// we start local execution immediately.
 		log.Warningf("%v: GetCachedResult() returned a result neither cache hit nor cache miss: %v", a.cmd.Identifiers.ExecutionID, a.execContext.Result)
-		close(lCh)
+		signalLocal()
 	}
 	// Store action result before calling DownloadOutputs, which will overwrite the result in the
 	// exec context.
@@ -447,11 +677,14 @@ func (a *action) runRemoteRace(ctx, cCtx context.Context, client *rexec.Client,
 	}
 	log.V(2).Infof("Downloading action outputs to temp dir: %v", tmpDir)
 	outs, err := a.execContext.GetFlattenedOutputs()
+	won := raceResult{t: remote}
 	if err != nil {
 		log.Errorf("%v: Unable to get flattened outputs from Action Result: %v", a.cmd.Identifiers.ExecutionID, err)
 		a.execContext.DownloadOutputs(tmpDir)
 	} else {
-		a.execContext.DownloadSpecifiedOutputs(a.excludeOutputsViaFilter(outs), tmpDir)
+		filtered := a.excludeOutputsViaFilter(outs)
+		won.skipped = skippedOutputs(outs, filtered)
+		a.downloadSpecifiedOutputs(a.execContext, filtered, tmpDir)
 	}
 	select {
 	case <-cCtx.Done():
@@ -466,7 +699,31 @@ func (a *action) runRemoteRace(ctx, cCtx context.Context, client *rexec.Client,
 	if v := ctx.Value(testOnlyBlockRemoteExecKey); v != nil {
 		v.(func())()
 	}
-	return raceResult{t: remote, res: a.execContext.Result, oe: rOE}
+	won.res, won.oe = a.execContext.Result, rOE
+	return won
+}
+
+// missHoldoff returns how long to hold off local execution after a cache miss. Local execution
+// is held off only if dynamic holdoff is enabled and the action is expected to execute faster
+// remotely than locally.
+func (a *action) missHoldoff(maxHoldoff time.Duration) time.Duration {
+	if !a.dynamicHoldoff {
+		return 0
+	}
+	rl, ok := a.forecast.PercentileRemoteLatency(a, remotePercentileCutoff)
+	if !ok {
+		return 0
+	}
+	ll, ok := a.forecast.PercentileLocalLatency(a, localPercentileCutoff)
+	if !ok || rl >= ll {
+		return 0
+	}
+	// See runRemoteRace for the use of racingBias * 2.
+	sl := time.Duration(float64(rl.Milliseconds())*(a.racingBias*2)) * time.Millisecond
+	if sl > maxHoldoff {
+		sl = maxHoldoff
+	}
+	return sl
 }
 
 // runLocalRace runs the local portion of the race. If local execution resources
@@ -482,7 +739,7 @@ func (a *action) runLocalRace(ctx, cCtx context.Context, pool *LocalPool, client
 		log.Warningf("%v: Failed to download virtual inputs before local race run: %v", a.cmd.Identifiers.ExecutionID, err)
 	}
 	cmd := a.duplicateCmd(0)
-	if a.cmd.InputSpec != nil {
+	if a.cmd.InputSpec != nil && !a.runsInContainer() {
 		if len(a.cmdEnvironment) > 0 {
 			if cmd.InputSpec.EnvironmentVariables == nil {
 				cmd.InputSpec.EnvironmentVariables = make(map[string]string)
@@ -490,7 +747,10 @@ func (a *action) runLocalRace(ctx, cCtx context.Context, pool *LocalPool, client
 			mergeMaps(cmd.InputSpec.EnvironmentVariables, sliceToMap(a.cmdEnvironment, "="))
 		}
 	}
-	exitCode, err := pool.Run(ctx, cCtx, cmd, a.lbls, lOE, lr)
+	a.unshareOutputs()
+	// Output is not streamed while racing, since remote may still win the race after local
+	// has produced output. It is sent with the result once the race is decided.
+	exitCode, err := pool.Run(ctx, cCtx, cmd, a.lbls, a.lOpt, lOE, lr)
 	if errors.Is(err, context.Canceled) {
 		// Local did not run due to intentional context cancelation.
 		return raceResult{t: canceled}
@@ -509,6 +769,9 @@ func (a *action) runLocalRace(ctx, cCtx context.Context, pool *LocalPool, client
 	a.rec.LocalMetadata.ExecutedLocally = true
 	a.rec.CopyEventTimesFrom(lr)
 	a.rec.LocalMetadata.Result = command.ResultToProto(a.res)
+	if a.res.Status == command.SuccessResultStatus {
+		a.addOutputsToCAS()
+	}
 	return raceResult{t: local, res: a.res, oe: lOE}
 }
 
@@ -681,6 +944,9 @@ func (a *action) excludeUnchangedOutputs(outs map[string]*client.TreeOutput, out
 }
 
 func (a *action) getCachedResult(ctx context.Context) {
+	if a.getActionCacheResult(ctx) {
+		return
+	}
 	if a.execContext == nil {
 		log.Warningf("%v: no rexec.Context", a.cmd.Identifiers.ExecutionID)
 		return
@@ -708,6 +974,12 @@ func (a *action) cacheLocal() {
 	if !a.res.IsOk() || a.lOpt.GetDoNotCache() {
 		return
 	}
+	if ui := a.rec.GetLocalMetadata().GetUndeclaredInputs(); len(ui) > 0 {
+		// The remote execution of this action would not see the same inputs, so the local
+		// result must not be served from the remote cache.
+		log.Warningf("%v: Not updating remote cache, local execution accessed undeclared inputs: %v", a.cmd.Identifiers.ExecutionID, ui)
+		return
+	}
 	if err := a.generateDepsFile(); err != nil {
 		log.Warningf("%v: Failed to generate deps file: %v", a.cmd.Identifiers.ExecutionID, err)
 		return
@@ -776,6 +1048,7 @@ func (a *action) populateCommandIO(ctx context.Context, ip *inputprocessor.Input
 		}
 	}
 	log.V(2).Infof("%v: InputSpec: %+v, outputs: %+v", a.cmd.Identifiers.ExecutionID, a.cmd.InputSpec, append(a.cmd.OutputFiles, a.cmd.OutputDirs...))
+	a.inputsProcessed = true
 	return nil
 }
 
@@ -965,6 +1238,10 @@ func (a *action) duplicate(n int) []*action {
 		newAction.rOpt = &trOpt
 		newAction.lOpt = &tlOpt
 		newAction.oe = outerr.NewRecordingOutErr()
+		// Reruns of the action must execute it rather than reuse a cached result.
+		newAction.actionCache = nil
+		newAction.cas = nil
+		newAction.stream = nil
 		res = append(res, newAction)
 	}
 	return res
@@ -996,6 +1273,8 @@ func (a *action) downloadVirtualInputs(ctx context.Context, cl *rexec.Client) er
 	if a.cmd.InputSpec == nil {
 		return nil
 	}
+	// Virtual input paths are relative to the exec root, not the working directory, same as
+	// the paths of the regular inputs.
 	outDir := a.cmd.ExecRoot
 	outs := map[string]*client.TreeOutput{}
 	for _, vi := range a.cmd.InputSpec.VirtualInputs {
@@ -1004,17 +1283,22 @@ func (a *action) downloadVirtualInputs(ctx context.Context, cl *rexec.Client) er
 			if err != nil {
 				log.Warningf("%v: Invalid digest provided for virtual input %v: %v", a.cmd.Identifiers.ExecutionID, vi.Path, err)
 			}
+			// Skip inputs already fetched by a previous attempt to execute the action.
+			if a.fmc != nil {
+				if md := a.fmc.Get(filepath.Join(outDir, vi.Path)); md.Err == nil && md.Digest == dg {
+					continue
+				}
+			}
 			outs[vi.Path] = &client.TreeOutput{
-				Path:   vi.Path,
-				Digest: dg,
+				Path:         vi.Path,
+				Digest:       dg,
+				IsExecutable: vi.IsExecutable,
 			}
 		}
 	}
 	if len(outs) == 0 {
 		return nil
 	}
-	cmd := a.cmd
-	outDir = filepath.Join(outDir, cmd.WorkingDir)
 	_, err := cl.GrpcClient.DownloadOutputs(ctx, outs, outDir, cl.FileMetadataCache)
 	if err != nil {
 		return err
@@ -1022,7 +1306,7 @@ func (a *action) downloadVirtualInputs(ctx context.Context, cl *rexec.Client) er
 	for _, vi := range a.cmd.InputSpec.VirtualInputs {
 		if _, ok := outs[vi.Path]; ok {
 			abspath := filepath.Join(outDir, vi.Path)
-			if vi.Mtime != time.Unix(0, 0).UTC() {
+			if !vi.Mtime.IsZero() && vi.Mtime != time.Unix(0, 0).UTC() {
 				if err := os.Chtimes(abspath, vi.Mtime, vi.Mtime); err != nil {
 					log.Warningf("%v: Failed to change mtime of %v: %v", a.cmd.Identifiers.ExecutionID, abspath, err)
 				}
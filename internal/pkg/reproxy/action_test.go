@@ -16,6 +16,7 @@ package reproxy
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -31,6 +32,7 @@ import (
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/digest"
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/fakes"
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/filemetadata"
+	"github.com/google/go-cmp/cmp"
 )
 
 // TestDownloadRegex verifies that --download_regex controls which files to download from output list.
@@ -273,3 +275,64 @@ func TestDownloadRegex(t *testing.T) {
 		}
 	}
 }
+
+func TestUndeclaredReads(t *testing.T) {
+	er, cleanup := execroot.Setup(t, []string{"foo.c", "include/foo.h", "include/bar.h", "sysroot/stdio.h", "out/gen.h", "out/foo.o", "tool"})
+	defer cleanup()
+	cmd := &command.Command{
+		ExecRoot:   er,
+		WorkingDir: "out",
+		InputSpec: &command.InputSpec{
+			Inputs:        []string{"foo.c", "include/foo.h", "sysroot"},
+			VirtualInputs: []*command.VirtualInput{{Path: "out/gen.h", Digest: "abc/3"}},
+		},
+		OutputFiles: []string{"foo.o"},
+	}
+	reads := []string{
+		"../foo.c",
+		filepath.Join(er, "include/bar.h"),
+		"../include/bar.h",
+		"../include",
+		"../sysroot/stdio.h",
+		"gen.h",
+		"foo.o",
+		"../tool",
+		"../missing.h",
+		"/usr/include/stdio.h",
+	}
+	got := undeclaredReads(cmd, reads)
+	want := []string{"include/bar.h", "tool"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("undeclaredReads() returned wrong paths, diff (-want +got): %v", diff)
+	}
+}
+
+func TestDownloadVirtualInputsRelativeToExecRoot(t *testing.T) {
+	env, cleanup := fakes.NewTestEnv(t)
+	t.Cleanup(cleanup)
+	dg := env.Server.CAS.Put([]byte("generated"))
+	a := &action{
+		cmd: &command.Command{
+			Identifiers: &command.Identifiers{ExecutionID: "exec"},
+			ExecRoot:    env.ExecRoot,
+			WorkingDir:  "out",
+			InputSpec: &command.InputSpec{
+				VirtualInputs: []*command.VirtualInput{{Path: "out/gen/gen.h", Digest: dg.String()}},
+			},
+		},
+	}
+	if err := a.downloadVirtualInputs(context.Background(), env.Client); err != nil {
+		t.Fatalf("downloadVirtualInputs() returned error: %v", err)
+	}
+	path := filepath.Join(env.ExecRoot, "out", "gen", "gen.h")
+	got, err := digest.NewFromFile(path)
+	if err != nil {
+		t.Fatalf("digest.NewFromFile(%v) returned error: %v", path, err)
+	}
+	if got != dg {
+		t.Errorf("downloadVirtualInputs() wrote %v with digest %v, want %v", path, got, dg)
+	}
+	if _, err := os.Stat(filepath.Join(env.ExecRoot, "out", "out")); err == nil {
+		t.Errorf("downloadVirtualInputs() wrote virtual inputs relative to the working directory")
+	}
+}
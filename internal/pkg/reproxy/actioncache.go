@@ -0,0 +1,135 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reproxy
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/client"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/digest"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/outerr"
+
+	repb "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	log "github.com/golang/glog"
+	dpb "google.golang.org/protobuf/types/known/durationpb"
+)
+
+// usesActionCache returns whether the action can be looked up in and stored in the on-disk
+// action cache. The inputs of the action must have been processed for its digest to cover them.
+func (a *action) usesActionCache() bool {
+	return a.actionCache != nil && a.inputsProcessed && !a.lOpt.GetDoNotCache()
+}
+
+// actionCacheKey returns the digest of the action the on-disk action cache is keyed by. It is
+// computed as for a remote backend without support for platform properties in actions or
+// output paths in commands, so that the cache is shared by builds with and without a remote
+// backend.
+func (a *action) actionCacheKey(ctx context.Context) (digest.Digest, error) {
+	if a.actionCacheDg.Size > 0 {
+		return a.actionCacheDg, nil
+	}
+	if a.fmc == nil {
+		return digest.Empty, fmt.Errorf("no file metadata cache")
+	}
+	root, _, _, err := (&client.Client{}).ComputeMerkleTree(ctx, a.cmd.ExecRoot, a.cmd.WorkingDir, a.cmd.RemoteWorkingDir, a.cmd.InputSpec, a.fmc)
+	if err != nil {
+		return digest.Empty, err
+	}
+	cmdDg, err := digest.NewFromMessage(a.cmd.ToREProto(false))
+	if err != nil {
+		return digest.Empty, err
+	}
+	acPb := &repb.Action{
+		CommandDigest:   cmdDg.ToProto(),
+		InputRootDigest: root.ToProto(),
+	}
+	if a.cmd.Timeout > 0 {
+		acPb.Timeout = dpb.New(a.cmd.Timeout)
+	}
+	if a.actionCacheDg, err = digest.NewFromMessage(acPb); err != nil {
+		return digest.Empty, err
+	}
+	return a.actionCacheDg, nil
+}
+
+// getActionCacheResult restores the outputs of the action from the on-disk action cache and
+// returns whether the action was a valid hit.
+func (a *action) getActionCacheResult(ctx context.Context) bool {
+	if !a.usesActionCache() || !a.lOpt.GetAcceptCached() {
+		return false
+	}
+	key, err := a.actionCacheKey(ctx)
+	if err != nil {
+		log.Warningf("%v: Failed to compute action cache key: %v", a.cmd.Identifiers.ExecutionID, err)
+		return false
+	}
+	res, err := a.actionCache.Get(key, filepath.Join(a.cmd.ExecRoot, a.cmd.WorkingDir))
+	if err != nil {
+		log.Warningf("%v: Failed to look up action cache: %v", a.cmd.Identifiers.ExecutionID, err)
+		return false
+	}
+	if res == nil {
+		return false
+	}
+	// The restored outputs may differ from the cached file metadata.
+	a.clearOutputsCache()
+	if !a.cachedResultValid() {
+		log.V(1).Infof("%v: Action cache hit failed deps validation", a.cmd.Identifiers.ExecutionID)
+		return false
+	}
+	a.oe.WriteOut(res.GetStdoutRaw())
+	a.oe.WriteErr(res.GetStderrRaw())
+	a.res = &command.Result{Status: command.CacheHitResultStatus}
+	a.rec.LocalMetadata.ActionCacheHit = true
+	a.rec.LocalMetadata.Result = command.ResultToProto(a.res)
+	return true
+}
+
+// updateActionCache stores the outputs of a successful local execution of the action in the
+// on-disk action cache.
+func (a *action) updateActionCache(ctx context.Context) {
+	if !a.usesActionCache() || a.res.Status != command.SuccessResultStatus {
+		return
+	}
+	if ui := a.rec.GetLocalMetadata().GetUndeclaredInputs(); len(ui) > 0 {
+		log.V(1).Infof("%v: Not updating action cache, local execution accessed undeclared inputs: %v", a.cmd.Identifiers.ExecutionID, ui)
+		return
+	}
+	outs := append(append([]string{}, a.cmd.OutputFiles...), a.cmd.OutputDirs...)
+	if a.depsFile != "" {
+		// The deps file is needed to validate the cached result of actions with inputs from
+		// shallow input processing.
+		if err := a.generateDepsFile(); err != nil {
+			log.Warningf("%v: Failed to generate deps file: %v", a.cmd.Identifiers.ExecutionID, err)
+			return
+		}
+		outs = dedup(append(outs, a.depsFile))
+	}
+	key, err := a.actionCacheKey(ctx)
+	if err != nil {
+		log.Warningf("%v: Failed to compute action cache key: %v", a.cmd.Identifiers.ExecutionID, err)
+		return
+	}
+	var stdout, stderr []byte
+	if roe, ok := a.oe.(*outerr.RecordingOutErr); ok {
+		stdout, stderr = roe.Stdout(), roe.Stderr()
+	}
+	if err := a.actionCache.Put(key, filepath.Join(a.cmd.ExecRoot, a.cmd.WorkingDir), outs, 0, stdout, stderr); err != nil {
+		log.Warningf("%v: Failed to update action cache: %v", a.cmd.Identifiers.ExecutionID, err)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reproxy
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/client"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/filemetadata"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/rexec"
+
+	log "github.com/golang/glog"
+)
+
+// downloadSpecifiedOutputs downloads the given outputs of the action to outDir. Outputs whose
+// contents are in the local CAS are materialized from it rather than downloaded, and downloaded
+// outputs are added to the local CAS. Outputs are only hardlinked to blobs written after the
+// action was received, which are newer than its inputs.
+func (a *action) downloadSpecifiedOutputs(ec *rexec.Context, outs map[string]*client.TreeOutput, outDir string) {
+	if a.cas == nil {
+		ec.DownloadSpecifiedOutputs(outs, outDir)
+		return
+	}
+	wd := filepath.Join(outDir, a.cmd.WorkingDir)
+	rest := make(map[string]*client.TreeOutput)
+	for path, out := range outs {
+		if out.IsEmptyDirectory || out.SymlinkTarget != "" {
+			rest[path] = out
+			continue
+		}
+		abs := filepath.Join(wd, out.Path)
+		ok, err := a.cas.Materialize(out.Digest, out.IsExecutable, abs, a.received)
+		if err != nil {
+			log.Warningf("%v: Failed to materialize %v from the local CAS: %v", a.cmd.Identifiers.ExecutionID, abs, err)
+		}
+		if !ok || err != nil {
+			rest[path] = out
+			// The download writes into existing files, which may be hardlinks to blobs of the
+			// local CAS.
+			if err := os.Remove(abs); err != nil && !os.IsNotExist(err) {
+				log.Warningf("%v: Failed to remove %v before download: %v", a.cmd.Identifiers.ExecutionID, abs, err)
+			}
+			continue
+		}
+		if a.fmc != nil {
+			if err := a.fmc.Update(abs, &filemetadata.Metadata{Digest: out.Digest, IsExecutable: out.IsExecutable}); err != nil {
+				log.Warningf("%v: Failed to update file metadata of %v: %v", a.cmd.Identifiers.ExecutionID, abs, err)
+			}
+		}
+	}
+	if len(rest) < len(outs) {
+		log.V(2).Infof("%v: Materialized %v outputs from the local CAS", a.cmd.Identifiers.ExecutionID, len(outs)-len(rest))
+	}
+	ec.DownloadSpecifiedOutputs(rest, outDir)
+	if ec.Result.Err != nil {
+		return
+	}
+	for _, out := range rest {
+		if out.IsEmptyDirectory || out.SymlinkTarget != "" {
+			continue
+		}
+		abs := filepath.Join(wd, out.Path)
+		if err := a.cas.Add(abs, out.Digest, out.IsExecutable, a.received); err != nil {
+			log.Warningf("%v: Failed to add %v to the local CAS: %v", a.cmd.Identifiers.ExecutionID, abs, err)
+		}
+	}
+}
+
+// localOutputs returns the metadata of the regular output files of the action that exist.
+func (a *action) localOutputs() map[string]*filemetadata.Metadata {
+	res := make(map[string]*filemetadata.Metadata)
+	if a.fmc == nil {
+		return res
+	}
+	for _, path := range fileList(filepath.Join(a.cmd.ExecRoot, a.cmd.WorkingDir), a.cmd.OutputFiles, a.cmd.OutputDirs) {
+		md := a.fmc.Get(path)
+		if md.Err != nil || md.IsDirectory || md.Symlink != nil {
+			continue
+		}
+		res[path] = md
+	}
+	return res
+}
+
+// unshareOutputs replaces the outputs of the action that are hardlinks to blobs of the local CAS
+// by copies before local execution, which may write the outputs in place.
+func (a *action) unshareOutputs() {
+	if a.cas == nil || !a.cas.Hardlinks() {
+		return
+	}
+	for path, md := range a.localOutputs() {
+		if err := a.cas.Unshare(path, md.Digest, md.IsExecutable); err != nil {
+			log.Warningf("%v: Failed to unshare %v from the local CAS: %v", a.cmd.Identifiers.ExecutionID, path, err)
+		}
+	}
+}
+
+// addOutputsToCAS adds the outputs of a local execution of the action to the local CAS.
+func (a *action) addOutputsToCAS() {
+	if a.cas == nil {
+		return
+	}
+	// The outputs were modified by local execution.
+	a.clearOutputsCache()
+	for path, md := range a.localOutputs() {
+		if err := a.cas.Add(path, md.Digest, md.IsExecutable, a.received); err != nil {
+			log.Warningf("%v: Failed to add %v to the local CAS: %v", a.cmd.Identifiers.ExecutionID, path, err)
+		}
+	}
+}
@@ -16,14 +16,18 @@ package reproxy
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
 
 	lpb "github.com/bazelbuild/reclient/api/log"
 )
 
 func compareAction(ctx context.Context, s *Server, a *action) {
-	if !a.compare {
+	if !a.compare && !a.remoteVerify {
 		return
 	}
 	mismatches := make(map[string]*lpb.Verification_Mismatch)
@@ -76,9 +80,58 @@ func compareAction(ctx context.Context, s *Server, a *action) {
 		}
 	}
 	verRes := mismatchesToProto(mismatches, numVerified)
+	verRes.UndeclaredInputs = a.undeclaredInputs
 	a.rec.LocalMetadata.Verification = verRes
 }
 
+// undeclaredReads returns the sorted paths, relative to the exec root, of the files among the
+// given read paths that are inside the exec root but not covered by the declared inputs or
+// outputs of cmd. Relative paths are resolved against the working directory of cmd.
+func undeclaredReads(cmd *command.Command, paths []string) []string {
+	var declared []string
+	if cmd.InputSpec != nil {
+		for _, in := range cmd.InputSpec.Inputs {
+			declared = append(declared, filepath.Clean(in))
+		}
+		for _, vi := range cmd.InputSpec.VirtualInputs {
+			declared = append(declared, filepath.Clean(vi.Path))
+		}
+	}
+	for _, out := range append(append([]string{}, cmd.OutputFiles...), cmd.OutputDirs...) {
+		declared = append(declared, filepath.Join(cmd.WorkingDir, out))
+	}
+	covered := func(rel string) bool {
+		for _, d := range declared {
+			if d == "." || rel == d || strings.HasPrefix(rel, d+string(filepath.Separator)) {
+				return true
+			}
+		}
+		return false
+	}
+	seen := make(map[string]bool)
+	var res []string
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(cmd.ExecRoot, cmd.WorkingDir, p)
+		}
+		rel, err := filepath.Rel(cmd.ExecRoot, filepath.Clean(p))
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if seen[rel] || covered(rel) {
+			continue
+		}
+		seen[rel] = true
+		// Directories are traversed to reach inputs and are not inputs themselves.
+		if fi, err := os.Stat(filepath.Join(cmd.ExecRoot, rel)); err != nil || fi.IsDir() {
+			continue
+		}
+		res = append(res, filepath.ToSlash(rel))
+	}
+	sort.Strings(res)
+	return res
+}
+
 func mismatchesToProto(mismatches map[string]*lpb.Verification_Mismatch, numVerified int) *lpb.Verification {
 	// We need to output mismatches in fixed order, for tests.
 	var keys []string
@@ -24,6 +24,9 @@ import (
 	"github.com/bazelbuild/reclient/internal/pkg/labels"
 
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ppb "github.com/bazelbuild/reclient/api/proxy"
 
 	log "github.com/golang/glog"
 )
@@ -39,6 +42,10 @@ const (
 type Forecast struct {
 	downloadLatencies sync.Map
 	minSizeForStats   int
+	// labelModels and commandModels hold *actionModel keyed by labels and command digest prefix.
+	labelModels      sync.Map
+	commandModels    sync.Map
+	numCommandModels int64
 }
 
 // Run starts the background computation of parameter forecasts. Should be run in a goroutine.
@@ -71,7 +78,11 @@ func (f *Forecast) Run(ctx context.Context) {
 
 // RecordSample stores a sample from an action to be used to forecast future behavior.
 func (f *Forecast) RecordSample(a *action) {
-	if a.rec == nil || a.rec.RemoteMetadata == nil {
+	if a.rec == nil {
+		return
+	}
+	f.recordModelSample(a)
+	if a.rec.RemoteMetadata == nil {
 		return
 	}
 	v, ok := a.rec.RemoteMetadata.EventTimes[downloadResultMetricKey]
@@ -79,10 +90,7 @@ func (f *Forecast) RecordSample(a *action) {
 		return
 	}
 	l := labels.ToKey(a.lbls)
-	if f.minSizeForStats == 0 {
-		f.minSizeForStats = defaultMinSize
-	}
-	d, _ := f.downloadLatencies.LoadOrStore(l, &dataset{dataPoints: make([]int, datasetSize), minSizeForStats: f.minSizeForStats})
+	d, _ := f.downloadLatencies.LoadOrStore(l, f.newDataset())
 	ds, ok := d.(*dataset)
 	if !ok {
 		log.Warningf("Unexpected type found in the downloadLatencies map")
@@ -92,6 +100,50 @@ func (f *Forecast) RecordSample(a *action) {
 	ds.insert(int(ti.To.Sub(ti.From).Milliseconds()))
 }
 
+func (f *Forecast) newDataset() *dataset {
+	if f.minSizeForStats == 0 {
+		f.minSizeForStats = defaultMinSize
+	}
+	return &dataset{dataPoints: make([]int, datasetSize), minSizeForStats: f.minSizeForStats}
+}
+
+// loadDownloadLatencies restores download latency datasets from their persisted form, decaying
+// them according to their age. Datasets that were already recorded to are kept as is.
+func (f *Forecast) loadDownloadLatencies(dss map[string]*ppb.LatencyDataset, now time.Time) int {
+	n := 0
+	for k, dsPb := range dss {
+		vals := decayedSamples(dsPb.GetLatenciesMs(), now.Sub(dsPb.GetLastUpdated().AsTime()))
+		if len(vals) == 0 {
+			continue
+		}
+		ds := f.newDataset()
+		for _, v := range vals {
+			ds.insert(int(v))
+		}
+		ds.lastUpdated = dsPb.GetLastUpdated().AsTime()
+		ds.sort()
+		if _, loaded := f.downloadLatencies.LoadOrStore(k, ds); !loaded {
+			n++
+		}
+	}
+	return n
+}
+
+// downloadLatenciesToProto returns the download latency datasets in their persisted form.
+func (f *Forecast) downloadLatenciesToProto() map[string]*ppb.LatencyDataset {
+	res := make(map[string]*ppb.LatencyDataset)
+	f.downloadLatencies.Range(func(k, v interface{}) bool {
+		ds, ok := v.(*dataset)
+		if !ok {
+			log.Warningf("Unexpected value found in downloadLatencies map")
+			return true
+		}
+		res[k.(string)] = ds.toProto()
+		return true
+	})
+	return res
+}
+
 // PercentileDownloadLatency returns the expected pth percentile download latency of the given
 // action.
 func (f *Forecast) PercentileDownloadLatency(a *action, p int) (time.Duration, error) {
@@ -116,6 +168,7 @@ type dataset struct {
 	minSizeForStats int
 	sortedData      []int
 	sMu             sync.RWMutex
+	lastUpdated     time.Time
 }
 
 func (d *dataset) insert(v int) {
@@ -127,6 +180,22 @@ func (d *dataset) insert(v int) {
 	if d.currSize > len(d.dataPoints) {
 		d.currSize = len(d.dataPoints)
 	}
+	d.lastUpdated = time.Now()
+}
+
+func (d *dataset) toProto() *ppb.LatencyDataset {
+	d.dMu.RLock()
+	defer d.dMu.RUnlock()
+	vals := make([]int64, 0, d.currSize)
+	// Until the dataset is full, samples are stored from index 0 to head.
+	start := 0
+	if d.currSize == len(d.dataPoints) {
+		start = d.head
+	}
+	for i := 0; i < d.currSize; i++ {
+		vals = append(vals, int64(d.dataPoints[(start+i)%len(d.dataPoints)]))
+	}
+	return &ppb.LatencyDataset{LatenciesMs: vals, LastUpdated: timestamppb.New(d.lastUpdated)}
 }
 
 func (d *dataset) sort() {
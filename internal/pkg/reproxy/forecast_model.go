@@ -0,0 +1,440 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reproxy
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bazelbuild/reclient/internal/pkg/event"
+	"github.com/bazelbuild/reclient/internal/pkg/labels"
+	"github.com/bazelbuild/reclient/internal/pkg/version"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/digest"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ppb "github.com/bazelbuild/reclient/api/proxy"
+
+	cpb "github.com/bazelbuild/remote-apis-sdks/go/api/command"
+
+	log "github.com/golang/glog"
+)
+
+const (
+	forecastModelsFile = "reproxy.forecast"
+	// commandKeyLen is the length of the command digest prefix that command models are keyed by.
+	commandKeyLen = 16
+	// maxCommandModels bounds the number of command models kept in memory and on disk.
+	maxCommandModels = 100000
+	// labelModelSamples is the number of latency samples kept per label.
+	labelModelSamples = datasetSize
+	// commandModelSamples is the number of latency samples kept per command.
+	commandModelSamples = 8
+	// minLabelModelSamples is the number of samples a label model needs before it is used.
+	minLabelModelSamples = 5
+	// hitRateWeight is the weight of a new cache lookup in the cache hit rate moving average.
+	hitRateWeight = 0.2
+	// forecastHalfLife is the age at which half of the persisted samples of a model or dataset are
+	// discarded when loaded.
+	forecastHalfLife = 24 * time.Hour
+	// forecastMaxAge is the age after which persisted models and datasets are discarded entirely.
+	forecastMaxAge = 7 * 24 * time.Hour
+)
+
+// remoteLatencyEvents are the remote events which together make up the end-to-end latency of a
+// remotely executed action.
+var remoteLatencyEvents = []string{command.EventUploadInputs, command.EventExecuteRemotely, command.EventDownloadResults}
+
+// actionModel is a learned model of the remote cache hit rate and execution latencies of
+// a group of actions.
+type actionModel struct {
+	mu      sync.Mutex
+	hitRate float64
+	lookups int64
+	remote  latencies
+	// execute holds the latencies of the remote execution alone, without uploading inputs and
+	// downloading outputs.
+	execute  latencies
+	local    latencies
+	lastUsed time.Time
+}
+
+func newActionModel(size int) *actionModel {
+	return &actionModel{remote: latencies{size: size}, execute: latencies{size: size}, local: latencies{size: size}}
+}
+
+func (m *actionModel) addLookup(hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v := 0.0
+	if hit {
+		v = 1.0
+	}
+	if m.lookups == 0 {
+		m.hitRate = v
+	} else {
+		m.hitRate += hitRateWeight * (v - m.hitRate)
+	}
+	m.lookups++
+	m.lastUsed = time.Now()
+}
+
+func (m *actionModel) addRemoteLatency(d, execute time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remote.add(d.Milliseconds())
+	m.execute.add(execute.Milliseconds())
+	m.lastUsed = time.Now()
+}
+
+func (m *actionModel) addLocalLatency(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.local.add(d.Milliseconds())
+	m.lastUsed = time.Now()
+}
+
+func (m *actionModel) cacheHitRate(minLookups int64) (float64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lookups < minLookups {
+		return 0, false
+	}
+	return m.hitRate, true
+}
+
+func (m *actionModel) remotePercentile(p, minSamples int) (time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.remote.percentile(p, minSamples)
+}
+
+func (m *actionModel) executePercentile(p, minSamples int) (time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.execute.percentile(p, minSamples)
+}
+
+func (m *actionModel) localPercentile(p, minSamples int) (time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.local.percentile(p, minSamples)
+}
+
+func (m *actionModel) toProto() *ppb.ActionModel {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &ppb.ActionModel{
+		CacheHitRate:       m.hitRate,
+		CacheLookups:       m.lookups,
+		RemoteLatenciesMs:  m.remote.values(),
+		ExecuteLatenciesMs: m.execute.values(),
+		LocalLatenciesMs:   m.local.values(),
+		LastUpdated:        timestamppb.New(m.lastUsed),
+	}
+}
+
+func actionModelFromProto(pb *ppb.ActionModel, size int, now time.Time) *actionModel {
+	age := now.Sub(pb.GetLastUpdated().AsTime())
+	m := newActionModel(size)
+	m.hitRate = pb.GetCacheHitRate()
+	m.lookups = int64(math.Round(float64(pb.GetCacheLookups()) * decayFactor(age)))
+	for _, v := range decayedSamples(pb.GetRemoteLatenciesMs(), age) {
+		m.remote.add(v)
+	}
+	for _, v := range decayedSamples(pb.GetExecuteLatenciesMs(), age) {
+		m.execute.add(v)
+	}
+	for _, v := range decayedSamples(pb.GetLocalLatenciesMs(), age) {
+		m.local.add(v)
+	}
+	m.lastUsed = pb.GetLastUpdated().AsTime()
+	return m
+}
+
+// decayFactor returns the fraction of persisted samples of the given age that are kept.
+func decayFactor(age time.Duration) float64 {
+	if age > forecastMaxAge {
+		return 0
+	}
+	if age < 0 {
+		return 1
+	}
+	return math.Exp2(-float64(age) / float64(forecastHalfLife))
+}
+
+// decayedSamples returns the newest samples of vals, ordered from oldest to newest, that are kept
+// given their age.
+func decayedSamples(vals []int64, age time.Duration) []int64 {
+	n := int(math.Ceil(float64(len(vals)) * decayFactor(age)))
+	return vals[len(vals)-n:]
+}
+
+// latencies is a fixed size ring buffer of latency samples in milliseconds.
+type latencies struct {
+	size int
+	vals []int64
+	head int
+}
+
+func (l *latencies) add(v int64) {
+	if len(l.vals) < l.size {
+		l.vals = append(l.vals, v)
+		return
+	}
+	l.vals[l.head] = v
+	l.head = (l.head + 1) % l.size
+}
+
+// values returns the samples from oldest to newest.
+func (l *latencies) values() []int64 {
+	res := make([]int64, 0, len(l.vals))
+	res = append(res, l.vals[l.head:]...)
+	return append(res, l.vals[:l.head]...)
+}
+
+func (l *latencies) percentile(p, minSamples int) (time.Duration, bool) {
+	v, ok := l.percentileValue(p, minSamples)
+	return time.Duration(v) * time.Millisecond, ok
+}
+
+// percentileValue returns the pth percentile of the raw samples.
+func (l *latencies) percentileValue(p, minSamples int) (int64, bool) {
+	if len(l.vals) == 0 || len(l.vals) < minSamples {
+		return 0, false
+	}
+	sorted := append([]int64{}, l.vals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := len(sorted) * p / 100
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx], true
+}
+
+// setCommandKey sets the prefix of the digest of the command of the action that command models
+// are keyed by. It must be called once the inputs of the action are processed, before the command
+// is modified for local execution.
+func (a *action) setCommandKey() {
+	if a.cmd == nil || a.cmd.InputSpec == nil {
+		return
+	}
+	dg, err := digest.NewFromMessage(a.cmd.ToREProto(false))
+	if err != nil {
+		return
+	}
+	a.cmdKey = dg.Hash
+	if len(a.cmdKey) > commandKeyLen {
+		a.cmdKey = a.cmdKey[:commandKeyLen]
+	}
+}
+
+func (f *Forecast) labelModel(a *action) *actionModel {
+	m, _ := f.labelModels.LoadOrStore(labels.ToKey(a.lbls), newActionModel(labelModelSamples))
+	return m.(*actionModel)
+}
+
+func (f *Forecast) commandModel(a *action, create bool) *actionModel {
+	k := a.cmdKey
+	if k == "" {
+		return nil
+	}
+	if m, ok := f.commandModels.Load(k); ok {
+		return m.(*actionModel)
+	}
+	if !create || atomic.LoadInt64(&f.numCommandModels) >= maxCommandModels {
+		return nil
+	}
+	m, loaded := f.commandModels.LoadOrStore(k, newActionModel(commandModelSamples))
+	if !loaded {
+		atomic.AddInt64(&f.numCommandModels, 1)
+	}
+	return m.(*actionModel)
+}
+
+// recordModelSample updates the cache hit and latency models with the outcome of the action.
+// Models are only used by dynamic racing holdoff and hedging, so nothing is recorded for actions
+// that use neither.
+func (f *Forecast) recordModelSample(a *action) {
+	if !a.dynamicHoldoff && !a.hedge {
+		return
+	}
+	var models []*actionModel
+	models = append(models, f.labelModel(a))
+	if m := f.commandModel(a, true); m != nil {
+		models = append(models, m)
+	}
+	rm := a.rec.GetRemoteMetadata()
+	// RemoteMetadata.CacheHit is only populated for LERC, so cache hits are derived from the result.
+	cacheHit := rm.GetResult().GetStatus() == cpb.CommandResultStatus_CACHE_HIT
+	if _, ok := rm.GetEventTimes()[command.EventCheckActionCache]; ok {
+		for _, m := range models {
+			m.addLookup(cacheHit)
+		}
+	}
+	if _, ok := rm.GetEventTimes()[command.EventExecuteRemotely]; ok && !cacheHit && rm.GetResult().GetStatus() == cpb.CommandResultStatus_SUCCESS {
+		d := totalDuration(rm.GetEventTimes(), remoteLatencyEvents...)
+		e := totalDuration(rm.GetEventTimes(), command.EventExecuteRemotely)
+		for _, m := range models {
+			m.addRemoteLatency(d, e)
+		}
+	}
+	lm := a.rec.GetLocalMetadata()
+	if ti, ok := lm.GetEventTimes()[event.LocalCommandExecution]; ok && lm.GetExecutedLocally() && lm.GetResult().GetStatus() == cpb.CommandResultStatus_SUCCESS {
+		d := totalDuration(map[string]*cpb.TimeInterval{event.LocalCommandExecution: ti}, event.LocalCommandExecution)
+		for _, m := range models {
+			m.addLocalLatency(d)
+		}
+	}
+}
+
+func totalDuration(times map[string]*cpb.TimeInterval, events ...string) time.Duration {
+	var res time.Duration
+	for _, e := range events {
+		tPb, ok := times[e]
+		if !ok {
+			continue
+		}
+		ti := command.TimeIntervalFromProto(tPb)
+		if !ti.From.IsZero() && !ti.To.IsZero() {
+			res += ti.To.Sub(ti.From)
+		}
+	}
+	return res
+}
+
+// CacheHitProbability returns the learned probability that the action is a remote cache hit.
+// Returns false if there is not enough data to make a prediction.
+func (f *Forecast) CacheHitProbability(a *action) (float64, bool) {
+	if m := f.commandModel(a, false); m != nil {
+		if p, ok := m.cacheHitRate(1); ok {
+			return p, true
+		}
+	}
+	return f.labelModel(a).cacheHitRate(minLabelModelSamples)
+}
+
+// PercentileRemoteLatency returns the expected pth percentile end-to-end latency of executing
+// the action remotely on a cache miss. Returns false if there is not enough data to make a
+// prediction.
+func (f *Forecast) PercentileRemoteLatency(a *action, p int) (time.Duration, bool) {
+	if m := f.commandModel(a, false); m != nil {
+		if d, ok := m.remotePercentile(p, 1); ok {
+			return d, true
+		}
+	}
+	return f.labelModel(a).remotePercentile(p, minLabelModelSamples)
+}
+
+// PercentileExecuteLatency returns the expected pth percentile latency of the remote execution of
+// the action on a cache miss, without uploading its inputs and downloading its outputs. Returns
+// false if there is not enough data to make a prediction.
+func (f *Forecast) PercentileExecuteLatency(a *action, p int) (time.Duration, bool) {
+	if m := f.commandModel(a, false); m != nil {
+		if d, ok := m.executePercentile(p, 1); ok {
+			return d, true
+		}
+	}
+	return f.labelModel(a).executePercentile(p, minLabelModelSamples)
+}
+
+// PercentileLocalLatency returns the expected pth percentile latency of executing the action
+// locally. Returns false if there is not enough data to make a prediction.
+func (f *Forecast) PercentileLocalLatency(a *action, p int) (time.Duration, bool) {
+	if m := f.commandModel(a, false); m != nil {
+		if d, ok := m.localPercentile(p, 1); ok {
+			return d, true
+		}
+	}
+	return f.labelModel(a).localPercentile(p, minLabelModelSamples)
+}
+
+// LoadFromDir loads previously learned models and download latency datasets from a directory.
+// Everything written by a different reproxy version is ignored, and samples are discarded
+// according to their age.
+func (f *Forecast) LoadFromDir(dir string) {
+	path := filepath.Join(dir, forecastModelsFile)
+	in, err := os.ReadFile(path)
+	if err != nil {
+		log.Infof("Forecast models file %v does not exist, will create one", path)
+		return
+	}
+	db := &ppb.ForecastModels{}
+	if err := proto.Unmarshal(in, db); err != nil {
+		log.Errorf("Failed to parse forecast models file %v: %v", path, err)
+		return
+	}
+	if db.GetVersion() != version.CurrentVersion() {
+		log.Infof("Forecast models are invalid as they were generated from reproxy version %v, current reproxy version is %v", db.GetVersion(), version.CurrentVersion())
+		return
+	}
+	now := time.Now()
+	nl, nc := 0, 0
+	for k, m := range db.GetLabelModels() {
+		if now.Sub(m.GetLastUpdated().AsTime()) > forecastMaxAge {
+			continue
+		}
+		if _, loaded := f.labelModels.LoadOrStore(k, actionModelFromProto(m, labelModelSamples, now)); !loaded {
+			nl++
+		}
+	}
+	for k, m := range db.GetCommandModels() {
+		if now.Sub(m.GetLastUpdated().AsTime()) > forecastMaxAge {
+			continue
+		}
+		if _, loaded := f.commandModels.LoadOrStore(k, actionModelFromProto(m, commandModelSamples, now)); !loaded {
+			atomic.AddInt64(&f.numCommandModels, 1)
+			nc++
+		}
+	}
+	nd := f.loadDownloadLatencies(db.GetDownloadLatencies(), now)
+	log.Infof("Loaded %v label models, %v command models and %v download latency datasets from %v", nl, nc, nd, path)
+}
+
+// WriteToDisk writes the learned models and download latency datasets to a directory.
+func (f *Forecast) WriteToDisk(dir string) {
+	db := &ppb.ForecastModels{
+		Version:           version.CurrentVersion(),
+		LabelModels:       make(map[string]*ppb.ActionModel),
+		CommandModels:     make(map[string]*ppb.ActionModel),
+		DownloadLatencies: f.downloadLatenciesToProto(),
+	}
+	f.labelModels.Range(func(k, v interface{}) bool {
+		db.LabelModels[k.(string)] = v.(*actionModel).toProto()
+		return true
+	})
+	f.commandModels.Range(func(k, v interface{}) bool {
+		db.CommandModels[k.(string)] = v.(*actionModel).toProto()
+		return true
+	})
+	out, err := proto.Marshal(db)
+	if err != nil {
+		log.Errorf("Failed to marshal forecast models: %v", err)
+		return
+	}
+	path := filepath.Join(dir, forecastModelsFile)
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		log.Errorf("Failed to write forecast models to %v: %v", path, err)
+		return
+	}
+	log.Infof("Wrote %v label models, %v command models and %v download latency datasets to %v", len(db.LabelModels), len(db.CommandModels), len(db.DownloadLatencies), path)
+}
@@ -16,11 +16,18 @@ package reproxy
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	lpb "github.com/bazelbuild/reclient/api/log"
+	ppb "github.com/bazelbuild/reclient/api/proxy"
+	"github.com/bazelbuild/reclient/internal/pkg/event"
+	"github.com/bazelbuild/reclient/internal/pkg/labels"
 	"github.com/bazelbuild/reclient/internal/pkg/logger"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
 
 	cpb "github.com/bazelbuild/remote-apis-sdks/go/api/command"
 
@@ -162,3 +169,214 @@ func TestMultipleLabels(t *testing.T) {
 		t.Errorf("PercentileDownloadLatency(%v,50) = %v, want 8ms", lbls2, m)
 	}
 }
+
+func actionWithOutcome(lbls map[string]string, cmd *command.Command, cacheHit bool, remoteMs, localMs int) *action {
+	ts := time.Now()
+	interval := func(ms int) *cpb.TimeInterval {
+		return command.TimeIntervalToProto(&command.TimeInterval{From: ts, To: ts.Add(time.Duration(ms) * time.Millisecond)})
+	}
+	rec := &lpb.LogRecord{
+		RemoteMetadata: &lpb.RemoteMetadata{
+			Result:     &cpb.CommandResult{Status: cpb.CommandResultStatus_SUCCESS},
+			EventTimes: map[string]*cpb.TimeInterval{command.EventCheckActionCache: interval(1)},
+		},
+	}
+	if cacheHit {
+		rec.RemoteMetadata.Result.Status = cpb.CommandResultStatus_CACHE_HIT
+	} else if remoteMs > 0 {
+		rec.RemoteMetadata.EventTimes[command.EventExecuteRemotely] = interval(remoteMs)
+	}
+	if localMs > 0 {
+		rec.LocalMetadata = &lpb.LocalMetadata{
+			ExecutedLocally: true,
+			Result:          &cpb.CommandResult{Status: cpb.CommandResultStatus_SUCCESS},
+			EventTimes:      map[string]*cpb.TimeInterval{event.LocalCommandExecution: interval(localMs)},
+		}
+	}
+	a := &action{lbls: lbls, cmd: cmd, rec: &logger.LogRecord{LogRecord: rec}, dynamicHoldoff: true}
+	a.setCommandKey()
+	return a
+}
+
+func TestModelsNotRecordedWithoutConsumers(t *testing.T) {
+	t.Parallel()
+	f := &Forecast{}
+	for i := 0; i < minLabelModelSamples; i++ {
+		a := actionWithOutcome(lbls, nil, false, 10, 10)
+		a.dynamicHoldoff = false
+		f.RecordSample(a)
+	}
+	a := actionWithOutcome(lbls, nil, false, 0, 0)
+	if _, ok := f.CacheHitProbability(a); ok {
+		t.Errorf("CacheHitProbability() without consumers returned ok, want not ok")
+	}
+	if _, ok := f.PercentileRemoteLatency(a, 50); ok {
+		t.Errorf("PercentileRemoteLatency(50) without consumers returned ok, want not ok")
+	}
+}
+
+func TestCacheHitProbability(t *testing.T) {
+	t.Parallel()
+	f := &Forecast{}
+	if _, ok := f.CacheHitProbability(actionWithOutcome(lbls, nil, false, 0, 0)); ok {
+		t.Errorf("CacheHitProbability() with no samples returned ok, want not ok")
+	}
+	for i := 0; i < minLabelModelSamples; i++ {
+		f.RecordSample(actionWithOutcome(lbls, nil, false, 0, 0))
+	}
+	p, ok := f.CacheHitProbability(actionWithOutcome(lbls, nil, false, 0, 0))
+	if !ok || p != 0 {
+		t.Errorf("CacheHitProbability() after misses = %v, %v, want 0, true", p, ok)
+	}
+	for i := 0; i < 20; i++ {
+		f.RecordSample(actionWithOutcome(lbls, nil, true, 0, 0))
+	}
+	p, ok = f.CacheHitProbability(actionWithOutcome(lbls, nil, false, 0, 0))
+	if !ok || p < 0.9 {
+		t.Errorf("CacheHitProbability() after hits = %v, %v, want > 0.9, true", p, ok)
+	}
+	if _, ok := f.CacheHitProbability(actionWithOutcome(lbls2, nil, false, 0, 0)); ok {
+		t.Errorf("CacheHitProbability() for other labels returned ok, want not ok")
+	}
+}
+
+func TestCommandModelPreferredOverLabelModel(t *testing.T) {
+	t.Parallel()
+	f := &Forecast{}
+	cmd := func(arg string) *command.Command {
+		return &command.Command{Args: []string{"clang", arg}, ExecRoot: "/root", InputSpec: &command.InputSpec{}}
+	}
+	for i := 0; i < minLabelModelSamples; i++ {
+		f.RecordSample(actionWithOutcome(lbls, cmd("slow.cc"), false, 1000, 100))
+		f.RecordSample(actionWithOutcome(lbls, cmd("fast.cc"), false, 10, 100))
+	}
+	tests := []struct {
+		name string
+		a    *action
+		want time.Duration
+	}{
+		{name: "slow command", a: actionWithOutcome(lbls, cmd("slow.cc"), false, 0, 0), want: time.Second},
+		{name: "fast command", a: actionWithOutcome(lbls, cmd("fast.cc"), false, 0, 0), want: 10 * time.Millisecond},
+		{name: "unknown command", a: actionWithOutcome(lbls, cmd("new.cc"), false, 0, 0), want: time.Second},
+	}
+	for _, tc := range tests {
+		got, ok := f.PercentileRemoteLatency(tc.a, 50)
+		if !ok || got != tc.want {
+			t.Errorf("%v: PercentileRemoteLatency(50) = %v, %v, want %v, true", tc.name, got, ok, tc.want)
+		}
+	}
+	if got, ok := f.PercentileLocalLatency(tests[0].a, 50); !ok || got != 100*time.Millisecond {
+		t.Errorf("PercentileLocalLatency(50) = %v, %v, want 100ms, true", got, ok)
+	}
+}
+
+func TestPercentileExecuteLatencyExcludesTransfers(t *testing.T) {
+	t.Parallel()
+	f := &Forecast{}
+	for i := 0; i < minLabelModelSamples; i++ {
+		a := actionWithOutcome(lbls, nil, false, 10, 0)
+		ts := time.Now()
+		a.rec.RemoteMetadata.EventTimes[command.EventUploadInputs] = command.TimeIntervalToProto(&command.TimeInterval{From: ts, To: ts.Add(time.Second)})
+		f.RecordSample(a)
+	}
+	a := actionWithOutcome(lbls, nil, false, 0, 0)
+	if got, ok := f.PercentileExecuteLatency(a, 50); !ok || got != 10*time.Millisecond {
+		t.Errorf("PercentileExecuteLatency(50) = %v, %v, want 10ms, true", got, ok)
+	}
+	if got, ok := f.PercentileRemoteLatency(a, 50); !ok || got != 1010*time.Millisecond {
+		t.Errorf("PercentileRemoteLatency(50) = %v, %v, want 1010ms, true", got, ok)
+	}
+}
+
+func TestForecastModelsPersistence(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	f := &Forecast{}
+	for i := 0; i < minLabelModelSamples; i++ {
+		f.RecordSample(actionWithOutcome(lbls, nil, false, 200, 300))
+	}
+	f.WriteToDisk(dir)
+
+	loaded := &Forecast{}
+	loaded.LoadFromDir(dir)
+	a := actionWithOutcome(lbls, nil, false, 0, 0)
+	if got, ok := loaded.PercentileRemoteLatency(a, 50); !ok || got != 200*time.Millisecond {
+		t.Errorf("PercentileRemoteLatency(50) after load = %v, %v, want 200ms, true", got, ok)
+	}
+	if got, ok := loaded.PercentileExecuteLatency(a, 50); !ok || got != 200*time.Millisecond {
+		t.Errorf("PercentileExecuteLatency(50) after load = %v, %v, want 200ms, true", got, ok)
+	}
+	if got, ok := loaded.PercentileLocalLatency(a, 50); !ok || got != 300*time.Millisecond {
+		t.Errorf("PercentileLocalLatency(50) after load = %v, %v, want 300ms, true", got, ok)
+	}
+	if got, ok := loaded.CacheHitProbability(a); !ok || got != 0 {
+		t.Errorf("CacheHitProbability() after load = %v, %v, want 0, true", got, ok)
+	}
+
+	out, err := proto.Marshal(&ppb.ForecastModels{
+		Version:     "0.0.0.stale",
+		LabelModels: map[string]*ppb.ActionModel{labels.ToKey(lbls): {CacheLookups: 10}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal forecast models: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, forecastModelsFile), out, 0644); err != nil {
+		t.Fatalf("Failed to write forecast models: %v", err)
+	}
+	stale := &Forecast{}
+	stale.LoadFromDir(dir)
+	if _, ok := stale.CacheHitProbability(a); ok {
+		t.Errorf("CacheHitProbability() after loading stale models returned ok, want not ok")
+	}
+}
+
+func TestDownloadLatenciesPersistence(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	f := &Forecast{minSizeForStats: 1}
+	latencies := make([]int, 0)
+	for i := 0; i < datasetSize+10; i++ {
+		latencies = append(latencies, i)
+	}
+	actions := actionsWithLatencies(t, lbls, latencies)
+	for _, a := range actions {
+		f.RecordSample(a)
+	}
+	f.WriteToDisk(dir)
+
+	loaded := &Forecast{minSizeForStats: 1}
+	loaded.LoadFromDir(dir)
+	d, ok := loaded.downloadLatencies.Load(labels.ToKey(lbls))
+	if !ok {
+		t.Fatalf("LoadFromDir() did not load a download latency dataset for %v", lbls)
+	}
+	got := d.(*dataset).toProto().GetLatenciesMs()
+	if len(got) != datasetSize || got[0] != 10 || got[len(got)-1] != datasetSize+9 {
+		t.Errorf("LoadFromDir() loaded %v samples from %v to %v, want %v samples from 10 to %v", len(got), got[0], got[len(got)-1], datasetSize, datasetSize+9)
+	}
+	m, err := loaded.PercentileDownloadLatency(actions[0], 50)
+	if err != nil {
+		t.Errorf("PercentileDownloadLatency(50) after load returned error: %v", err)
+	}
+	if want := time.Duration(datasetSize/2+10) * time.Millisecond; m != want {
+		t.Errorf("PercentileDownloadLatency(50) after load = %v, want %v", m, want)
+	}
+}
+
+func TestDecayedSamples(t *testing.T) {
+	vals := []int64{1, 2, 3, 4, 5, 6, 7, 8}
+	tests := []struct {
+		age  time.Duration
+		want []int64
+	}{
+		{age: 0, want: vals},
+		{age: forecastHalfLife, want: []int64{5, 6, 7, 8}},
+		{age: 2 * forecastHalfLife, want: []int64{7, 8}},
+		{age: forecastMaxAge + time.Hour, want: []int64{}},
+	}
+	for _, tc := range tests {
+		if diff := cmp.Diff(tc.want, decayedSamples(vals, tc.age)); diff != "" {
+			t.Errorf("decayedSamples(%v, %v) returned diff (-want +got):\n%v", vals, tc.age, diff)
+		}
+	}
+}
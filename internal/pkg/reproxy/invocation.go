@@ -0,0 +1,140 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reproxy
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/golang/glog"
+)
+
+// invocation is the state reproxy keeps per invocation ID, so that builds running concurrently
+// against a single reproxy do not affect each other.
+type invocation struct {
+	id            string
+	numActions    *windowedCount
+	numFallbacks  *windowedCount
+	numIPTimeouts *atomic.Int64
+	failBuild     bool
+	failBuildMu   sync.RWMutex
+	failBuildErr  error
+
+	// The fields below are guarded by the invocationsMu of the server.
+
+	// inFlight are the actions of the invocation that are running.
+	inFlight sync.WaitGroup
+	// numInFlight is the number of actions in inFlight.
+	numInFlight int
+	// lastUsed is the last time an action of the invocation started or finished.
+	lastUsed time.Time
+	// finishing is set once FinishInvocation is called for the invocation, after which no new
+	// actions are accepted for it.
+	finishing bool
+}
+
+func newInvocation(id string, failEarlyWindow time.Duration) *invocation {
+	return &invocation{
+		id:            id,
+		numActions:    &windowedCount{window: failEarlyWindow},
+		numFallbacks:  &windowedCount{window: failEarlyWindow},
+		numIPTimeouts: &atomic.Int64{},
+	}
+}
+
+func (inv *invocation) shouldFailBuild() (bool, error) {
+	inv.failBuildMu.RLock()
+	defer inv.failBuildMu.RUnlock()
+	return inv.failBuild, inv.failBuildErr
+}
+
+// startInvocationAction returns the state of the given invocation ID, creating it if needed, and
+// counts an action of it as in flight until endInvocationAction is called. The stats of
+// invocations other than the default one of this reproxy are aggregated separately by the logger.
+// Returns an error if the invocation is being finished.
+func (s *Server) startInvocationAction(id string) (*invocation, error) {
+	s.invocationsMu.Lock()
+	inv, ok := s.invocations[id]
+	var expired []string
+	if !ok {
+		expired = s.expireInvocationsLocked()
+		inv = newInvocation(id, s.FailEarlyWindow)
+		s.invocations[id] = inv
+		if id != invocationID {
+			s.Logger.TrackInvocation(id)
+		}
+	}
+	if inv.finishing {
+		s.invocationsMu.Unlock()
+		return nil, fmt.Errorf("invocation %v is being finished", id)
+	}
+	inv.inFlight.Add(1)
+	inv.numInFlight++
+	inv.lastUsed = time.Now()
+	s.invocationsMu.Unlock()
+	for _, id := range expired {
+		log.Infof("Forgetting invocation %v after it was idle for %v", id, s.InvocationIdleTimeout)
+		s.Logger.FinishInvocation(id)
+	}
+	return inv, nil
+}
+
+// retainInvocation counts work done on behalf of an action of inv after its response as in
+// flight, until endInvocationAction is called. The action itself must still be in flight.
+func (s *Server) retainInvocation(inv *invocation) {
+	s.invocationsMu.Lock()
+	defer s.invocationsMu.Unlock()
+	inv.inFlight.Add(1)
+	inv.numInFlight++
+}
+
+// endInvocationAction undoes startInvocationAction once an action of inv is done.
+func (s *Server) endInvocationAction(inv *invocation) {
+	s.invocationsMu.Lock()
+	defer s.invocationsMu.Unlock()
+	inv.numInFlight--
+	inv.lastUsed = time.Now()
+	inv.inFlight.Done()
+}
+
+// expireInvocationsLocked forgets the invocations without actions in flight for longer than the
+// idle timeout of the server, since their builds may have ended without FinishInvocation being
+// called. Returns the IDs of the forgotten invocations.
+func (s *Server) expireInvocationsLocked() []string {
+	if s.InvocationIdleTimeout <= 0 {
+		return nil
+	}
+	var expired []string
+	for id, inv := range s.invocations {
+		if id == invocationID || inv.finishing || inv.numInFlight > 0 || time.Since(inv.lastUsed) < s.InvocationIdleTimeout {
+			continue
+		}
+		delete(s.invocations, id)
+		expired = append(expired, id)
+	}
+	return expired
+}
+
+func (s *Server) allInvocations() []*invocation {
+	s.invocationsMu.Lock()
+	defer s.invocationsMu.Unlock()
+	invs := make([]*invocation, 0, len(s.invocations))
+	for _, inv := range s.invocations {
+		invs = append(invs, inv)
+	}
+	return invs
+}
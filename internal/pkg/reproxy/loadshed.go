@@ -0,0 +1,140 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reproxy
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+
+	log "github.com/golang/glog"
+)
+
+// numLoadShedProbes is the number of remote executions sampled after the cool-down period before
+// deciding whether the remote backend has recovered.
+const numLoadShedProbes = 10
+
+type breakerState int
+
+const (
+	// breakerClosed means remote execution is healthy and no actions are shed.
+	breakerClosed breakerState = iota
+	// breakerOpen means remote execution is degraded and actions are shed to local execution.
+	breakerOpen
+	// breakerHalfOpen means the cool-down period is over and a fraction of actions probe remote
+	// execution again.
+	breakerHalfOpen
+)
+
+// loadShedder is a circuit breaker that moves actions that can run locally to local execution
+// while the remote backend is degraded, that is while the windowed remote error ratio or average
+// remote latency is over its threshold.
+type loadShedder struct {
+	maxErrorRatio float64
+	maxLatency    time.Duration
+	minActions    int64
+	window        time.Duration
+	cooldown      time.Duration
+	probeRatio    float64
+
+	mu        sync.Mutex
+	state     breakerState
+	openedAt  time.Time
+	numRemote *windowedCount
+	numErrors *windowedCount
+	latencyMs *windowedCount
+	// numCandidates is the number of actions considered for probing since the breaker half-opened.
+	numCandidates int64
+}
+
+func (ls *loadShedder) enabled() bool {
+	return ls != nil && (ls.maxErrorRatio > 0 || ls.maxLatency > 0)
+}
+
+// resetLocked starts a new window of remote execution samples. Must be called with mu held.
+func (ls *loadShedder) resetLocked() {
+	ls.numRemote = &windowedCount{window: ls.window}
+	ls.numErrors = &windowedCount{window: ls.window}
+	ls.latencyMs = &windowedCount{window: ls.window}
+}
+
+// recordRemote records the result and latency of a remote execution attempt.
+func (ls *loadShedder) recordRemote(res *command.Result, latency time.Duration) {
+	if !ls.enabled() || res == nil {
+		return
+	}
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.state == breakerOpen {
+		// Results of remote executions started before the breaker opened are not useful.
+		return
+	}
+	if ls.numRemote == nil {
+		ls.resetLocked()
+	}
+	ls.numRemote.Add(1)
+	ls.latencyMs.Add(latency.Milliseconds())
+	if res.Status == command.RemoteErrorResultStatus || res.Status == command.TimeoutResultStatus {
+		ls.numErrors.Add(1)
+	}
+	minSamples := ls.minActions
+	if ls.state == breakerHalfOpen {
+		minSamples = numLoadShedProbes
+	}
+	n := ls.numRemote.Load()
+	if n < minSamples || n == 0 {
+		return
+	}
+	errRatio := float64(ls.numErrors.Load()) / float64(n)
+	avgLatency := time.Duration(ls.latencyMs.Load()/n) * time.Millisecond
+	degraded := (ls.maxErrorRatio > 0 && errRatio >= ls.maxErrorRatio) || (ls.maxLatency > 0 && avgLatency >= ls.maxLatency)
+	switch {
+	case degraded:
+		log.Warningf("Remote execution is degraded (error ratio %.2f, average latency %v), shedding actions to local execution for %v", errRatio, avgLatency, ls.cooldown)
+		ls.state = breakerOpen
+		ls.openedAt = time.Now()
+	case ls.state == breakerHalfOpen:
+		log.Infof("Remote execution recovered (error ratio %.2f, average latency %v), no longer shedding actions", errRatio, avgLatency)
+		ls.state = breakerClosed
+		ls.resetLocked()
+	}
+}
+
+// shouldShed returns whether an action that can run locally should skip remote execution.
+func (ls *loadShedder) shouldShed() bool {
+	if !ls.enabled() {
+		return false
+	}
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.state == breakerOpen && time.Since(ls.openedAt) >= ls.cooldown {
+		log.Infof("Load shedding cool-down of %v is over, probing remote execution with %.0f%% of actions", ls.cooldown, ls.probeRatio*100)
+		ls.state = breakerHalfOpen
+		ls.numCandidates = 0
+		ls.resetLocked()
+	}
+	switch ls.state {
+	case breakerOpen:
+		return true
+	case breakerHalfOpen:
+		// Send every action that makes the running count of probes reach the next integer.
+		ls.numCandidates++
+		n := float64(ls.numCandidates)
+		return math.Floor(n*ls.probeRatio) == math.Floor((n-1)*ls.probeRatio)
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reproxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+)
+
+var (
+	remoteOK  = &command.Result{Status: command.SuccessResultStatus}
+	remoteErr = &command.Result{Status: command.RemoteErrorResultStatus}
+)
+
+func TestLoadShedderDisabled(t *testing.T) {
+	ls := &loadShedder{minActions: 1}
+	for i := 0; i < 10; i++ {
+		ls.recordRemote(remoteErr, time.Hour)
+	}
+	if ls.shouldShed() {
+		t.Errorf("shouldShed() = true for disabled load shedder, want false")
+	}
+	var nilLS *loadShedder
+	nilLS.recordRemote(remoteErr, time.Hour)
+	if nilLS.shouldShed() {
+		t.Errorf("shouldShed() = true for nil load shedder, want false")
+	}
+}
+
+func TestLoadShedderOpens(t *testing.T) {
+	tests := []struct {
+		name     string
+		ls       *loadShedder
+		res      *command.Result
+		latency  time.Duration
+		wantShed bool
+	}{
+		{
+			name:     "error ratio",
+			ls:       &loadShedder{maxErrorRatio: 0.5, minActions: 4, cooldown: time.Hour},
+			res:      remoteErr,
+			wantShed: true,
+		},
+		{
+			name:     "latency",
+			ls:       &loadShedder{maxLatency: time.Second, minActions: 4, cooldown: time.Hour},
+			res:      remoteOK,
+			latency:  2 * time.Second,
+			wantShed: true,
+		},
+		{
+			name:    "healthy",
+			ls:      &loadShedder{maxErrorRatio: 0.5, maxLatency: time.Second, minActions: 4, cooldown: time.Hour},
+			res:     remoteOK,
+			latency: time.Millisecond,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 3; i++ {
+				tc.ls.recordRemote(tc.res, tc.latency)
+			}
+			if tc.ls.shouldShed() {
+				t.Fatalf("shouldShed() = true before min actions, want false")
+			}
+			tc.ls.recordRemote(tc.res, tc.latency)
+			if got := tc.ls.shouldShed(); got != tc.wantShed {
+				t.Errorf("shouldShed() = %v, want %v", got, tc.wantShed)
+			}
+		})
+	}
+}
+
+func TestLoadShedderProbes(t *testing.T) {
+	ls := &loadShedder{maxErrorRatio: 0.5, minActions: 1, cooldown: time.Hour, probeRatio: 0.25}
+	ls.recordRemote(remoteErr, 0)
+	if !ls.shouldShed() {
+		t.Fatalf("shouldShed() = false after remote error, want true")
+	}
+	// End the cool-down period.
+	ls.openedAt = time.Now().Add(-2 * time.Hour)
+	var got []bool
+	for i := 0; i < 8; i++ {
+		got = append(got, ls.shouldShed())
+	}
+	want := []bool{true, true, true, false, true, true, true, false}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("shouldShed() during probing = %v, want %v", got, want)
+		}
+	}
+	for i := 0; i < numLoadShedProbes; i++ {
+		ls.recordRemote(remoteOK, 0)
+	}
+	if ls.state != breakerClosed {
+		t.Errorf("load shedder state after successful probes = %v, want %v", ls.state, breakerClosed)
+	}
+	if ls.shouldShed() {
+		t.Errorf("shouldShed() = true after successful probes, want false")
+	}
+}
+
+func TestLoadShedderFailedProbesReopen(t *testing.T) {
+	ls := &loadShedder{maxErrorRatio: 0.5, minActions: 1, cooldown: time.Hour, probeRatio: 1}
+	ls.recordRemote(remoteErr, 0)
+	ls.openedAt = time.Now().Add(-2 * time.Hour)
+	if ls.shouldShed() {
+		t.Fatalf("shouldShed() = true with probe ratio 1, want false")
+	}
+	for i := 0; i < numLoadShedProbes; i++ {
+		ls.recordRemote(remoteErr, 0)
+	}
+	if ls.state != breakerOpen {
+		t.Errorf("load shedder state after failed probes = %v, want %v", ls.state, breakerOpen)
+	}
+	if !ls.shouldShed() {
+		t.Errorf("shouldShed() = false after failed probes, want true")
+	}
+}
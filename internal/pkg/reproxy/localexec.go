@@ -16,25 +16,36 @@ package reproxy
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os/exec"
 	"time"
 
+	"github.com/bazelbuild/reclient/internal/pkg/cgroups"
 	"github.com/bazelbuild/reclient/internal/pkg/event"
 	"github.com/bazelbuild/reclient/internal/pkg/labels"
 	"github.com/bazelbuild/reclient/internal/pkg/localresources"
 	"github.com/bazelbuild/reclient/internal/pkg/logger"
+	"github.com/bazelbuild/reclient/internal/pkg/sandbox"
+	"github.com/bazelbuild/reclient/internal/pkg/subprocess"
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/outerr"
 
 	lpb "github.com/bazelbuild/reclient/api/log"
+	ppb "github.com/bazelbuild/reclient/api/proxy"
+
+	log "github.com/golang/glog"
 )
 
+// requirements are the local resources reserved for an action.
 type requirements struct {
 	cpus   int64
 	ramMBs int64
 }
 
 var (
+	// defaultReqs and lblReqs are the requirements of actions until enough local executions of
+	// actions with the same labels have been measured.
 	defaultReqs = requirements{1, 512}
 
 	lblReqs = map[labels.Labels]requirements{
@@ -44,6 +55,19 @@ var (
 		labels.D8Labels():        {8, 4096},
 		labels.ClangLinkLabels(): {1, 8192},
 	}
+
+	// linkPriority is the local execution queue priority of link and archive actions, which are
+	// usually on the critical path of a build.
+	linkPriority  = int32(10)
+	lblPriorities = map[labels.Labels]int32{
+		labels.ClangLinkLabels(): linkPriority,
+		labels.NaClLinkLabels():  linkPriority,
+		labels.LLVMArLabels():    linkPriority,
+	}
+
+	// errOOMKilled is returned for local commands killed for exceeding the memory limit of their
+	// cgroup.
+	errOOMKilled = errors.New("local command exceeded its memory limit and was killed")
 )
 
 // Executor can run commands and retrieve their outputs.
@@ -52,34 +76,97 @@ type Executor interface {
 	ExecuteWithOutErr(ctx context.Context, cmd *command.Command, oe outerr.OutErr) error
 }
 
+// UsageExecutor can run commands and report the resources they used.
+type UsageExecutor interface {
+	// ExecuteWithUsage runs the given command inside the working directory and returns its
+	// resource usage, or nil if the usage is unknown.
+	ExecuteWithUsage(ctx context.Context, cmd *command.Command, oe outerr.OutErr) (*subprocess.ResourceUsage, error)
+}
+
+// CgroupExecutor can run commands confined to a cgroup and report the resources they used.
+type CgroupExecutor interface {
+	// ExecuteInCgroup runs the given command inside the working directory in the given cgroup
+	// and returns its resource usage, or nil if the usage is unknown.
+	ExecuteInCgroup(ctx context.Context, cmd *command.Command, oe outerr.OutErr, cg *cgroups.Group) (*subprocess.ResourceUsage, error)
+}
+
+// SandboxExecutor can run commands in a sandbox containing only their declared inputs.
+type SandboxExecutor interface {
+	// ExecuteInSandbox runs the given command in a sandbox and returns the undeclared inputs,
+	// relative to the exec root, that the command tried to access.
+	ExecuteInSandbox(ctx context.Context, cmd *command.Command, oe outerr.OutErr) ([]string, error)
+}
+
 // LocalPool is responsible for executing commands locally.
 type LocalPool struct {
 	executor Executor
-	resMgr   *localresources.Manager
+	// dockerExecutor runs commands requesting the DOCKER local execution platform.
+	dockerExecutor Executor
+	// sandboxExecutor runs commands requesting the SANDBOX local execution platform.
+	sandboxExecutor SandboxExecutor
+	resMgr          *localresources.Manager
+	// resources learns the requirements of actions from the usage reported by UsageExecutors.
+	resources *resourceModels
+	// cgroups confines commands run directly on the host by a CgroupExecutor to the resources
+	// reserved for them.
+	cgroups *cgroups.Manager
+	// cgroupMemFactor is the factor applied to the reserved RAM to get the memory limit of a
+	// cgroup.
+	cgroupMemFactor float64
 }
 
-// NewLocalPool creates a pool with the given args.
+// NewLocalPool creates a pool with the given args. Commands requesting the DOCKER local
+// execution platform are run in their container image by invoking docker through exec,
+// and commands requesting the SANDBOX platform are run by exec in a private exec root. The
+// resources reserved for actions are learned from the measured usage of earlier actions with the
+// same labels.
 func NewLocalPool(exec Executor, resMgr *localresources.Manager) *LocalPool {
 	return &LocalPool{
-		executor: exec,
-		resMgr:   resMgr,
+		executor:        exec,
+		dockerExecutor:  &subprocess.DockerExecutor{Runtime: exec},
+		sandboxExecutor: &sandbox.Executor{Runner: exec},
+		resMgr:          resMgr,
+		resources:       &resourceModels{},
 	}
 }
 
-// Run runs a command locally. Returns the stdout, stderr, exit code, and error in case more
-// information about the failure is needed.
-func (l *LocalPool) Run(ctx, cCtx context.Context, cmd *command.Command, lbls map[string]string, oe outerr.OutErr, rec *logger.LogRecord) (int, error) {
-	var req requirements
-	var ok bool
-	if req, ok = lblReqs[labels.FromMap(lbls)]; !ok {
-		req = defaultReqs
+// UseCgroups confines commands run directly on the host to cgroups created by m. Once the
+// requirements of their labels are learned, the cgroups are limited to the CPUs reserved for them
+// and to memoryFactor times the RAM reserved for them. Commands exceeding their memory limit are
+// killed and fail with a local error.
+func (l *LocalPool) UseCgroups(m *cgroups.Manager, memoryFactor float64) {
+	l.cgroups = m
+	l.cgroupMemFactor = memoryFactor
+}
+
+// Run runs a command locally on the platform requested by lOpt. Returns the stdout, stderr,
+// exit code, and error in case more information about the failure is needed.
+func (l *LocalPool) Run(ctx, cCtx context.Context, cmd *command.Command, lbls map[string]string, lOpt *ppb.LocalExecutionOptions, oe outerr.OutErr, rec *logger.LogRecord) (int, error) {
+	lbl := labels.FromMap(lbls)
+	key := labels.ToKey(lbls)
+	req, learned := l.resources.requirements(key)
+	if !learned {
+		var ok bool
+		if req, ok = lblReqs[lbl]; !ok {
+			req = defaultReqs
+		}
+	}
+	priority := lOpt.GetPriority()
+	if priority == 0 {
+		priority = lblPriorities[lbl]
 	}
 	if rec.GetLocalMetadata() == nil {
 		rec.LocalMetadata = &lpb.LocalMetadata{}
 	}
+	rec.LocalMetadata.LocalPriority = priority
+	rec.LocalMetadata.ResourceUsage = &lpb.LocalResourceUsage{
+		ReservedCpus:  req.cpus,
+		ReservedRamMb: req.ramMBs,
+		Learned:       learned,
+	}
 
 	qt := time.Now()
-	release, err := l.resMgr.Lock(cCtx, req.cpus, req.ramMBs)
+	release, err := l.resMgr.LockWithPriority(cCtx, priority, req.cpus, req.ramMBs)
 	et := rec.RecordEventTime(event.LocalCommandQueued, qt)
 	if err != nil {
 		return 0, err
@@ -91,10 +178,81 @@ func (l *LocalPool) Run(ctx, cCtx context.Context, cmd *command.Command, lbls ma
 	if v := ctx.Value(testOnlyBlockLocalExecKey); v != nil {
 		v.(func())()
 	}
-	err = l.executor.ExecuteWithOutErr(ctx, cmd, oe)
+	start := time.Now()
+	usage, err := l.execute(ctx, cmd, req, learned, lOpt, oe, rec)
+	if usage != nil {
+		rec.LocalMetadata.ResourceUsage.PeakRssMb = usage.PeakRSSBytes / bytesPerMB
+		rec.LocalMetadata.ResourceUsage.CpuTimeMs = usage.CPUTime.Milliseconds()
+		// Failed and canceled executions don't reflect the requirements of the action.
+		if err == nil && ctx.Err() == nil {
+			l.resources.record(key, usage, time.Since(start))
+		}
+	}
+	if errors.Is(err, errOOMKilled) {
+		// The command is reported as a local failure rather than as exiting with the kill signal.
+		rec.LocalMetadata.FailureReason = lpb.LocalFailureReason_LOCAL_FAILURE_REASON_OOM_KILLED
+		return 0, err
+	}
 	exitCode := 0
 	if exitErr, _ := err.(*exec.ExitError); exitErr != nil {
 		exitCode = exitErr.ExitCode()
 	}
 	return exitCode, err
 }
+
+// execute runs the command on the platform requested by lOpt, and returns the resources used by
+// the command if they are known. learned is whether req was learned from earlier executions.
+func (l *LocalPool) execute(ctx context.Context, cmd *command.Command, req requirements, learned bool, lOpt *ppb.LocalExecutionOptions, oe outerr.OutErr, rec *logger.LogRecord) (*subprocess.ResourceUsage, error) {
+	if lOpt.GetPlatform() != ppb.LocalExecutionOptions_SANDBOX || l.sandboxExecutor == nil {
+		e := l.executorFor(lOpt)
+		if ce, ok := e.(CgroupExecutor); ok && l.cgroups != nil {
+			return l.executeInCgroup(ctx, ce, cmd, req, learned, oe)
+		}
+		if ue, ok := e.(UsageExecutor); ok {
+			return ue.ExecuteWithUsage(ctx, cmd, oe)
+		}
+		return nil, e.ExecuteWithOutErr(ctx, cmd, oe)
+	}
+	undeclared, err := l.sandboxExecutor.ExecuteInSandbox(ctx, cmd, oe)
+	if len(undeclared) > 0 {
+		log.Warningf("%v: Sandboxed local execution accessed %v undeclared inputs: %v", cmd.Identifiers.ExecutionID, len(undeclared), undeclared)
+		rec.LocalMetadata.UndeclaredInputs = undeclared
+	}
+	return nil, err
+}
+
+func (l *LocalPool) executorFor(lOpt *ppb.LocalExecutionOptions) Executor {
+	if lOpt.GetPlatform() == ppb.LocalExecutionOptions_DOCKER && l.dockerExecutor != nil {
+		return l.dockerExecutor
+	}
+	return l.executor
+}
+
+// executeInCgroup runs the command confined to a new cgroup, which is limited to the given
+// requirements if they were learned. Returns errOOMKilled if the command was killed for exceeding
+// its memory limit.
+func (l *LocalPool) executeInCgroup(ctx context.Context, ce CgroupExecutor, cmd *command.Command, req requirements, learned bool, oe outerr.OutErr) (*subprocess.ResourceUsage, error) {
+	// The static requirements are only rough estimates used for scheduling, so enforcing them
+	// would kill commands that legitimately need more. Until the requirements are learned, the
+	// cgroup only measures the usage of the command.
+	var cpus, memMBs int64
+	if learned {
+		cpus, memMBs = req.cpus, int64(float64(req.ramMBs)*l.cgroupMemFactor)
+	}
+	cg, err := l.cgroups.NewGroup(cpus, memMBs)
+	if err != nil {
+		log.Warningf("%v: Failed to create cgroup, running command unconfined: %v", cmd.Identifiers.ExecutionID, err)
+		return ce.ExecuteInCgroup(ctx, cmd, oe, nil)
+	}
+	defer func() {
+		if err := cg.Close(); err != nil {
+			log.Warningf("%v: %v", cmd.Identifiers.ExecutionID, err)
+		}
+	}()
+	usage, err := ce.ExecuteInCgroup(ctx, cmd, oe, cg)
+	if err != nil && cg.OOMKilled() {
+		log.Warningf("%v: Local command was killed for exceeding its memory limit of %v MB", cmd.Identifiers.ExecutionID, memMBs)
+		return usage, fmt.Errorf("%w: memory limit of %v MB", errOOMKilled, memMBs)
+	}
+	return usage, err
+}
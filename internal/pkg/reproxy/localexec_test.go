@@ -17,18 +17,27 @@ package reproxy
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/bazelbuild/reclient/internal/pkg/cgroups"
 	"github.com/bazelbuild/reclient/internal/pkg/labels"
 	"github.com/bazelbuild/reclient/internal/pkg/localresources"
 	"github.com/bazelbuild/reclient/internal/pkg/logger"
+	"github.com/bazelbuild/reclient/internal/pkg/subprocess"
 
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/outerr"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
 
 	lpb "github.com/bazelbuild/reclient/api/log"
+	ppb "github.com/bazelbuild/reclient/api/proxy"
 )
 
 func TestLocalPoolMaxParallelism(t *testing.T) {
@@ -49,7 +58,7 @@ func TestLocalPoolMaxParallelism(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			oe := outerr.NewRecordingOutErr()
-			exitCode, err := pool.Run(ctx, ctx, &command.Command{}, nil, oe, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
+			exitCode, err := pool.Run(ctx, ctx, &command.Command{}, nil, nil, oe, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
 			stdout := string(oe.Stdout())
 			stderr := string(oe.Stderr())
 			if stdout != exec.stdout || stderr != exec.stderr || exitCode != 0 || err != exec.err {
@@ -84,7 +93,7 @@ func TestLocalPoolCancellation(t *testing.T) {
 	go func() {
 		defer wg.Done()
 		oe := outerr.NewRecordingOutErr()
-		exitCode, err := pool.Run(ctx, cCtx, &command.Command{}, nil, oe, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
+		exitCode, err := pool.Run(ctx, cCtx, &command.Command{}, nil, nil, oe, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
 		if exitCode != 0 || err == nil || !errors.Is(err, context.Canceled) {
 			t.Errorf("Run() = %v,%v, want context canceled error", exitCode, err)
 		}
@@ -115,7 +124,7 @@ func TestLocalPool_InsufficientSystemResources(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			oe := outerr.NewRecordingOutErr()
-			exitCode, err := pool.Run(ctx, ctx, &command.Command{}, labels.ToMap(labels.MetalavaLabels()), oe, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
+			exitCode, err := pool.Run(ctx, ctx, &command.Command{}, labels.ToMap(labels.MetalavaLabels()), nil, oe, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
 			stdout := string(oe.Stdout())
 			stderr := string(oe.Stderr())
 			if stdout != exec.stdout || stderr != exec.stderr || exitCode != 0 || err != exec.err {
@@ -146,7 +155,7 @@ func TestLocalPoolHighMem(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			oe := outerr.NewRecordingOutErr()
-			exitCode, err := pool.Run(ctx, ctx, &command.Command{}, labels.ToMap(labels.MetalavaLabels()), oe, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
+			exitCode, err := pool.Run(ctx, ctx, &command.Command{}, labels.ToMap(labels.MetalavaLabels()), nil, oe, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
 			stdout := string(oe.Stdout())
 			stderr := string(oe.Stderr())
 			if stdout != exec.stdout || stderr != exec.stderr || exitCode != 0 || err != exec.err {
@@ -186,7 +195,7 @@ func TestLocalPoolMixed(t *testing.T) {
 				lbls = labels.ClangCppLabels()
 			}
 			oe := outerr.NewRecordingOutErr()
-			exitCode, err := pool.Run(ctx, ctx, &command.Command{}, labels.ToMap(lbls), oe, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
+			exitCode, err := pool.Run(ctx, ctx, &command.Command{}, labels.ToMap(lbls), nil, oe, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
 			stdout := string(oe.Stdout())
 			stderr := string(oe.Stderr())
 			if stdout != exec.stdout || stderr != exec.stderr || exitCode != 0 || err != exec.err {
@@ -203,7 +212,7 @@ func TestLocalPoolMixed(t *testing.T) {
 	go func() {
 		defer wg.Done()
 		oe := outerr.NewRecordingOutErr()
-		exitCode, err := pool.Run(ctx, ctx, &command.Command{}, labels.ToMap(labels.ClangCppLabels()), oe, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
+		exitCode, err := pool.Run(ctx, ctx, &command.Command{}, labels.ToMap(labels.ClangCppLabels()), nil, oe, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
 		stdout := string(oe.Stdout())
 		stderr := string(oe.Stderr())
 		if stdout != exec.stdout || stderr != exec.stderr || exitCode != 0 || err != exec.err {
@@ -245,7 +254,7 @@ func TestLocalPoolEventTimes(t *testing.T) {
 			defer wg.Done()
 			oe := outerr.NewRecordingOutErr()
 			rec := &logger.LogRecord{LogRecord: &lpb.LogRecord{}}
-			pool.Run(ctx, ctx, &command.Command{}, nil, oe, rec)
+			pool.Run(ctx, ctx, &command.Command{}, nil, nil, oe, rec)
 			tiLCQ, okLCQ := rec.LocalMetadata.EventTimes["LocalCommandQueued"]
 			tiLCE, okLCE := rec.LocalMetadata.EventTimes["LocalCommandExecution"]
 			if !okLCQ {
@@ -264,6 +273,71 @@ func TestLocalPoolEventTimes(t *testing.T) {
 	wg.Wait()
 }
 
+func TestLocalPoolDockerPlatform(t *testing.T) {
+	t.Parallel()
+	exec := &stubExecutor{}
+	pool := NewLocalPool(exec, localresources.NewManager(1, 512))
+	cmd := &command.Command{
+		Args:        []string{"echo", "hi"},
+		ExecRoot:    "/src",
+		Identifiers: &command.Identifiers{ExecutionID: "abc"},
+		Platform:    map[string]string{"container-image": "docker://img"},
+	}
+	ctx := context.Background()
+	lOpt := &ppb.LocalExecutionOptions{Platform: ppb.LocalExecutionOptions_DOCKER}
+	if _, err := pool.Run(ctx, ctx, cmd, nil, lOpt, outerr.NewRecordingOutErr(), &logger.LogRecord{LogRecord: &lpb.LogRecord{}}); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+	if len(exec.cmds) != 1 {
+		t.Fatalf("Run() executed %v commands, want 1", len(exec.cmds))
+	}
+	got := exec.cmds[0].Args
+	if len(got) < 3 || got[0] != "docker" || got[1] != "run" || got[len(got)-3] != "img" {
+		t.Errorf("Run() with DOCKER platform executed %v, want docker run of img", got)
+	}
+	if _, err := pool.Run(ctx, ctx, cmd, nil, nil, outerr.NewRecordingOutErr(), &logger.LogRecord{LogRecord: &lpb.LogRecord{}}); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+	if diff := cmp.Diff(cmd.Args, exec.cmds[1].Args); diff != "" {
+		t.Errorf("Run() without platform executed wrong command, diff (-want +got): %v", diff)
+	}
+}
+
+type stubSandboxExecutor struct {
+	undeclared []string
+	cmds       []*command.Command
+}
+
+func (s *stubSandboxExecutor) ExecuteInSandbox(ctx context.Context, cmd *command.Command, oe outerr.OutErr) ([]string, error) {
+	s.cmds = append(s.cmds, cmd)
+	return s.undeclared, nil
+}
+
+func TestLocalPoolSandboxPlatform(t *testing.T) {
+	t.Parallel()
+	exec := &stubExecutor{}
+	sb := &stubSandboxExecutor{undeclared: []string{"foo.h"}}
+	pool := NewLocalPool(exec, localresources.NewManager(1, 512))
+	pool.sandboxExecutor = sb
+	cmd := &command.Command{
+		Args:        []string{"echo", "hi"},
+		ExecRoot:    "/src",
+		Identifiers: &command.Identifiers{ExecutionID: "abc"},
+	}
+	ctx := context.Background()
+	lOpt := &ppb.LocalExecutionOptions{Platform: ppb.LocalExecutionOptions_SANDBOX}
+	rec := &logger.LogRecord{LogRecord: &lpb.LogRecord{}}
+	if _, err := pool.Run(ctx, ctx, cmd, nil, lOpt, outerr.NewRecordingOutErr(), rec); err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+	if len(sb.cmds) != 1 || len(exec.cmds) != 0 {
+		t.Errorf("Run() with SANDBOX platform executed %v sandboxed and %v plain commands, want 1 and 0", len(sb.cmds), len(exec.cmds))
+	}
+	if diff := cmp.Diff([]string{"foo.h"}, rec.GetLocalMetadata().GetUndeclaredInputs()); diff != "" {
+		t.Errorf("Run() recorded wrong undeclared inputs, diff (-want +got): %v", diff)
+	}
+}
+
 type stubExecutor struct {
 	numParallel int64
 	maxParallel int64
@@ -273,10 +347,12 @@ type stubExecutor struct {
 	stdout      string
 	stderr      string
 	err         error
+	cmds        []*command.Command
 }
 
 func (s *stubExecutor) ExecuteWithOutErr(ctx context.Context, cmd *command.Command, oe outerr.OutErr) error {
 	s.mu.Lock()
+	s.cmds = append(s.cmds, cmd)
 	s.numParallel++
 	if s.numParallel > s.maxParallel {
 		s.maxParallel = s.numParallel
@@ -297,3 +373,179 @@ func (s *stubExecutor) ExecuteWithOutErr(ctx context.Context, cmd *command.Comma
 	oe.WriteErr([]byte(s.stderr))
 	return s.err
 }
+
+func TestLocalPoolPriority(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		lbls map[string]string
+		lOpt *ppb.LocalExecutionOptions
+		want int32
+	}{
+		{
+			name: "default",
+			lbls: labels.ToMap(labels.ClangCppLabels()),
+			want: 0,
+		},
+		{
+			name: "link labels",
+			lbls: labels.ToMap(labels.ClangLinkLabels()),
+			want: linkPriority,
+		},
+		{
+			name: "requested",
+			lbls: labels.ToMap(labels.ClangLinkLabels()),
+			lOpt: &ppb.LocalExecutionOptions{Priority: 3},
+			want: 3,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			pool := &LocalPool{
+				executor: &stubExecutor{},
+				resMgr:   localresources.NewManager(1, 512),
+			}
+			ctx := context.Background()
+			rec := &logger.LogRecord{LogRecord: &lpb.LogRecord{}}
+			if _, err := pool.Run(ctx, ctx, &command.Command{}, tc.lbls, tc.lOpt, outerr.NewRecordingOutErr(), rec); err != nil {
+				t.Fatalf("Run() returned error: %v", err)
+			}
+			if got := rec.GetLocalMetadata().GetLocalPriority(); got != tc.want {
+				t.Errorf("Run() recorded local priority %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+type stubUsageExecutor struct {
+	stubExecutor
+	usage *subprocess.ResourceUsage
+}
+
+func (s *stubUsageExecutor) ExecuteWithUsage(ctx context.Context, cmd *command.Command, oe outerr.OutErr) (*subprocess.ResourceUsage, error) {
+	return s.usage, s.ExecuteWithOutErr(ctx, cmd, oe)
+}
+
+func TestLocalPoolLearnedRequirements(t *testing.T) {
+	t.Parallel()
+	pool := &LocalPool{
+		executor: &stubUsageExecutor{
+			usage: &subprocess.ResourceUsage{CPUTime: time.Millisecond, PeakRSSBytes: 1000 * bytesPerMB},
+		},
+		resMgr:    localresources.NewManager(16, 16384),
+		resources: &resourceModels{},
+	}
+	ctx := context.Background()
+	lbls := labels.ToMap(labels.ClangLinkLabels())
+	run := func() *lpb.LocalResourceUsage {
+		t.Helper()
+		rec := &logger.LogRecord{LogRecord: &lpb.LogRecord{}}
+		if _, err := pool.Run(ctx, ctx, &command.Command{}, lbls, nil, outerr.NewRecordingOutErr(), rec); err != nil {
+			t.Fatalf("Run() returned error: %v", err)
+		}
+		return rec.GetLocalMetadata().GetResourceUsage()
+	}
+	for i := 0; i < minResourceModelSamples; i++ {
+		want := &lpb.LocalResourceUsage{ReservedCpus: 1, ReservedRamMb: 8192, PeakRssMb: 1000, CpuTimeMs: 1}
+		if diff := cmp.Diff(want, run(), protocmp.Transform()); diff != "" {
+			t.Fatalf("Run() #%d recorded wrong resource usage, diff (-want +got): %v", i, diff)
+		}
+	}
+	want := &lpb.LocalResourceUsage{ReservedCpus: 1, ReservedRamMb: 1000, Learned: true, PeakRssMb: 1000, CpuTimeMs: 1}
+	if diff := cmp.Diff(want, run(), protocmp.Transform()); diff != "" {
+		t.Errorf("Run() after %d samples recorded wrong resource usage, diff (-want +got): %v", minResourceModelSamples, diff)
+	}
+}
+
+type stubCgroupExecutor struct {
+	stubExecutor
+	oomKill bool
+	cg      *cgroups.Group
+}
+
+func (s *stubCgroupExecutor) ExecuteInCgroup(ctx context.Context, cmd *command.Command, oe outerr.OutErr, cg *cgroups.Group) (*subprocess.ResourceUsage, error) {
+	s.cg = cg
+	if s.oomKill {
+		if err := os.WriteFile(filepath.Join(cg.Path(), "memory.events"), []byte("oom 1\noom_kill 1\n"), 0644); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("signal: killed")
+	}
+	return nil, s.ExecuteWithOutErr(ctx, cmd, oe)
+}
+
+func TestLocalPoolCgroups(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" {
+		t.Skip("cgroups are only supported on linux")
+	}
+	tests := []struct {
+		name       string
+		learned    bool
+		oomKill    bool
+		wantErr    error
+		wantReason lpb.LocalFailureReason
+	}{
+		{
+			name:    "success",
+			learned: true,
+		},
+		{
+			name:       "oom killed",
+			learned:    true,
+			oomKill:    true,
+			wantErr:    errOOMKilled,
+			wantReason: lpb.LocalFailureReason_LOCAL_FAILURE_REASON_OOM_KILLED,
+		},
+		{
+			name: "requirements not learned",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			exec := &stubCgroupExecutor{oomKill: tc.oomKill}
+			pool := &LocalPool{
+				executor:  exec,
+				resMgr:    localresources.NewManager(4, 4096),
+				resources: &resourceModels{},
+			}
+			pool.UseCgroups(cgroups.New(t.TempDir()), 2)
+			lbls := labels.ToMap(labels.ClangLinkLabels())
+			if tc.learned {
+				for i := 0; i < minResourceModelSamples; i++ {
+					pool.resources.record(labels.ToKey(lbls), &subprocess.ResourceUsage{CPUTime: time.Second, PeakRSSBytes: 1024 * bytesPerMB}, time.Second)
+				}
+			}
+			ctx := context.Background()
+			rec := &logger.LogRecord{LogRecord: &lpb.LogRecord{}}
+			cmd := &command.Command{Identifiers: &command.Identifiers{ExecutionID: "abc"}}
+			exitCode, err := pool.Run(ctx, ctx, cmd, lbls, nil, outerr.NewRecordingOutErr(), rec)
+			if exitCode != 0 || !errors.Is(err, tc.wantErr) {
+				t.Errorf("Run() = %v, %v, want 0, %v", exitCode, err, tc.wantErr)
+			}
+			if got := rec.GetLocalMetadata().GetFailureReason(); got != tc.wantReason {
+				t.Errorf("Run() recorded failure reason %v, want %v", got, tc.wantReason)
+			}
+			if exec.cg == nil {
+				t.Fatalf("Run() did not run the command in a cgroup")
+			}
+			mem, err := os.ReadFile(filepath.Join(exec.cg.Path(), "memory.max"))
+			if !tc.learned {
+				// The static 8192 MB reserved for link actions is not enforced.
+				if !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("Run() set memory.max of cgroup to %q before requirements were learned, want unset", mem)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to read memory.max of cgroup: %v", err)
+			}
+			// The learned 1024 MB are reserved.
+			if got, want := string(mem), fmt.Sprint(2*1024*1024*1024); got != want {
+				t.Errorf("Run() set memory.max of cgroup to %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reproxy
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bazelbuild/reclient/internal/pkg/subprocess"
+	"github.com/bazelbuild/reclient/internal/pkg/version"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ppb "github.com/bazelbuild/reclient/api/proxy"
+
+	log "github.com/golang/glog"
+)
+
+const (
+	resourceModelsFile = "reproxy.resources"
+	// resourceModelSamples is the number of resource usage samples kept per label.
+	resourceModelSamples = 100
+	// minResourceModelSamples is the number of samples a resource model needs before its learned
+	// requirements replace the static ones.
+	minResourceModelSamples = 5
+	// resourcePercentile is the percentile of the sampled usage that is reserved for an action.
+	resourcePercentile = 90
+	bytesPerMB         = 1024 * 1024
+)
+
+// resourceModel is a learned model of the local resource usage of a group of actions.
+type resourceModel struct {
+	mu        sync.Mutex
+	rssMBs    latencies
+	cpuMillis latencies
+	lastUsed  time.Time
+}
+
+func newResourceModel() *resourceModel {
+	return &resourceModel{
+		rssMBs:    latencies{size: resourceModelSamples},
+		cpuMillis: latencies{size: resourceModelSamples},
+	}
+}
+
+func (m *resourceModel) add(rssMB, cpuMillis int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rssMBs.add(rssMB)
+	m.cpuMillis.add(cpuMillis)
+	m.lastUsed = time.Now()
+}
+
+func (m *resourceModel) requirements() (requirements, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rss, ok := m.rssMBs.percentileValue(resourcePercentile, minResourceModelSamples)
+	if !ok {
+		return requirements{}, false
+	}
+	cpu, ok := m.cpuMillis.percentileValue(resourcePercentile, minResourceModelSamples)
+	if !ok {
+		return requirements{}, false
+	}
+	req := requirements{cpus: (cpu + 999) / 1000, ramMBs: rss}
+	if req.cpus < 1 {
+		req.cpus = 1
+	}
+	if req.ramMBs < 1 {
+		req.ramMBs = 1
+	}
+	return req, true
+}
+
+func (m *resourceModel) toProto() *ppb.ResourceModel {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &ppb.ResourceModel{
+		PeakRssMbs:  m.rssMBs.values(),
+		CpuMillis:   m.cpuMillis.values(),
+		LastUpdated: timestamppb.New(m.lastUsed),
+	}
+}
+
+func resourceModelFromProto(pb *ppb.ResourceModel, now time.Time) *resourceModel {
+	age := now.Sub(pb.GetLastUpdated().AsTime())
+	m := newResourceModel()
+	for _, v := range decayedSamples(pb.GetPeakRssMbs(), age) {
+		m.rssMBs.add(v)
+	}
+	for _, v := range decayedSamples(pb.GetCpuMillis(), age) {
+		m.cpuMillis.add(v)
+	}
+	m.lastUsed = pb.GetLastUpdated().AsTime()
+	return m
+}
+
+// resourceModels learns the local resource requirements of actions per label from the measured
+// usage of their local executions. A nil *resourceModels learns nothing.
+type resourceModels struct {
+	// models maps label keys to *resourceModel.
+	models sync.Map
+}
+
+// requirements returns the learned requirements of actions with the given labels key. Returns
+// false if there is not enough data to make a prediction.
+func (r *resourceModels) requirements(key string) (requirements, bool) {
+	if r == nil {
+		return requirements{}, false
+	}
+	m, ok := r.models.Load(key)
+	if !ok {
+		return requirements{}, false
+	}
+	return m.(*resourceModel).requirements()
+}
+
+// record adds the resource usage of a successful local execution that took the given wall time
+// to the model of actions with the given labels key.
+func (r *resourceModels) record(key string, usage *subprocess.ResourceUsage, wall time.Duration) {
+	if r == nil || usage == nil || usage.PeakRSSBytes <= 0 || wall < time.Microsecond {
+		return
+	}
+	rssMB := (usage.PeakRSSBytes + bytesPerMB - 1) / bytesPerMB
+	cpuMillis := usage.CPUTime.Microseconds() * 1000 / wall.Microseconds()
+	m, _ := r.models.LoadOrStore(key, newResourceModel())
+	m.(*resourceModel).add(rssMB, cpuMillis)
+}
+
+// LoadResourceModels loads previously learned local resource requirements from a directory.
+// Models written by a different reproxy version are ignored, and samples are discarded according
+// to their age.
+func (l *LocalPool) LoadResourceModels(dir string) {
+	if l.resources == nil {
+		return
+	}
+	path := filepath.Join(dir, resourceModelsFile)
+	in, err := os.ReadFile(path)
+	if err != nil {
+		log.Infof("Resource models file %v does not exist, will create one", path)
+		return
+	}
+	db := &ppb.ResourceModels{}
+	if err := proto.Unmarshal(in, db); err != nil {
+		log.Errorf("Failed to parse resource models file %v: %v", path, err)
+		return
+	}
+	if db.GetVersion() != version.CurrentVersion() {
+		log.Infof("Resource models are invalid as they were generated from reproxy version %v, current reproxy version is %v", db.GetVersion(), version.CurrentVersion())
+		return
+	}
+	now := time.Now()
+	n := 0
+	for k, m := range db.GetLabelModels() {
+		if now.Sub(m.GetLastUpdated().AsTime()) > forecastMaxAge {
+			continue
+		}
+		if _, loaded := l.resources.models.LoadOrStore(k, resourceModelFromProto(m, now)); !loaded {
+			n++
+		}
+	}
+	log.Infof("Loaded %v resource models from %v", n, path)
+}
+
+// WriteResourceModels writes the learned local resource requirements to a directory.
+func (l *LocalPool) WriteResourceModels(dir string) {
+	if l.resources == nil {
+		return
+	}
+	db := &ppb.ResourceModels{
+		Version:     version.CurrentVersion(),
+		LabelModels: make(map[string]*ppb.ResourceModel),
+	}
+	l.resources.models.Range(func(k, v interface{}) bool {
+		db.LabelModels[k.(string)] = v.(*resourceModel).toProto()
+		return true
+	})
+	out, err := proto.Marshal(db)
+	if err != nil {
+		log.Errorf("Failed to marshal resource models: %v", err)
+		return
+	}
+	path := filepath.Join(dir, resourceModelsFile)
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		log.Errorf("Failed to write resource models to %v: %v", path, err)
+		return
+	}
+	log.Infof("Wrote %v resource models to %v", len(db.GetLabelModels()), path)
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reproxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bazelbuild/reclient/internal/pkg/labels"
+	"github.com/bazelbuild/reclient/internal/pkg/subprocess"
+)
+
+func TestResourceModelsRequirements(t *testing.T) {
+	t.Parallel()
+	r := &resourceModels{}
+	key := labels.ToKey(lbls)
+	usage := &subprocess.ResourceUsage{CPUTime: 3 * time.Second, PeakRSSBytes: 100*bytesPerMB + 1}
+	for i := 0; i < minResourceModelSamples-1; i++ {
+		r.record(key, usage, 2*time.Second)
+	}
+	if got, ok := r.requirements(key); ok {
+		t.Fatalf("requirements() before min samples = %+v, true, want not ok", got)
+	}
+	r.record(key, usage, 2*time.Second)
+	// 1.5 CPUs on average are rounded up, and the peak RSS is rounded up to whole MBs.
+	want := requirements{cpus: 2, ramMBs: 101}
+	if got, ok := r.requirements(key); !ok || got != want {
+		t.Errorf("requirements() = %+v, %v, want %+v, true", got, ok, want)
+	}
+	if got, ok := r.requirements(labels.ToKey(lbls2)); ok {
+		t.Errorf("requirements() for unseen labels = %+v, true, want not ok", got)
+	}
+}
+
+func TestResourceModelsIgnoresUnknownUsage(t *testing.T) {
+	t.Parallel()
+	r := &resourceModels{}
+	key := labels.ToKey(lbls)
+	for i := 0; i < minResourceModelSamples; i++ {
+		r.record(key, &subprocess.ResourceUsage{CPUTime: time.Second}, time.Second)
+		r.record(key, nil, time.Second)
+	}
+	if got, ok := r.requirements(key); ok {
+		t.Errorf("requirements() without peak RSS samples = %+v, true, want not ok", got)
+	}
+	var nilModels *resourceModels
+	nilModels.record(key, &subprocess.ResourceUsage{CPUTime: time.Second, PeakRSSBytes: bytesPerMB}, time.Second)
+	if got, ok := nilModels.requirements(key); ok {
+		t.Errorf("requirements() on nil models = %+v, true, want not ok", got)
+	}
+}
+
+func TestResourceModelsPersistence(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	pool := &LocalPool{resources: &resourceModels{}}
+	key := labels.ToKey(lbls)
+	for i := 0; i < minResourceModelSamples; i++ {
+		pool.resources.record(key, &subprocess.ResourceUsage{CPUTime: 4 * time.Second, PeakRSSBytes: 2048 * bytesPerMB}, time.Second)
+	}
+	pool.WriteResourceModels(dir)
+
+	loaded := &LocalPool{resources: &resourceModels{}}
+	loaded.LoadResourceModels(dir)
+	want := requirements{cpus: 4, ramMBs: 2048}
+	if got, ok := loaded.resources.requirements(key); !ok || got != want {
+		t.Errorf("requirements() after load = %+v, %v, want %+v, true", got, ok, want)
+	}
+}
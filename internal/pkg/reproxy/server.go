@@ -29,10 +29,13 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/bazelbuild/reclient/internal/pkg/actioncache"
 	"github.com/bazelbuild/reclient/internal/pkg/event"
+	"github.com/bazelbuild/reclient/internal/pkg/execpolicy"
 	"github.com/bazelbuild/reclient/internal/pkg/features"
 	"github.com/bazelbuild/reclient/internal/pkg/interceptors"
 	"github.com/bazelbuild/reclient/internal/pkg/labels"
+	"github.com/bazelbuild/reclient/internal/pkg/localcas"
 	"github.com/bazelbuild/reclient/internal/pkg/logger"
 	"github.com/bazelbuild/reclient/internal/pkg/pathtranslator"
 	"github.com/bazelbuild/reclient/internal/pkg/protoencoding"
@@ -48,6 +51,7 @@ import (
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	lpb "github.com/bazelbuild/reclient/api/log"
 	ppb "github.com/bazelbuild/reclient/api/proxy"
@@ -87,6 +91,11 @@ var (
 	pollTime = time.Second * 10
 	// AllowedIPTimeouts is the max number of IP timeouts before failing the build
 	AllowedIPTimeouts = int64(7)
+	// errCommandCancelled is the cause of actions cancelled through CancelCommand.
+	errCommandCancelled = errors.New("command was cancelled")
+	// executionIDRe matches valid execution IDs. Execution IDs are used in paths and container
+	// names, so they must not contain separators or other special characters.
+	executionIDRe = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
 )
 
 // Server is the server responsible for executing and retrieving results of RunRequests.
@@ -102,6 +111,7 @@ type Server struct {
 	RemoteDisabled            bool
 	DumpInputTree             bool
 	Forecast                  *Forecast
+	Policy                    *execpolicy.Policy
 	StartTime                 time.Time
 	FailEarlyMinActionCount   int64
 	FailEarlyMinFallbackRatio float64
@@ -109,13 +119,22 @@ type Server struct {
 	RacingBias                float64
 	DownloadTmp               string
 	MaxHoldoff                time.Duration // Maximum amount of time to wait for downloads before starting racing.
+	DynamicHoldoff            bool          // Use learned cache hit and latency models to decide when to start local execution when racing.
+	HedgeDelay                time.Duration // Amount of time after which a REMOTE_HEDGED action without a learned remote execution latency is hedged. 0 disables hedging such actions.
+	LoadShedMaxErrorRatio     float64       // Windowed remote error ratio above which REMOTE_LOCAL_FALLBACK and RACING actions are executed locally. 0 disables the condition.
+	LoadShedMaxLatency        time.Duration // Windowed average remote latency above which REMOTE_LOCAL_FALLBACK and RACING actions are executed locally. 0 disables the condition.
+	LoadShedMinActions        int64         // Minimum number of remote executions in LoadShedWindow before load shedding can take effect.
+	LoadShedWindow            time.Duration // Window of time to compute the remote error ratio and latency over. 0 indicates all datapoints should be used.
+	LoadShedCooldown          time.Duration // Amount of time to shed actions before probing remote execution again.
+	LoadShedProbeRatio        float64       // Fraction of actions executed remotely to probe whether remote execution recovered.
 	StartupCancelFn           func()
-	numActions                *windowedCount
-	numFallbacks              *windowedCount
-	numIPTimeouts             *atomic.Int64
-	failBuild                 bool
-	failBuildMu               sync.RWMutex
-	failBuildErr              error
+	ActionCache               *actioncache.Cache // On-disk cache of the results of local executions. nil disables the cache.
+	LocalCAS                  *localcas.CAS      // Local content-addressable store outputs are deduplicated through. nil disables deduplication.
+	InvocationIdleTimeout     time.Duration      // Amount of time without actions after which the state of an invocation is forgotten. 0 disables expiry.
+	invocations               map[string]*invocation
+	invocationsMu             sync.Mutex
+	loadShedder               *loadShedder
+	virtualOutputs            *virtualOutputs
 	activeActions             sync.Map
 	records                   []*lpb.LogRecord
 	rmu                       sync.Mutex
@@ -134,6 +153,7 @@ type Server struct {
 
 type ctxWithCause struct {
 	context.Context
+	mu    sync.Mutex
 	cause error
 }
 
@@ -142,9 +162,12 @@ var (
 	invocationID = uuid.New().String()
 )
 
-func (c ctxWithCause) Err() error {
-	if c.cause != nil {
-		return c.cause
+func (c *ctxWithCause) Err() error {
+	c.mu.Lock()
+	cause := c.cause
+	c.mu.Unlock()
+	if cause != nil {
+		return cause
 	}
 	return c.Context.Err()
 }
@@ -153,7 +176,9 @@ func cancelWithCause(ctx context.Context) (context.Context, func(error)) {
 	cancelCtx, cancel := context.WithCancel(ctx)
 	withCause := &ctxWithCause{Context: cancelCtx}
 	return withCause, func(cause error) {
+		withCause.mu.Lock()
 		withCause.cause = cause
+		withCause.mu.Unlock()
 		cancel()
 	}
 }
@@ -164,9 +189,16 @@ func (s *Server) Init() {
 	s.drain = make(chan bool)
 	s.started = make(chan bool)
 	s.cleanupDone = make(chan bool)
-	s.numActions = &windowedCount{window: s.FailEarlyWindow}
-	s.numFallbacks = &windowedCount{window: s.FailEarlyWindow}
-	s.numIPTimeouts = &atomic.Int64{}
+	s.invocations = make(map[string]*invocation)
+	s.loadShedder = &loadShedder{
+		maxErrorRatio: s.LoadShedMaxErrorRatio,
+		maxLatency:    s.LoadShedMaxLatency,
+		minActions:    s.LoadShedMinActions,
+		window:        s.LoadShedWindow,
+		cooldown:      s.LoadShedCooldown,
+		probeRatio:    s.LoadShedProbeRatio,
+	}
+	s.virtualOutputs = newVirtualOutputs()
 }
 
 // SetInputProcessor sets the InputProcessor property of Server and then unblocks startup.
@@ -266,22 +298,28 @@ func (wc *windowedCount) Load() int64 {
 }
 
 func (s *Server) checkFailBuild() {
-	nf := s.numFallbacks.Load()
-	na := s.numActions.Load()
-	nt := s.numIPTimeouts.Load()
+	for _, inv := range s.allInvocations() {
+		s.checkInvocationFailBuild(inv)
+	}
+}
+
+func (s *Server) checkInvocationFailBuild(inv *invocation) {
+	nf := inv.numFallbacks.Load()
+	na := inv.numActions.Load()
+	nt := inv.numIPTimeouts.Load()
 	if nt <= AllowedIPTimeouts && na < s.FailEarlyMinActionCount {
 		return
 	}
 	if nt > AllowedIPTimeouts || float64(nf)/float64(na) >= s.FailEarlyMinFallbackRatio {
-		s.failBuildMu.Lock()
-		defer s.failBuildMu.Unlock()
-		// Set the switch to fail all the new actions...
-		s.failBuild = true
-		s.failBuildErr = s.getFailBuildErr(nf, na, nt)
-		// .. and cancel the actions that are already started.
+		inv.failBuildMu.Lock()
+		defer inv.failBuildMu.Unlock()
+		// Set the switch to fail all the new actions of the invocation...
+		inv.failBuild = true
+		inv.failBuildErr = s.getFailBuildErr(nf, na, nt)
+		// .. and cancel its actions that are already started.
 		s.activeActions.Range(func(key, val any) bool {
-			if action, ok := val.(*action); ok {
-				action.cancelFunc(s.failBuildErr)
+			if action, ok := val.(*action); ok && action.inv == inv {
+				action.cancelFunc(inv.failBuildErr)
 			}
 			return true
 		})
@@ -289,12 +327,6 @@ func (s *Server) checkFailBuild() {
 	}
 }
 
-func (s *Server) shouldFailBuild() (bool, error) {
-	s.failBuildMu.RLock()
-	defer s.failBuildMu.RUnlock()
-	return s.failBuild, s.failBuildErr
-}
-
 func (s *Server) getFailBuildErr(nf, na, nt int64) error {
 	if nt > AllowedIPTimeouts {
 		return fmt.Errorf("this build has encountered too many action input processing timeouts. Number of timeouts %v > %v",
@@ -363,6 +395,47 @@ func (s *Server) Shutdown(ctx context.Context, req *ppb.ShutdownRequest) (*ppb.S
 	}, nil
 }
 
+// FinishInvocation returns the aggregated stats of the actions of an invocation and forgets its
+// state, including its fail early state, without shutting the server down. New actions of the
+// invocation are rejected while its actions in flight are waited for.
+func (s *Server) FinishInvocation(ctx context.Context, req *ppb.FinishInvocationRequest) (*ppb.FinishInvocationResponse, error) {
+	id := req.GetInvocationId()
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "no invocation_id provided in the request")
+	}
+	s.invocationsMu.Lock()
+	inv, ok := s.invocations[id]
+	if ok {
+		if inv.finishing {
+			s.invocationsMu.Unlock()
+			return nil, status.Errorf(codes.FailedPrecondition, "invocation %v is already being finished", id)
+		}
+		inv.finishing = true
+	}
+	s.invocationsMu.Unlock()
+	if ok {
+		done := make(chan bool)
+		go func() {
+			inv.inFlight.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			s.invocationsMu.Lock()
+			inv.finishing = false
+			s.invocationsMu.Unlock()
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		s.invocationsMu.Lock()
+		delete(s.invocations, id)
+		s.invocationsMu.Unlock()
+	}
+	return &ppb.FinishInvocationResponse{
+		Stats: s.Logger.FinishInvocation(id),
+	}, nil
+}
+
 func (s *Server) withServerDrainCancel(ctx context.Context) context.Context {
 	ctx, cancel := context.WithCancel(ctx)
 	go func() {
@@ -388,6 +461,51 @@ func (s *Server) waitForStarted(ctx context.Context) error {
 
 // RunCommand runs a command according to the parameters defined in the RunRequest.
 func (s *Server) RunCommand(ctx context.Context, req *ppb.RunRequest) (*ppb.RunResponse, error) {
+	return s.runCommand(ctx, req, nil)
+}
+
+// RunCommandStream runs a command according to the parameters defined in the RunRequest, and
+// streams its stdout and stderr as they are produced by local execution. The last response
+// contains the result.
+func (s *Server) RunCommandStream(req *ppb.RunRequest, srv ppb.Commands_RunCommandStreamServer) error {
+	stream := newOutputStream(srv.Send)
+	resp, err := s.runCommand(srv.Context(), req, stream)
+	if err != nil {
+		return err
+	}
+	return stream.finish(resp)
+}
+
+// CancelCommand cancels the in-flight actions matching the execution ID and invocation ID of the
+// request. Cancelled actions finish with an interrupted result.
+func (s *Server) CancelCommand(ctx context.Context, req *ppb.CancelCommandRequest) (*ppb.CancelCommandResponse, error) {
+	if req.GetExecutionId() == "" && req.GetInvocationId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no execution_id or invocation_id provided in the request")
+	}
+	resp := &ppb.CancelCommandResponse{}
+	s.activeActions.Range(func(key, val any) bool {
+		a, ok := val.(*action)
+		if !ok {
+			return true
+		}
+		executionID := key.(string)
+		if req.GetExecutionId() != "" && executionID != req.GetExecutionId() {
+			return true
+		}
+		if req.GetInvocationId() != "" && a.cmd.Identifiers.InvocationID != req.GetInvocationId() {
+			return true
+		}
+		log.Infof("%v: Cancelling execution", executionID)
+		a.cancelFunc(errCommandCancelled)
+		resp.ExecutionIds = append(resp.ExecutionIds, executionID)
+		return true
+	})
+	return resp, nil
+}
+
+// runCommand runs a command according to the parameters defined in the RunRequest. The output of
+// the command is streamed to stream, if not nil.
+func (s *Server) runCommand(ctx context.Context, req *ppb.RunRequest, stream *outputStream) (*ppb.RunResponse, error) {
 	log.V(1).Infof("Received RunRequest:\n%s", protoencoding.TextWithIndent.Format(req))
 	// Intentionally overwriting ctx so that all function calls below will be canceled at server shutdown.
 	ctx = s.withServerDrainCancel(ctx)
@@ -405,9 +523,20 @@ func (s *Server) RunCommand(ctx context.Context, req *ppb.RunRequest) (*ppb.RunR
 	if req.Command == nil {
 		return nil, status.Error(codes.InvalidArgument, "no command provided in the request")
 	}
-	executionID := uuid.New().String()
+	executionID := req.GetCommand().GetIdentifiers().GetExecutionId()
+	if executionID == "" {
+		executionID = uuid.New().String()
+	}
+	if !executionIDRe.MatchString(executionID) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid execution_id %q, want only letters, digits and dashes", executionID)
+	}
+	req, policyOverrides := s.Policy.Apply(req)
+	for _, o := range policyOverrides {
+		log.V(1).Infof("%v: Execution policy rule %v overrode %v from %q to %q", executionID, o.GetRule(), o.GetField(), o.GetOriginalValue(), o.GetValue())
+	}
 	cmd := command.FromProto(req.Command)
 	cmd.Identifiers.ExecutionID = executionID
+	stream.setExecutionID(executionID)
 	cmd.Identifiers.ToolName = "re-client"
 	cmd.Identifiers.ToolVersion = version.CurrentVersion()
 	if err := cmd.Validate(); err != nil {
@@ -416,6 +545,11 @@ func (s *Server) RunCommand(ctx context.Context, req *ppb.RunRequest) (*ppb.RunR
 	if cmd.Identifiers.InvocationID == "" {
 		cmd.Identifiers.InvocationID = invocationID
 	}
+	inv, err := s.startInvocationAction(cmd.Identifiers.InvocationID)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	defer s.endInvocationAction(inv)
 	cmd.FillDefaultFieldValues()
 
 	s.addLabelDigestToCommandID(cmd, req)
@@ -435,8 +569,9 @@ func (s *Server) RunCommand(ctx context.Context, req *ppb.RunRequest) (*ppb.RunR
 	setPlatformOSFamily(cmd)
 
 	localMetadata := &lpb.LocalMetadata{
-		EventTimes: map[string]*cpb.TimeInterval{},
-		Labels:     req.GetLabels(),
+		EventTimes:      map[string]*cpb.TimeInterval{},
+		Labels:          req.GetLabels(),
+		PolicyOverrides: policyOverrides,
 	}
 	cmdEnv := req.GetMetadata().GetEnvironment()
 	if req.GetExecutionOptions().GetLogEnvironment() {
@@ -462,6 +597,14 @@ func (s *Server) RunCommand(ctx context.Context, req *ppb.RunRequest) (*ppb.RunR
 		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("%v: Invalid number of reruns. Total reruns of >= 2 expected. Got num_local_reruns=%v and num_remote_reruns=%v", executionID, numLocalRerun, numRemoteRerun))
 	}
 
+	remoteVerify := req.GetExecutionOptions().GetRemoteVerify()
+	if remoteVerify && req.GetExecutionOptions().GetExecutionStrategy() != ppb.ExecutionStrategy_REMOTE {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("%v: remote_verify is only supported for the REMOTE execution strategy, got %v", executionID, req.GetExecutionOptions().GetExecutionStrategy()))
+	}
+	if remoteVerify && compareMode {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("%v: remote_verify cannot be combined with compare_with_local", executionID))
+	}
+
 	reclientTimeout := int(req.GetExecutionOptions().GetReclientTimeout())
 	if reclientTimeout <= 0 {
 		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Invalid reclient_timeout set. Expected a value > 0, got: %v", reclientTimeout))
@@ -484,6 +627,7 @@ func (s *Server) RunCommand(ctx context.Context, req *ppb.RunRequest) (*ppb.RunR
 		lOpt:            req.GetExecutionOptions().GetLocalExecutionOptions(),
 		execStrategy:    req.GetExecutionOptions().GetExecutionStrategy(),
 		compare:         compareMode,
+		remoteVerify:    remoteVerify,
 		numLocalReruns:  numLocalRerun,
 		numRemoteReruns: numRemoteRerun,
 		reclientTimeout: reclientTimeout,
@@ -496,13 +640,40 @@ func (s *Server) RunCommand(ctx context.Context, req *ppb.RunRequest) (*ppb.RunR
 		toolchainInputs: ti,
 		cmdEnvironment:  cmdEnv,
 		cancelFunc:      cancel,
+		done:            make(chan bool),
+		inv:             inv,
 		racingBias:      s.RacingBias,
 		downloadRegex:   req.GetExecutionOptions().GetDownloadRegex(),
 		downloadTmp:     s.DownloadTmp,
 		atomicDownloads: req.GetExecutionOptions().GetEnableAtomicDownloads(),
+		traceInputs:     compareMode && req.GetExecutionOptions().GetTraceLocalInputs(),
+		dynamicHoldoff:  s.DynamicHoldoff,
+		hedgeDelay:      s.HedgeDelay,
+		loadShedder:     s.loadShedder,
+		cas:             s.LocalCAS,
+		received:        start,
+		stream:          stream,
+	}
+	if !compareMode {
+		a.actionCache = s.ActionCache
+	}
+	for {
+		prev, loaded := s.activeActions.LoadOrStore(executionID, a)
+		if !loaded {
+			break
+		}
+		// The request is a retry of an execution whose previous attempt is still being torn down.
+		log.Infof("%v: Waiting for the previous attempt of the execution to finish", executionID)
+		select {
+		case <-prev.(*action).done:
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
 	}
-	s.activeActions.Store(executionID, a)
-	defer s.activeActions.Delete(executionID)
+	defer func() {
+		s.activeActions.Delete(executionID)
+		close(a.done)
+	}()
 
 	if rand.Intn(100) < features.GetConfig().ExperimentalCacheMissRate {
 		a.rOpt.AcceptCached = false
@@ -512,14 +683,32 @@ func (s *Server) RunCommand(ctx context.Context, req *ppb.RunRequest) (*ppb.RunR
 	if a.compare {
 		s.rerunAction(aCtx, a)
 	}
+	if errors.Is(aCtx.Err(), errCommandCancelled) && !a.res.IsOk() {
+		log.Infof("%v: Execution was cancelled", executionID)
+		a.res = &command.Result{
+			Status:   command.InterruptedResultStatus,
+			ExitCode: command.InterruptedExitCode,
+			Err:      errCommandCancelled,
+		}
+	}
+	s.virtualOutputs.update(a)
 
-	s.logRecord(a, start)
-	oe := a.oe.(*outerr.RecordingOutErr)
-	fallbackOE := a.fallbackOE.(*outerr.RecordingOutErr)
 	var logRecord *lpb.LogRecord
-	if req.GetExecutionOptions().GetIncludeActionLog() {
-		logRecord = a.rec.LogRecord
+	if a.shouldVerifyRemote() {
+		a.rec.RecordEventTime(event.ProxyExecution, start)
+		if req.GetExecutionOptions().GetIncludeActionLog() {
+			// The record is updated with the comparison once the verification finishes.
+			logRecord = proto.Clone(a.rec.LogRecord).(*lpb.LogRecord)
+		}
+		s.verifyRemoteAsync(a)
+	} else {
+		s.logRecord(a, start)
+		if req.GetExecutionOptions().GetIncludeActionLog() {
+			logRecord = a.rec.LogRecord
+		}
 	}
+	oe := a.oe.(*outerr.RecordingOutErr)
+	fallbackOE := a.fallbackOE.(*outerr.RecordingOutErr)
 	if !a.res.IsOk() {
 		log.Errorf("%v: Execution failed with %+v", executionID, a.res)
 		log.Flush()
@@ -619,6 +808,12 @@ func (s *Server) AddProxyEvents(ctx context.Context, req *ppb.AddProxyEventsRequ
 }
 
 func (s *Server) logRecord(a *action, start time.Time) {
+	a.rec.RecordEventTime(event.ProxyExecution, start)
+	s.logAction(a)
+}
+
+// logAction logs the record of the action, whose proxy execution time is already recorded.
+func (s *Server) logAction(a *action) {
 	a.rec.Command = command.ToProto(a.cmd)
 	if a.res != nil {
 		a.rec.Result = command.ResultToProto(a.res)
@@ -627,7 +822,6 @@ func (s *Server) logRecord(a *action, start time.Time) {
 	} else if a.rec.RemoteMetadata.GetResult() != nil {
 		a.rec.Result = a.rec.RemoteMetadata.Result
 	}
-	a.rec.RecordEventTime(event.ProxyExecution, start)
 	logger.AddCompletionStatus(a.rec, a.execStrategy)
 	s.Logger.Log(a.rec)
 	if s.KeepLastRecords > 0 {
@@ -644,22 +838,39 @@ func (s *Server) logRecord(a *action, start time.Time) {
 }
 
 func (s *Server) populateCommandIO(ctx context.Context, a *action) (err error) {
+	// Declared inputs that are virtual outputs are resolved before input processing, which drops
+	// inputs that do not exist, and inputs found by input processing are resolved after.
+	s.virtualOutputs.resolveInputs(a)
 	if err = a.populateCommandIO(ctx, s.InputProcessor); errors.Is(err, inputprocessor.ErrIPTimeout) {
-		s.numIPTimeouts.Add(1)
+		a.inv.numIPTimeouts.Add(1)
+	}
+	if err == nil {
+		s.virtualOutputs.resolveInputs(a)
+		if s.Forecast != nil {
+			a.setCommandKey()
+		}
 	}
 	return err
 }
 
 func (s *Server) runAction(ctx context.Context, a *action) {
-	if failBuild, failBuildErr := s.shouldFailBuild(); failBuild {
+	if failBuild, failBuildErr := a.inv.shouldFailBuild(); failBuild {
 		if failBuildErr != nil {
 			a.res = command.NewLocalErrorResult(failBuildErr)
 			a.oe.WriteOut([]byte(failBuildErr.Error()))
 		}
 		return
 	}
-	defer s.numActions.Add(1)
+	defer a.inv.numActions.Add(1)
 	if s.RemoteDisabled {
+		// Inputs are only processed to look up the action in the on-disk action cache.
+		if a.actionCache != nil {
+			if err := s.populateCommandIO(ctx, a); err != nil {
+				log.Warningf("%v: Failed to process inputs, not using the action cache: %v", a.cmd.Identifiers.ExecutionID, err)
+			} else {
+				a.addDepsFileOutput()
+			}
+		}
 		a.runLocal(ctx, s.LocalPool)
 		return
 	}
@@ -672,21 +883,26 @@ func (s *Server) runAction(ctx context.Context, a *action) {
 	if a.rOpt.GetCanonicalizeWorkingDir() {
 		a.cmd.RemoteWorkingDir = toRemoteWorkingDir(a.cmd.WorkingDir)
 	}
+	if (a.execStrategy == ppb.ExecutionStrategy_REMOTE_LOCAL_FALLBACK || a.execStrategy == ppb.ExecutionStrategy_RACING) && s.loadShedder.shouldShed() {
+		log.V(2).Infof("%v: Remote execution is degraded, executing locally.", a.cmd.Identifiers.ExecutionID)
+		s.virtualOutputs.resolveInputs(a)
+		if err := a.downloadVirtualInputs(ctx, s.REClient); err != nil {
+			log.Warningf("%v: Failed to download virtual inputs before shed local run: %v", a.cmd.Identifiers.ExecutionID, err)
+		}
+		a.rec.LocalMetadata.LoadShed = true
+		a.runLocal(ctx, s.LocalPool)
+		return
+	}
 	switch a.execStrategy {
 	case ppb.ExecutionStrategy_LOCAL:
-		err := a.downloadVirtualInputs(ctx, s.REClient)
-		if err != nil {
-			log.Warningf("%v: Failed to download virtual inputs before LERC run: %v", a.cmd.Identifiers.ExecutionID, err)
-		}
 		s.runLERC(ctx, a)
 		if !a.res.IsOk() && a.res.Status != command.NonZeroExitResultStatus {
 			log.Warningf("%v: LERC failed with %+v, falling back to local.", a.cmd.Identifiers.ExecutionID, a.res)
 			a.runLocal(ctx, s.LocalPool)
-			s.numFallbacks.Add(1)
+			a.inv.numFallbacks.Add(1)
 		}
 		return
 	case ppb.ExecutionStrategy_REMOTE:
-		// TODO: add support for compare mode in remote execution.
 		s.runRemote(ctx, a)
 		return
 	case ppb.ExecutionStrategy_REMOTE_LOCAL_FALLBACK:
@@ -703,12 +919,16 @@ func (s *Server) runAction(ctx context.Context, a *action) {
 				log.Warningf("%v: Failed to download virtual inputs before local fallback: %v", a.cmd.Identifiers.ExecutionID, err)
 			}
 			a.runLocal(ctx, s.LocalPool)
-			s.numFallbacks.Add(1)
+			a.inv.numFallbacks.Add(1)
 		}
 		return
 	case ppb.ExecutionStrategy_RACING:
 		s.runRacing(ctx, a)
 		return
+	case ppb.ExecutionStrategy_REMOTE_HEDGED:
+		a.hedge = true
+		s.runRemote(ctx, a)
+		return
 	}
 	a.res = command.NewLocalErrorResult(fmt.Errorf("%v: invalid execution strategy %v", a.cmd.Identifiers.ExecutionID, a.execStrategy))
 }
@@ -734,19 +954,7 @@ func (s *Server) rerunAction(ctx context.Context, a *action) {
 			if !act.res.IsOk() {
 				log.Warningf("%v: Execution failed during remote rerun attempt:%v with %v", act.cmd.Identifiers.ExecutionID, attemptNum, act.res)
 			}
-			remoteRerunMetaData := &lpb.RerunMetadata{
-				Attempt:                int64(attemptNum),
-				Result:                 command.ResultToProto(act.res),
-				NumOutputFiles:         act.rec.RemoteMetadata.NumOutputFiles,
-				NumOutputDirectories:   act.rec.RemoteMetadata.NumOutputDirectories,
-				TotalOutputBytes:       act.rec.RemoteMetadata.TotalOutputBytes,
-				OutputFileDigests:      act.rec.RemoteMetadata.OutputFileDigests,
-				OutputDirectoryDigests: act.rec.RemoteMetadata.OutputDirectoryDigests,
-				LogicalBytesDownloaded: act.rec.RemoteMetadata.LogicalBytesDownloaded,
-				RealBytesDownloaded:    act.rec.RemoteMetadata.RealBytesDownloaded,
-				EventTimes:             act.rec.RemoteMetadata.EventTimes,
-			}
-			c <- remoteRerunMetaData
+			c <- remoteRerunMetadata(attemptNum, act.res, act.rec.RemoteMetadata)
 		}()
 	}
 	rerunWaiter.Wait()
@@ -763,12 +971,17 @@ func (s *Server) rerunAction(ctx context.Context, a *action) {
 	for i := 0; i < a.numLocalReruns; i++ {
 		act := localActionDupes[i]
 		attemptNum := i + 1
+		// Only the first local rerun is traced, since tracing slows down execution.
+		act.traceInputs = a.traceInputs && i == 0
 		act.clearOutputsCache()
 		if a.compare {
 			restoreInOutFiles()
 			restoreInOutFiles = a.stashInputOutputFiles()
 		}
 		act.runLocal(ctx, s.LocalPool)
+		if act.traceInputs {
+			a.undeclaredInputs = act.undeclaredInputs
+		}
 
 		if !act.res.IsOk() {
 			log.Warningf("%v: Execution failed during local rerun attempt:%v with %+v", act.cmd.Identifiers.ExecutionID, attemptNum, act.res)
@@ -820,6 +1033,68 @@ func (s *Server) rerunAction(ctx context.Context, a *action) {
 	compareAction(ctx, s, a)
 }
 
+func remoteRerunMetadata(attemptNum int, res *command.Result, rm *lpb.RemoteMetadata) *lpb.RerunMetadata {
+	return &lpb.RerunMetadata{
+		Attempt:                int64(attemptNum),
+		Result:                 command.ResultToProto(res),
+		NumOutputFiles:         rm.NumOutputFiles,
+		NumOutputDirectories:   rm.NumOutputDirectories,
+		TotalOutputBytes:       rm.TotalOutputBytes,
+		OutputFileDigests:      rm.OutputFileDigests,
+		OutputDirectoryDigests: rm.OutputDirectoryDigests,
+		LogicalBytesDownloaded: rm.LogicalBytesDownloaded,
+		RealBytesDownloaded:    rm.RealBytesDownloaded,
+		EventTimes:             rm.EventTimes,
+	}
+}
+
+// shouldVerifyRemote returns whether the result of the action is a remote cache hit to verify.
+func (a *action) shouldVerifyRemote() bool {
+	return a.remoteVerify && a.res != nil && a.res.Status == command.CacheHitResultStatus && a.rec.GetRemoteMetadata() != nil
+}
+
+// verifyRemoteAsync verifies a remote cache hit of the action after its response is returned, so
+// that the verification does not add to the latency of the action. The record of the action is
+// logged once the verification finishes. Shutdown and FinishInvocation wait for the verification.
+func (s *Server) verifyRemoteAsync(a *action) {
+	s.wgShutdown.Add(1)
+	s.retainInvocation(a.inv)
+	go func() {
+		defer s.wgShutdown.Done()
+		defer s.endInvocationAction(a.inv)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(a.reclientTimeout)*time.Second)
+		defer cancel()
+		s.verifyRemote(ctx, a)
+		s.logAction(a)
+	}()
+}
+
+// verifyRemote executes a remote cache hit remotely again, without accepting or updating cached
+// results, and compares the outputs with those of the cached result. The cached result is
+// recorded as the first remote rerun of the action and the verifying execution as the second.
+func (s *Server) verifyRemote(ctx context.Context, a *action) {
+	act := a.duplicate(1)[0]
+	act.rOpt.AcceptCached = false
+	act.rOpt.DoNotCache = true
+	act.rOpt.DownloadOutputs = false
+	act.runRemote(ctx, s.REClient)
+	if !act.res.IsOk() {
+		log.Warningf("%v: Execution failed during remote verification with %v", act.cmd.Identifiers.ExecutionID, act.res)
+	}
+	// The action digests differ since the verifying action is not cached, but the commands must not.
+	if got, want := act.rec.GetRemoteMetadata().GetCommandDigest(), a.rec.RemoteMetadata.GetCommandDigest(); got != want {
+		log.Warningf("%v: Remote verification executed command %v instead of cached command %v, skipping comparison", a.cmd.Identifiers.ExecutionID, got, want)
+		return
+	}
+	a.numLocalReruns = 0
+	a.numRemoteReruns = 2
+	a.rec.RemoteMetadata.RerunMetadata = []*lpb.RerunMetadata{
+		remoteRerunMetadata(1, a.res, a.rec.RemoteMetadata),
+		remoteRerunMetadata(2, act.res, act.rec.RemoteMetadata),
+	}
+	compareAction(ctx, s, a)
+}
+
 func (s *Server) runLERC(ctx context.Context, a *action) {
 	if err := a.createExecContext(ctx, s.REClient); err != nil {
 		// This cannot really happen, as the only error it checks for is cmd.Validate.
@@ -839,18 +1114,28 @@ func (s *Server) runLERC(ctx context.Context, a *action) {
 		}
 	}
 	if !a.lOpt.GetAcceptCached() {
+		s.downloadVirtualInputs(ctx, a)
 		a.runLocal(ctx, s.LocalPool)
 		a.cacheLocal()
 		return
 	}
 	a.getCachedResult(ctx)
 	if a.res == nil || !a.res.IsOk() {
+		s.downloadVirtualInputs(ctx, a)
 		a.runLocal(ctx, s.LocalPool)
 		a.cacheLocal()
 		return
 	}
 }
 
+// downloadVirtualInputs fetches the virtual inputs of a processed LERC action, which include
+// virtual outputs of previous actions, before executing it locally.
+func (s *Server) downloadVirtualInputs(ctx context.Context, a *action) {
+	if err := a.downloadVirtualInputs(ctx, s.REClient); err != nil {
+		log.Warningf("%v: Failed to download virtual inputs before LERC run: %v", a.cmd.Identifiers.ExecutionID, err)
+	}
+}
+
 func (s *Server) runRacing(ctx context.Context, a *action) {
 	if features.GetConfig().CleanIncludePaths {
 		a.cmd.Args = cleanIncludePaths(a.cmd)
@@ -861,7 +1146,7 @@ func (s *Server) runRacing(ctx context.Context, a *action) {
 	}
 	log.V(1).Infof("%v: Inputs: %v, Outputs: %+v", a.cmd.Identifiers.ExecutionID, a.cmd.InputSpec.Inputs, a.cmd.OutputFiles)
 
-	a.race(ctx, s.REClient, s.LocalPool, s.numFallbacks, s.MaxHoldoff)
+	a.race(ctx, s.REClient, s.LocalPool, a.inv.numFallbacks, s.MaxHoldoff)
 	if a.res == nil {
 		a.res = command.NewLocalErrorResult(fmt.Errorf("racing did not produce a result"))
 	}
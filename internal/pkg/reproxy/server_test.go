@@ -28,9 +28,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/bazelbuild/reclient/internal/pkg/actioncache"
 	"github.com/bazelbuild/reclient/internal/pkg/deps"
 	"github.com/bazelbuild/reclient/internal/pkg/event"
+	"github.com/bazelbuild/reclient/internal/pkg/execpolicy"
 	"github.com/bazelbuild/reclient/internal/pkg/execroot"
+	"github.com/bazelbuild/reclient/internal/pkg/localcas"
 	"github.com/bazelbuild/reclient/internal/pkg/localresources"
 	"github.com/bazelbuild/reclient/internal/pkg/logger"
 	"github.com/bazelbuild/reclient/internal/pkg/stats"
@@ -43,6 +46,7 @@ import (
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/fakes"
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/filemetadata"
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/outerr"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/rexec"
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -59,7 +63,7 @@ var cmpLogRecordsOpts = []cmp.Option{
 	protocmp.IgnoreFields(&cpb.Command{}, "identifiers"),
 	protocmp.IgnoreFields(&cpb.CommandResult{}, "msg"),
 	protocmp.IgnoreFields(&lpb.RemoteMetadata{}, "action_digest", "command_digest", "total_input_bytes", "logical_bytes_uploaded", "real_bytes_uploaded", "logical_bytes_downloaded", "real_bytes_downloaded", "event_times", "stderr_digest", "stdout_digest"),
-	protocmp.IgnoreFields(&lpb.LocalMetadata{}, "event_times"),
+	protocmp.IgnoreFields(&lpb.LocalMetadata{}, "event_times", "resource_usage"),
 	protocmp.IgnoreFields(&lpb.RerunMetadata{}, "event_times"),
 	protocmp.IgnoreFields(&lpb.Verification_Mismatch{}, "action_digest"),
 	protocmp.SortRepeated(func(a, b string) bool { return a < b }),
@@ -854,7 +858,7 @@ func TestRemoteWithPreserveUnchangedOutputMtime(t *testing.T) {
 				protocmp.IgnoreFields(&cpb.Command{}, "identifiers"),
 				protocmp.IgnoreFields(&cpb.CommandResult{}, "msg"),
 				protocmp.IgnoreFields(&lpb.RemoteMetadata{}, "action_digest", "command_digest", "total_input_bytes", "real_bytes_uploaded", "real_bytes_downloaded", "event_times", "stderr_digest", "stdout_digest"),
-				protocmp.IgnoreFields(&lpb.LocalMetadata{}, "event_times"),
+				protocmp.IgnoreFields(&lpb.LocalMetadata{}, "event_times", "resource_usage"),
 				protocmp.IgnoreFields(&lpb.RerunMetadata{}, "event_times"),
 				protocmp.IgnoreFields(&lpb.Verification_Mismatch{}, "action_digest"),
 				protocmp.SortRepeated(func(a, b string) bool { return a < b }),
@@ -3640,6 +3644,261 @@ func TestNoRerunWhenNoCompareMode(t *testing.T) {
 	}
 }
 
+func TestRemoteVerify(t *testing.T) {
+	tests := []struct {
+		name       string
+		fresh      string
+		wantVerify *lpb.Verification
+	}{
+		{
+			name:  "match",
+			fresh: "cached",
+			wantVerify: &lpb.Verification{
+				TotalVerified: 1,
+			},
+		},
+		{
+			name:  "mismatch",
+			fresh: "fresh",
+			wantVerify: &lpb.Verification{
+				TotalMismatches: 1,
+				TotalVerified:   1,
+				Mismatches: []*lpb.Verification_Mismatch{{
+					Path:          filepath.ToSlash(abOutPath),
+					RemoteDigests: []string{digest.NewFromBlob([]byte("cached")).String(), digest.NewFromBlob([]byte("fresh")).String()},
+					Determinism:   lpb.DeterminismStatus_NON_DETERMINISTIC,
+				}},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			env, cleanup := fakes.NewTestEnv(t)
+			fmc := filemetadata.NewSingleFlightCache()
+			env.Client.FileMetadataCache = fmc
+			t.Cleanup(cleanup)
+			resMgr := localresources.NewDefaultManager()
+			server := &Server{
+				LocalPool:         NewLocalPool(&subprocess.SystemExecutor{}, resMgr),
+				FileMetadataStore: fmc,
+				MaxHoldoff:        time.Minute,
+				DownloadTmp:       t.TempDir(),
+			}
+			server.Init()
+			server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
+			server.SetREClient(env.Client, func() {})
+			lg, err := logger.New(logger.TextFormat, env.ExecRoot, stats.New(), nil, nil, nil)
+			if err != nil {
+				t.Errorf("error initializing logger: %v", err)
+			}
+			server.Logger = lg
+			cmdArgs := []string{"fake-exec"}
+			ctx := context.Background()
+			req := &ppb.RunRequest{
+				Command: &cpb.Command{
+					Args:     cmdArgs,
+					ExecRoot: env.ExecRoot,
+					Output: &cpb.OutputSpec{
+						OutputFiles: []string{abOutPath},
+					},
+				},
+				Labels: map[string]string{"type": "tool"},
+				ExecutionOptions: &ppb.ProxyExecutionOptions{
+					ExecutionStrategy:      ppb.ExecutionStrategy_REMOTE,
+					RemoteExecutionOptions: &ppb.RemoteExecutionOptions{AcceptCached: true, DownloadOutputs: true},
+					RemoteVerify:           true,
+					ReclientTimeout:        3600,
+					IncludeActionLog:       true,
+				},
+			}
+			cmd := &command.Command{
+				Identifiers: &command.Identifiers{},
+				Args:        cmdArgs,
+				ExecRoot:    env.ExecRoot,
+				InputSpec:   &command.InputSpec{},
+				OutputFiles: []string{abOutPath},
+			}
+			setPlatformOSFamily(cmd)
+			// The cached result, followed by the result of the verifying execution which neither
+			// accepts nor updates cached results.
+			env.Set(cmd, command.DefaultExecutionOptions(), &command.Result{Status: command.CacheHitResultStatus}, &fakes.OutputFile{abOutPath, "cached"})
+			verifyOpts := command.DefaultExecutionOptions()
+			verifyOpts.DoNotCache = true
+			env.Set(cmd, verifyOpts, &command.Result{Status: command.SuccessResultStatus}, &fakes.OutputFile{abOutPath, tc.fresh})
+
+			got, err := server.RunCommand(ctx, req)
+			if err != nil {
+				t.Fatalf("RunCommand() returned error: %v", err)
+			}
+			if got.GetResult().GetStatus() != cpb.CommandResultStatus_CACHE_HIT {
+				t.Errorf("RunCommand() returned status %v, want %v", got.GetResult().GetStatus(), cpb.CommandResultStatus_CACHE_HIT)
+			}
+			// The response is returned without waiting for the verification.
+			if v := got.GetActionLog().GetLocalMetadata().GetVerification(); v != nil {
+				t.Errorf("RunCommand() returned action log with verification %v, want none", v)
+			}
+			path := filepath.Join(env.ExecRoot, abOutPath)
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				t.Errorf("error reading from %s: %v", path, err)
+			}
+			if string(contents) != "cached" {
+				t.Errorf("RunCommand output %s: %q; want %q", path, contents, "cached")
+			}
+			server.DrainAndReleaseResources()
+			recs, _, err := logger.ParseFromLogDirs(logger.TextFormat, []string{env.ExecRoot})
+			if err != nil {
+				t.Fatalf("logger.ParseFromLogDirs failed: %v", err)
+			}
+			if len(recs) != 1 {
+				t.Fatalf("logger.ParseFromLogDirs returned %v records, want 1", len(recs))
+			}
+			opts := []cmp.Option{
+				protocmp.Transform(),
+				protocmp.IgnoreFields(&lpb.Verification_Mismatch{}, "action_digest"),
+				protocmp.SortRepeated(func(a, b string) bool { return a < b }),
+			}
+			if diff := cmp.Diff(tc.wantVerify, recs[0].GetLocalMetadata().GetVerification(), opts...); diff != "" {
+				t.Errorf("RunCommand() recorded diff in verification (-want +got):\n%s", diff)
+			}
+			if n := len(recs[0].GetRemoteMetadata().GetRerunMetadata()); n != 2 {
+				t.Errorf("RunCommand() recorded %v remote reruns, want 2", n)
+			}
+		})
+	}
+}
+
+func TestRemoteHedged(t *testing.T) {
+	tests := []struct {
+		name         string
+		forecast     bool
+		hedgeDelay   time.Duration
+		wantStatus   lpb.CompletionStatus
+		wantHedged   bool
+		wantHedgeWon bool
+	}{
+		{
+			name:       "no forecast",
+			wantStatus: lpb.CompletionStatus_STATUS_REMOTE_EXECUTION,
+		},
+		{
+			name:         "no forecast with default delay",
+			hedgeDelay:   time.Millisecond,
+			wantStatus:   lpb.CompletionStatus_STATUS_HEDGED_SECONDARY,
+			wantHedged:   true,
+			wantHedgeWon: true,
+		},
+		{
+			name:         "hedge wins",
+			forecast:     true,
+			wantStatus:   lpb.CompletionStatus_STATUS_HEDGED_SECONDARY,
+			wantHedged:   true,
+			wantHedgeWon: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			env, cleanup := fakes.NewTestEnv(t)
+			fmc := filemetadata.NewSingleFlightCache()
+			env.Client.FileMetadataCache = fmc
+			t.Cleanup(cleanup)
+			resMgr := localresources.NewDefaultManager()
+			lbls := map[string]string{"type": "tool"}
+			f := &Forecast{}
+			if tc.forecast {
+				for i := 0; i < minLabelModelSamples; i++ {
+					f.RecordSample(actionWithOutcome(lbls, nil, false, 1, 0))
+				}
+			}
+			server := &Server{
+				LocalPool:         NewLocalPool(&subprocess.SystemExecutor{}, resMgr),
+				FileMetadataStore: fmc,
+				Forecast:          f,
+				MaxHoldoff:        time.Minute,
+				HedgeDelay:        tc.hedgeDelay,
+				DownloadTmp:       t.TempDir(),
+			}
+			server.Init()
+			server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
+			server.SetREClient(env.Client, func() {})
+			lg, err := logger.New(logger.TextFormat, env.ExecRoot, stats.New(), nil, nil, nil)
+			if err != nil {
+				t.Errorf("error initializing logger: %v", err)
+			}
+			server.Logger = lg
+			cmdArgs := []string{"fake-exec"}
+			req := &ppb.RunRequest{
+				Command: &cpb.Command{
+					Args:     cmdArgs,
+					ExecRoot: env.ExecRoot,
+					Output: &cpb.OutputSpec{
+						OutputFiles: []string{abOutPath},
+					},
+				},
+				Labels: lbls,
+				ExecutionOptions: &ppb.ProxyExecutionOptions{
+					ExecutionStrategy:      ppb.ExecutionStrategy_REMOTE_HEDGED,
+					RemoteExecutionOptions: &ppb.RemoteExecutionOptions{AcceptCached: true, DownloadOutputs: true},
+					ReclientTimeout:        3600,
+				},
+			}
+			cmd := &command.Command{
+				Identifiers: &command.Identifiers{},
+				Args:        cmdArgs,
+				ExecRoot:    env.ExecRoot,
+				InputSpec:   &command.InputSpec{},
+				OutputFiles: []string{abOutPath},
+			}
+			setPlatformOSFamily(cmd)
+			env.Set(cmd, command.DefaultExecutionOptions(), &command.Result{Status: command.SuccessResultStatus}, &fakes.OutputFile{abOutPath, "output"})
+			// The primary execution only completes once it is canceled.
+			ctx := context.WithValue(context.Background(), testOnlyBlockHedgedPrimaryKey, func(pCtx context.Context) {
+				if tc.wantHedged {
+					<-pCtx.Done()
+				}
+			})
+
+			got, err := server.RunCommand(ctx, req)
+			if err != nil {
+				t.Fatalf("RunCommand() returned error: %v", err)
+			}
+			if got.GetResult().GetStatus() != cpb.CommandResultStatus_SUCCESS {
+				t.Errorf("RunCommand() returned status %v, want %v", got.GetResult().GetStatus(), cpb.CommandResultStatus_SUCCESS)
+			}
+			path := filepath.Join(env.ExecRoot, abOutPath)
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				t.Errorf("error reading from %s: %v", path, err)
+			}
+			if string(contents) != "output" {
+				t.Errorf("RunCommand output %s: %q; want %q", path, contents, "output")
+			}
+			server.DrainAndReleaseResources()
+			recs, _, err := logger.ParseFromLogDirs(logger.TextFormat, []string{env.ExecRoot})
+			if err != nil {
+				t.Fatalf("logger.ParseFromLogDirs failed: %v", err)
+			}
+			if len(recs) != 1 {
+				t.Fatalf("logger.ParseFromLogDirs returned %v records, want 1", len(recs))
+			}
+			rec := recs[0]
+			if rec.GetCompletionStatus() != tc.wantStatus {
+				t.Errorf("RunCommand() recorded completion status %v, want %v", rec.GetCompletionStatus(), tc.wantStatus)
+			}
+			if rec.GetRemoteMetadata().GetHedged() != tc.wantHedged || rec.GetRemoteMetadata().GetHedgeWon() != tc.wantHedgeWon {
+				t.Errorf("RunCommand() recorded hedged=%v, hedge_won=%v, want %v, %v", rec.GetRemoteMetadata().GetHedged(), rec.GetRemoteMetadata().GetHedgeWon(), tc.wantHedged, tc.wantHedgeWon)
+			}
+			for _, e := range []string{event.HedgeHoldoff, event.HedgedRemoteExecution} {
+				if _, ok := rec.GetLocalMetadata().GetEventTimes()[e]; ok != tc.wantHedged {
+					t.Errorf("RunCommand() recorded event %v: %v, want %v", e, ok, tc.wantHedged)
+				}
+			}
+		})
+	}
+}
+
 func TestRemoteLocalFallback(t *testing.T) {
 	env, cleanup := fakes.NewTestEnv(t)
 	fmc := filemetadata.NewSingleFlightCache()
@@ -3749,104 +4008,281 @@ func TestRemoteLocalFallback(t *testing.T) {
 	}
 }
 
-func TestForwardErrorLog(t *testing.T) {
+func TestRemoteLocalFallbackLoadShed(t *testing.T) {
 	env, cleanup := fakes.NewTestEnv(t)
-	t.Cleanup(cleanup)
 	fmc := filemetadata.NewSingleFlightCache()
 	env.Client.FileMetadataCache = fmc
+	t.Cleanup(cleanup)
 	resMgr := localresources.NewDefaultManager()
 	server := &Server{
-		MaxHoldoff:        time.Minute,
-		DownloadTmp:       t.TempDir(),
-		FileMetadataStore: fmc,
+		LocalPool:             NewLocalPool(&subprocess.SystemExecutor{}, resMgr),
+		MaxHoldoff:            time.Minute,
+		DownloadTmp:           t.TempDir(),
+		FileMetadataStore:     fmc,
+		LoadShedMaxErrorRatio: 0.5,
+		LoadShedMinActions:    2,
+		LoadShedCooldown:      time.Hour,
 	}
 	server.Init()
 	server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
 	server.SetREClient(env.Client, func() {})
+	lg, err := logger.New(logger.TextFormat, env.ExecRoot, stats.New(), nil, nil, nil)
+	if err != nil {
+		t.Errorf("error initializing logger: %v", err)
+	}
+	server.Logger = lg
+	var cmdArgs []string
+	if runtime.GOOS == "windows" {
+		cmdArgs = []string{"cmd", "/c", fmt.Sprintf("mkdir %s && echo hello>%s", abPath, abOutPath)}
+	} else {
+		cmdArgs = []string{"/bin/bash", "-c", fmt.Sprintf("mkdir -p %s && echo hello > %s", abPath, abOutPath)}
+	}
 	ctx := context.Background()
-	// There is an error in the mock code which returns CacheHitResultStatus for RemoteErrorResultStatus. We need to disable the cache here
-	// in order to get the expected response.
-	remoteExecOptions := &ppb.RemoteExecutionOptions{AcceptCached: false}
 	req := &ppb.RunRequest{
 		Command: &cpb.Command{
-			Args:     []string{"clang", "-o", abOutPath, "a"},
+			Args:     cmdArgs,
 			ExecRoot: env.ExecRoot,
 			Output: &cpb.OutputSpec{
 				OutputFiles: []string{abOutPath},
 			},
 		},
-		Labels:           map[string]string{"type": "compile", "lang": "cpp", "compiler": "clang"},
-		ExecutionOptions: &ppb.ProxyExecutionOptions{ExecutionStrategy: ppb.ExecutionStrategy_REMOTE, RemoteExecutionOptions: remoteExecOptions, ReclientTimeout: 3600},
+		Labels: map[string]string{"type": "tool", "shallow": "true"},
+		ExecutionOptions: &ppb.ProxyExecutionOptions{
+			ExecutionStrategy:      ppb.ExecutionStrategy_REMOTE_LOCAL_FALLBACK,
+			RemoteExecutionOptions: &ppb.RemoteExecutionOptions{DoNotCache: true},
+			ReclientTimeout:        3600,
+		},
 	}
-	wantCmd := &command.Command{
+	cmd := &command.Command{
 		Identifiers: &command.Identifiers{},
-		Args:        []string{"clang", "-o", abOutPath, "a"},
+		Args:        cmdArgs,
 		ExecRoot:    env.ExecRoot,
 		InputSpec:   &command.InputSpec{},
 		OutputFiles: []string{abOutPath},
 	}
-	setPlatformOSFamily(wantCmd)
-	errorMsg := "An Error Occured"
-	internalErrorMsg := "rpc error: code = Internal desc = "
-	res := &command.Result{Status: command.RemoteErrorResultStatus, Err: errors.New(errorMsg), ExitCode: 45}
-	env.Set(wantCmd, command.DefaultExecutionOptions(), res)
-	got, err := server.RunCommand(ctx, req)
-	wantErrPrefix := "reclient[" + got.ExecutionId + "]: " + command.RemoteErrorResultStatus.String() + ": "
+	setPlatformOSFamily(cmd)
+	// Don't cache the remote error so that every action is executed remotely.
+	opts := command.DefaultExecutionOptions()
+	opts.DoNotCache = true
+	env.Set(cmd, opts, &command.Result{Status: command.RemoteErrorResultStatus, Err: errors.New("backend unavailable")})
+	// The first two actions fail remotely, which opens the breaker, and the third is shed.
+	for i := 0; i < 3; i++ {
+		got, err := server.RunCommand(ctx, req)
+		if err != nil {
+			t.Fatalf("RunCommand() returned error: %v", err)
+		}
+		if got.GetResult().GetStatus() != cpb.CommandResultStatus_SUCCESS {
+			t.Errorf("RunCommand() returned status %v, want %v", got.GetResult().GetStatus(), cpb.CommandResultStatus_SUCCESS)
+		}
+	}
+	server.DrainAndReleaseResources()
+	recs, _, err := logger.ParseFromLogDirs(logger.TextFormat, []string{env.ExecRoot})
 	if err != nil {
-		t.Errorf("RunCommand() returned error: %v", err)
+		t.Fatalf("logger.ParseFromLogDirs failed: %v", err)
 	}
-	want := &ppb.RunResponse{
-		Result: &cpb.CommandResult{
-			Status:   cpb.CommandResultStatus_REMOTE_ERROR,
-			ExitCode: 45,
-			Msg:      internalErrorMsg + errorMsg,
-		},
-		Stderr: []byte(wantErrPrefix + internalErrorMsg + errorMsg + "\n"),
+	var gotStatuses []lpb.CompletionStatus
+	for _, rec := range recs {
+		gotStatuses = append(gotStatuses, rec.GetCompletionStatus())
 	}
-	if diff := cmp.Diff(want, got, protocmp.IgnoreFields(&ppb.RunResponse{}, "execution_id"), protocmp.Transform()); diff != "" {
-		t.Errorf("RunCommand() returned diff in result: (-want +got)\n%s", diff)
+	wantStatuses := []lpb.CompletionStatus{
+		lpb.CompletionStatus_STATUS_LOCAL_FALLBACK,
+		lpb.CompletionStatus_STATUS_LOCAL_FALLBACK,
+		lpb.CompletionStatus_STATUS_LOAD_SHED,
+	}
+	if diff := cmp.Diff(wantStatuses, gotStatuses); diff != "" {
+		t.Errorf("RunCommand() recorded diff in completion statuses: (-want +got)\n%s", diff)
+	}
+	if shed := recs[len(recs)-1]; shed.GetRemoteMetadata() != nil || !shed.GetLocalMetadata().GetLoadShed() {
+		t.Errorf("Shed action recorded remote metadata %v, load_shed=%v, want no remote metadata and load_shed=true", shed.GetRemoteMetadata(), shed.GetLocalMetadata().GetLoadShed())
 	}
 }
 
-func TestFailEarlyOnIpTimeouts(t *testing.T) {
+func TestExecutionPolicyOverride(t *testing.T) {
 	env, cleanup := fakes.NewTestEnv(t)
 	fmc := filemetadata.NewSingleFlightCache()
 	env.Client.FileMetadataCache = fmc
 	t.Cleanup(cleanup)
+	policyPath := filepath.Join(t.TempDir(), "policy.textproto")
+	policyConfig := `
+rules {
+  name: "local-tools"
+  labels {
+    key: "type"
+    value: "tool"
+  }
+  execution_options {
+    execution_strategy: LOCAL
+  }
+}`
+	if err := os.WriteFile(policyPath, []byte(policyConfig), 0644); err != nil {
+		t.Fatalf("Failed to write policy config: %v", err)
+	}
+	policy, err := execpolicy.New(policyPath)
+	if err != nil {
+		t.Fatalf("execpolicy.New() returned error: %v", err)
+	}
 	resMgr := localresources.NewDefaultManager()
-
+	server := &Server{
+		LocalPool:         NewLocalPool(&subprocess.SystemExecutor{}, resMgr),
+		MaxHoldoff:        time.Minute,
+		DownloadTmp:       t.TempDir(),
+		FileMetadataStore: fmc,
+		Policy:            policy,
+	}
+	server.Init()
+	server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
+	server.SetREClient(env.Client, func() {})
+	lg, err := logger.New(logger.TextFormat, env.ExecRoot, stats.New(), nil, nil, nil)
+	if err != nil {
+		t.Errorf("error initializing logger: %v", err)
+	}
+	server.Logger = lg
+	var cmdArgs []string
+	if runtime.GOOS == "windows" {
+		cmdArgs = []string{"cmd", "/c", fmt.Sprintf("mkdir %s && echo hello>%s", abPath, abOutPath)}
+	} else {
+		cmdArgs = []string{"/bin/bash", "-c", fmt.Sprintf("mkdir -p %s && echo hello > %s", abPath, abOutPath)}
+	}
 	req := &ppb.RunRequest{
 		Command: &cpb.Command{
-			Args:     []string{"clang", "-o", abOutPath, "a"},
+			Args:     cmdArgs,
 			ExecRoot: env.ExecRoot,
 			Output: &cpb.OutputSpec{
 				OutputFiles: []string{abOutPath},
 			},
 		},
-		Labels:           map[string]string{"type": "compile", "lang": "cpp", "compiler": "clang"},
+		Labels: map[string]string{"type": "tool"},
+		// Remote execution is not set up in the fake, so the action only succeeds if the
+		// policy makes it run locally.
 		ExecutionOptions: &ppb.ProxyExecutionOptions{ExecutionStrategy: ppb.ExecutionStrategy_REMOTE, ReclientTimeout: 3600},
 	}
-
-	cmdsPerTest := 20
-	tests := []struct {
-		name     string
-		depsErr  error
-		maxExecs int
-	}{
-		{
-			name:    "deps scan timeouts should trigger fail early condition",
-			depsErr: fmt.Errorf("deps scan timeout: %w", context.DeadlineExceeded),
-			// because MonitorFailBuildConditions is called asynchronously
-			// maxExecs can be either equal to or greater by one than AllowedIPTimeouts
-			maxExecs: int(AllowedIPTimeouts) + 1,
-		},
-		{
-			name:     "non-timeout errors should not trigger fail early condition",
-			depsErr:  fmt.Errorf("deps scan error: %w", context.Canceled),
-			maxExecs: cmdsPerTest,
-		},
+	got, err := server.RunCommand(context.Background(), req)
+	if err != nil {
+		t.Fatalf("RunCommand() returned error: %v", err)
 	}
-	for _, test := range tests {
+	if got.GetResult().GetStatus() != cpb.CommandResultStatus_SUCCESS {
+		t.Errorf("RunCommand() returned status %v, want %v", got.GetResult().GetStatus(), cpb.CommandResultStatus_SUCCESS)
+	}
+	if req.GetExecutionOptions().GetExecutionStrategy() != ppb.ExecutionStrategy_REMOTE {
+		t.Errorf("RunCommand() modified the execution strategy of the request to %v", req.GetExecutionOptions().GetExecutionStrategy())
+	}
+	server.DrainAndReleaseResources()
+	recs, _, err := logger.ParseFromLogDirs(logger.TextFormat, []string{env.ExecRoot})
+	if err != nil {
+		t.Fatalf("logger.ParseFromLogDirs failed: %v", err)
+	}
+	if len(recs) != 1 {
+		t.Fatalf("logger.ParseFromLogDirs returned %v records, want 1", len(recs))
+	}
+	if recs[0].GetCompletionStatus() != lpb.CompletionStatus_STATUS_LOCAL_EXECUTION {
+		t.Errorf("RunCommand() recorded completion status %v, want %v", recs[0].GetCompletionStatus(), lpb.CompletionStatus_STATUS_LOCAL_EXECUTION)
+	}
+	wantOverrides := []*lpb.PolicyOverride{
+		{Rule: "local-tools", Field: "execution_options.execution_strategy", OriginalValue: "REMOTE", Value: "LOCAL"},
+	}
+	if diff := cmp.Diff(wantOverrides, recs[0].GetLocalMetadata().GetPolicyOverrides(), protocmp.Transform()); diff != "" {
+		t.Errorf("RunCommand() recorded diff in policy overrides: (-want +got)\n%s", diff)
+	}
+}
+
+func TestForwardErrorLog(t *testing.T) {
+	env, cleanup := fakes.NewTestEnv(t)
+	t.Cleanup(cleanup)
+	fmc := filemetadata.NewSingleFlightCache()
+	env.Client.FileMetadataCache = fmc
+	resMgr := localresources.NewDefaultManager()
+	server := &Server{
+		MaxHoldoff:        time.Minute,
+		DownloadTmp:       t.TempDir(),
+		FileMetadataStore: fmc,
+	}
+	server.Init()
+	server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
+	server.SetREClient(env.Client, func() {})
+	ctx := context.Background()
+	// There is an error in the mock code which returns CacheHitResultStatus for RemoteErrorResultStatus. We need to disable the cache here
+	// in order to get the expected response.
+	remoteExecOptions := &ppb.RemoteExecutionOptions{AcceptCached: false}
+	req := &ppb.RunRequest{
+		Command: &cpb.Command{
+			Args:     []string{"clang", "-o", abOutPath, "a"},
+			ExecRoot: env.ExecRoot,
+			Output: &cpb.OutputSpec{
+				OutputFiles: []string{abOutPath},
+			},
+		},
+		Labels:           map[string]string{"type": "compile", "lang": "cpp", "compiler": "clang"},
+		ExecutionOptions: &ppb.ProxyExecutionOptions{ExecutionStrategy: ppb.ExecutionStrategy_REMOTE, RemoteExecutionOptions: remoteExecOptions, ReclientTimeout: 3600},
+	}
+	wantCmd := &command.Command{
+		Identifiers: &command.Identifiers{},
+		Args:        []string{"clang", "-o", abOutPath, "a"},
+		ExecRoot:    env.ExecRoot,
+		InputSpec:   &command.InputSpec{},
+		OutputFiles: []string{abOutPath},
+	}
+	setPlatformOSFamily(wantCmd)
+	errorMsg := "An Error Occured"
+	internalErrorMsg := "rpc error: code = Internal desc = "
+	res := &command.Result{Status: command.RemoteErrorResultStatus, Err: errors.New(errorMsg), ExitCode: 45}
+	env.Set(wantCmd, command.DefaultExecutionOptions(), res)
+	got, err := server.RunCommand(ctx, req)
+	wantErrPrefix := "reclient[" + got.ExecutionId + "]: " + command.RemoteErrorResultStatus.String() + ": "
+	if err != nil {
+		t.Errorf("RunCommand() returned error: %v", err)
+	}
+	want := &ppb.RunResponse{
+		Result: &cpb.CommandResult{
+			Status:   cpb.CommandResultStatus_REMOTE_ERROR,
+			ExitCode: 45,
+			Msg:      internalErrorMsg + errorMsg,
+		},
+		Stderr: []byte(wantErrPrefix + internalErrorMsg + errorMsg + "\n"),
+	}
+	if diff := cmp.Diff(want, got, protocmp.IgnoreFields(&ppb.RunResponse{}, "execution_id"), protocmp.Transform()); diff != "" {
+		t.Errorf("RunCommand() returned diff in result: (-want +got)\n%s", diff)
+	}
+}
+
+func TestFailEarlyOnIpTimeouts(t *testing.T) {
+	env, cleanup := fakes.NewTestEnv(t)
+	fmc := filemetadata.NewSingleFlightCache()
+	env.Client.FileMetadataCache = fmc
+	t.Cleanup(cleanup)
+	resMgr := localresources.NewDefaultManager()
+
+	req := &ppb.RunRequest{
+		Command: &cpb.Command{
+			Args:     []string{"clang", "-o", abOutPath, "a"},
+			ExecRoot: env.ExecRoot,
+			Output: &cpb.OutputSpec{
+				OutputFiles: []string{abOutPath},
+			},
+		},
+		Labels:           map[string]string{"type": "compile", "lang": "cpp", "compiler": "clang"},
+		ExecutionOptions: &ppb.ProxyExecutionOptions{ExecutionStrategy: ppb.ExecutionStrategy_REMOTE, ReclientTimeout: 3600},
+	}
+
+	cmdsPerTest := 20
+	tests := []struct {
+		name     string
+		depsErr  error
+		maxExecs int
+	}{
+		{
+			name:    "deps scan timeouts should trigger fail early condition",
+			depsErr: fmt.Errorf("deps scan timeout: %w", context.DeadlineExceeded),
+			// because MonitorFailBuildConditions is called asynchronously
+			// maxExecs can be either equal to or greater by one than AllowedIPTimeouts
+			maxExecs: int(AllowedIPTimeouts) + 1,
+		},
+		{
+			name:     "non-timeout errors should not trigger fail early condition",
+			depsErr:  fmt.Errorf("deps scan error: %w", context.Canceled),
+			maxExecs: cmdsPerTest,
+		},
+	}
+	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			depScanner := &stubCPPDependencyScanner{
 				processInputsError: test.depsErr,
@@ -4055,6 +4491,202 @@ func TestFailEarly(t *testing.T) {
 	}
 }
 
+func TestFailEarlyPerInvocation(t *testing.T) {
+	env, cleanup := fakes.NewTestEnv(t)
+	fmc := filemetadata.NewSingleFlightCache()
+	env.Client.FileMetadataCache = fmc
+	t.Cleanup(cleanup)
+	resMgr := localresources.NewDefaultManager()
+	server := &Server{
+		LocalPool:                 NewLocalPool(&subprocess.SystemExecutor{}, resMgr),
+		FailEarlyMinActionCount:   1,
+		FailEarlyMinFallbackRatio: 0.5,
+		MaxHoldoff:                time.Minute,
+		DownloadTmp:               t.TempDir(),
+		FileMetadataStore:         fmc,
+	}
+	server.Init()
+	server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
+	server.SetREClient(env.Client, func() {})
+	lg, err := logger.New(logger.TextFormat, env.ExecRoot, stats.New(), nil, nil, nil)
+	if err != nil {
+		t.Errorf("error initializing logger: %v", err)
+	}
+	server.Logger = lg
+	t.Cleanup(server.DrainAndReleaseResources)
+	var cmdArgs []string
+	if runtime.GOOS == "windows" {
+		cmdArgs = []string{"cmd", "/c", fmt.Sprintf("mkdir %s && echo hellos>%s", abPath, abOutPath)}
+	} else {
+		cmdArgs = []string{"/bin/bash", "-c", fmt.Sprintf("mkdir -p %s && echo hellos > %s", abPath, abOutPath)}
+	}
+	newReq := func(invID string, platform map[string]string) *ppb.RunRequest {
+		return &ppb.RunRequest{
+			Command: &cpb.Command{
+				Identifiers: &cpb.Identifiers{InvocationId: invID},
+				Args:        cmdArgs,
+				ExecRoot:    env.ExecRoot,
+				Platform:    platform,
+				Output: &cpb.OutputSpec{
+					OutputFiles: []string{abOutPath},
+				},
+			},
+			Labels:           map[string]string{"type": "tool"},
+			ExecutionOptions: &ppb.ProxyExecutionOptions{ExecutionStrategy: ppb.ExecutionStrategy_REMOTE_LOCAL_FALLBACK, ReclientTimeout: 3600},
+		}
+	}
+	// Remote execution fails for the actions of invocation a and succeeds for those of invocation b.
+	cmdFallback := &command.Command{
+		Identifiers: &command.Identifiers{},
+		Args:        cmdArgs,
+		ExecRoot:    env.ExecRoot,
+		InputSpec:   &command.InputSpec{},
+		OutputFiles: []string{abOutPath},
+	}
+	setPlatformOSFamily(cmdFallback)
+	cmdSuccess := &command.Command{
+		Identifiers: &command.Identifiers{},
+		Args:        cmdArgs,
+		ExecRoot:    env.ExecRoot,
+		InputSpec:   &command.InputSpec{},
+		Platform:    map[string]string{"key": "value"},
+		OutputFiles: []string{abOutPath},
+	}
+	setPlatformOSFamily(cmdSuccess)
+	ctx := context.Background()
+	// run runs an action of the given invocation and returns whether it failed early.
+	run := func(invID string) bool {
+		t.Helper()
+		var platform map[string]string
+		if invID == "a" {
+			env.Set(cmdFallback, command.DefaultExecutionOptions(), &command.Result{Status: command.NonZeroExitResultStatus, ExitCode: 5})
+		} else {
+			platform = map[string]string{"key": "value"}
+			env.Set(cmdSuccess, command.DefaultExecutionOptions(), &command.Result{Status: command.SuccessResultStatus})
+		}
+		got, err := server.RunCommand(ctx, newReq(invID, platform))
+		if err != nil {
+			t.Fatalf("RunCommand(%v) returned error: %v", invID, err)
+		}
+		return got.GetResult().GetStatus() == cpb.CommandResultStatus_LOCAL_ERROR
+	}
+
+	if run("a") || run("b") {
+		t.Errorf("RunCommand() failed early before fail early conditions were checked")
+	}
+	server.checkFailBuild()
+	if !run("a") {
+		t.Errorf("RunCommand(a) did not fail early after its fallback ratio was exceeded")
+	}
+	if run("b") {
+		t.Errorf("RunCommand(b) failed early after the fallback ratio of a was exceeded")
+	}
+
+	tests := []struct {
+		invID         string
+		wantRecords   int64
+		wantFallbacks bool
+	}{
+		{invID: "a", wantRecords: 2, wantFallbacks: true},
+		{invID: "b", wantRecords: 2},
+	}
+	for _, tc := range tests {
+		resp, err := server.FinishInvocation(ctx, &ppb.FinishInvocationRequest{InvocationId: tc.invID})
+		if err != nil {
+			t.Fatalf("FinishInvocation(%v) returned error: %v", tc.invID, err)
+		}
+		if got := resp.GetStats().GetNumRecords(); got != tc.wantRecords {
+			t.Errorf("FinishInvocation(%v) returned stats of %v records, want %v", tc.invID, got, tc.wantRecords)
+		}
+		gotFallbacks := false
+		for _, st := range resp.GetStats().GetStats() {
+			if st.GetName() == "CompletionStatus" {
+				for _, c := range st.GetCountsByValue() {
+					gotFallbacks = gotFallbacks || c.GetName() == lpb.CompletionStatus_STATUS_LOCAL_FALLBACK.String()
+				}
+			}
+		}
+		if gotFallbacks != tc.wantFallbacks {
+			t.Errorf("FinishInvocation(%v) returned stats with local fallbacks = %v, want %v", tc.invID, gotFallbacks, tc.wantFallbacks)
+		}
+	}
+	// The fail early state of a finished invocation is forgotten.
+	if run("a") {
+		t.Errorf("RunCommand(a) failed early after FinishInvocation(a)")
+	}
+}
+
+func TestFinishInvocationWaitsForActionsInFlight(t *testing.T) {
+	server := &Server{}
+	server.Init()
+	inv, err := server.startInvocationAction("a")
+	if err != nil {
+		t.Fatalf("startInvocationAction(a) returned error: %v", err)
+	}
+	finished := make(chan error)
+	go func() {
+		_, err := server.FinishInvocation(context.Background(), &ppb.FinishInvocationRequest{InvocationId: "a"})
+		finished <- err
+	}()
+	// New actions of the invocation are rejected once it is being finished.
+	for {
+		server.invocationsMu.Lock()
+		finishing := inv.finishing
+		server.invocationsMu.Unlock()
+		if finishing {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := server.startInvocationAction("a"); err == nil {
+		t.Errorf("startInvocationAction(a) while finishing returned no error")
+	}
+	select {
+	case err := <-finished:
+		t.Fatalf("FinishInvocation(a) returned %v before the action in flight ended", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	server.endInvocationAction(inv)
+	if err := <-finished; err != nil {
+		t.Fatalf("FinishInvocation(a) returned error: %v", err)
+	}
+	if got := len(server.allInvocations()); got != 0 {
+		t.Errorf("FinishInvocation(a) left %v invocations, want 0", got)
+	}
+}
+
+func TestIdleInvocationsExpire(t *testing.T) {
+	server := &Server{InvocationIdleTimeout: time.Minute}
+	server.Init()
+	idle, err := server.startInvocationAction("idle")
+	if err != nil {
+		t.Fatalf("startInvocationAction(idle) returned error: %v", err)
+	}
+	server.endInvocationAction(idle)
+	busy, err := server.startInvocationAction("busy")
+	if err != nil {
+		t.Fatalf("startInvocationAction(busy) returned error: %v", err)
+	}
+	defer server.endInvocationAction(busy)
+	server.invocationsMu.Lock()
+	idle.lastUsed = time.Now().Add(-time.Hour)
+	busy.lastUsed = time.Now().Add(-time.Hour)
+	server.invocationsMu.Unlock()
+	// Expiry happens when an invocation is added.
+	added, err := server.startInvocationAction("new")
+	if err != nil {
+		t.Fatalf("startInvocationAction(new) returned error: %v", err)
+	}
+	defer server.endInvocationAction(added)
+	got := make(map[string]bool)
+	for _, inv := range server.allInvocations() {
+		got[inv.id] = true
+	}
+	if diff := cmp.Diff(map[string]bool{"busy": true, "new": true}, got); diff != "" {
+		t.Errorf("Invocations after expiry returned diff (-want +got):\n%s", diff)
+	}
+}
+
 func TestRunCommand_LabelDigestAddedToCommandID(t *testing.T) {
 	env, cleanup := fakes.NewTestEnv(t)
 	fmc := filemetadata.NewSingleFlightCache()
@@ -4836,7 +5468,7 @@ func TestRacingRemoteFailsWhileLocalQueuedLocalWins(t *testing.T) {
 	if !bytes.Equal(contents, wantOutput) {
 		t.Errorf("RunCommand output %s: %q; want %q", path, contents, wantOutput)
 	}
-	if nf := server.numFallbacks.Load(); nf != 1 {
+	if nf := server.invocations[invocationID].numFallbacks.Load(); nf != 1 {
 		t.Errorf("numFallbacks expected to be 1, got %v", nf)
 	}
 
@@ -6383,6 +7015,56 @@ func TestRunCommandError(t *testing.T) {
 			},
 			wantCode: codes.InvalidArgument,
 		},
+		{
+			name: "remote verify with racing",
+			inp:  inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr),
+			req: &ppb.RunRequest{
+				Command: &cpb.Command{
+					Args:     []string{"a", "b", "c"},
+					ExecRoot: execRoot,
+				},
+				Labels: map[string]string{"type": "compile"},
+				ExecutionOptions: &ppb.ProxyExecutionOptions{
+					ExecutionStrategy: ppb.ExecutionStrategy_RACING,
+					RemoteVerify:      true,
+					ReclientTimeout:   3600,
+				},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "invalid execution id",
+			inp:  inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr),
+			req: &ppb.RunRequest{
+				Command: &cpb.Command{
+					Identifiers: &cpb.Identifiers{ExecutionId: "../../.."},
+					Args:        []string{"a", "b", "c"},
+					ExecRoot:    execRoot,
+				},
+				Labels:           map[string]string{"type": "compile"},
+				ExecutionOptions: &ppb.ProxyExecutionOptions{ExecutionStrategy: ppb.ExecutionStrategy_REMOTE, ReclientTimeout: 3600},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "remote verify with compare",
+			inp:  inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr),
+			req: &ppb.RunRequest{
+				Command: &cpb.Command{
+					Args:     []string{"a", "b", "c"},
+					ExecRoot: execRoot,
+				},
+				Labels: map[string]string{"type": "compile"},
+				ExecutionOptions: &ppb.ProxyExecutionOptions{
+					ExecutionStrategy: ppb.ExecutionStrategy_REMOTE,
+					RemoteVerify:      true,
+					CompareWithLocal:  true,
+					NumRemoteReruns:   2,
+					ReclientTimeout:   3600,
+				},
+			},
+			wantCode: codes.InvalidArgument,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -6609,6 +7291,438 @@ func TestRemoteDisabled(t *testing.T) {
 	}
 }
 
+func TestRemoteDisabledActionCache(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test uses bash")
+	}
+	env, cleanup := fakes.NewTestEnv(t)
+	fmc := filemetadata.NewSingleFlightCache()
+	env.Client.FileMetadataCache = fmc
+	t.Cleanup(cleanup)
+	ac, err := actioncache.New(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("actioncache.New() failed: %v", err)
+	}
+	resMgr := localresources.NewDefaultManager()
+	server := &Server{
+		LocalPool:         NewLocalPool(&subprocess.SystemExecutor{}, resMgr),
+		RemoteDisabled:    true,
+		ActionCache:       ac,
+		MaxHoldoff:        time.Minute,
+		DownloadTmp:       t.TempDir(),
+		FileMetadataStore: fmc,
+	}
+	server.Init()
+	server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
+	server.SetREClient(&rexec.Client{FileMetadataCache: fmc}, func() {})
+	lg, err := logger.New(logger.TextFormat, env.ExecRoot, stats.New(), nil, nil, nil)
+	if err != nil {
+		t.Errorf("error initializing logger: %v", err)
+	}
+	server.Logger = lg
+	countPath := filepath.Join(t.TempDir(), "count")
+	req := &ppb.RunRequest{
+		Command: &cpb.Command{
+			Args:     []string{"/bin/bash", "-c", fmt.Sprintf("mkdir -p %s && echo hello > %s && echo run >> %s && echo warning >&2", abPath, abOutPath, countPath)},
+			ExecRoot: env.ExecRoot,
+			Output: &cpb.OutputSpec{
+				OutputFiles: []string{abOutPath},
+			},
+		},
+		Labels: map[string]string{"type": "tool"},
+		ExecutionOptions: &ppb.ProxyExecutionOptions{
+			ExecutionStrategy: ppb.ExecutionStrategy_LOCAL,
+			LocalExecutionOptions: &ppb.LocalExecutionOptions{
+				AcceptCached: true,
+			},
+			ReclientTimeout: 3600,
+		},
+	}
+	ctx := context.Background()
+	path := filepath.Join(env.ExecRoot, abOutPath)
+	for i, wantStatus := range []cpb.CommandResultStatus_Value{cpb.CommandResultStatus_SUCCESS, cpb.CommandResultStatus_CACHE_HIT} {
+		os.Remove(path)
+		got, err := server.RunCommand(ctx, req)
+		if err != nil {
+			t.Fatalf("RunCommand(%v) returned error: %v", i, err)
+		}
+		want := &ppb.RunResponse{Result: &cpb.CommandResult{Status: wantStatus}, Stderr: []byte("warning\n")}
+		if diff := cmp.Diff(want, got, protocmp.IgnoreFields(&ppb.RunResponse{}, "execution_id"), protocmp.Transform()); diff != "" {
+			t.Errorf("RunCommand(%v) returned diff in result: (-want +got)\n%s", i, diff)
+		}
+		if contents, err := os.ReadFile(path); err != nil || string(contents) != "hello\n" {
+			t.Errorf("RunCommand(%v) output %v = %q, %v, want %q", i, path, contents, err, "hello\n")
+		}
+	}
+	if runs, err := os.ReadFile(countPath); err != nil || string(runs) != "run\n" {
+		t.Errorf("Action ran %q times, want once", runs)
+	}
+
+	server.DrainAndReleaseResources()
+	recs, _, err := logger.ParseFromLogDirs(logger.TextFormat, []string{env.ExecRoot})
+	if err != nil {
+		t.Fatalf("logger.ParseFromLogDirs failed: %v", err)
+	}
+	var hits []bool
+	for _, rec := range recs {
+		hits = append(hits, rec.GetLocalMetadata().GetActionCacheHit())
+	}
+	if diff := cmp.Diff([]bool{false, true}, hits); diff != "" {
+		t.Errorf("Server logs returned diff in action cache hits: (-want +got)\n%s", diff)
+	}
+}
+
+func TestLocalCASUnsharesOutputsBeforeLocalExecution(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test uses bash")
+	}
+	env, cleanup := fakes.NewTestEnv(t)
+	fmc := filemetadata.NewSingleFlightCache()
+	env.Client.FileMetadataCache = fmc
+	t.Cleanup(cleanup)
+	cas, err := localcas.New(t.TempDir(), 1<<20, true)
+	if err != nil {
+		t.Fatalf("localcas.New() failed: %v", err)
+	}
+	resMgr := localresources.NewDefaultManager()
+	server := &Server{
+		LocalPool:         NewLocalPool(&subprocess.SystemExecutor{}, resMgr),
+		RemoteDisabled:    true,
+		LocalCAS:          cas,
+		MaxHoldoff:        time.Minute,
+		DownloadTmp:       t.TempDir(),
+		FileMetadataStore: fmc,
+	}
+	server.Init()
+	server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
+	server.SetREClient(&rexec.Client{FileMetadataCache: fmc}, func() {})
+	lg, err := logger.New(logger.TextFormat, env.ExecRoot, stats.New(), nil, nil, nil)
+	if err != nil {
+		t.Errorf("error initializing logger: %v", err)
+	}
+	server.Logger = lg
+	t.Cleanup(server.DrainAndReleaseResources)
+	ctx := context.Background()
+	path := filepath.Join(env.ExecRoot, abOutPath)
+	for _, contents := range []string{"hello", "bye"} {
+		req := &ppb.RunRequest{
+			Command: &cpb.Command{
+				// The output is written in place, as opposed to replaced.
+				Args:     []string{"/bin/bash", "-c", fmt.Sprintf("mkdir -p %s && echo %s > %s", abPath, contents, abOutPath)},
+				ExecRoot: env.ExecRoot,
+				Output: &cpb.OutputSpec{
+					OutputFiles: []string{abOutPath},
+				},
+			},
+			Labels: map[string]string{"type": "tool"},
+			ExecutionOptions: &ppb.ProxyExecutionOptions{
+				ExecutionStrategy: ppb.ExecutionStrategy_LOCAL,
+				ReclientTimeout:   3600,
+			},
+		}
+		got, err := server.RunCommand(ctx, req)
+		if err != nil {
+			t.Fatalf("RunCommand(%v) returned error: %v", contents, err)
+		}
+		if got.GetResult().GetStatus() != cpb.CommandResultStatus_SUCCESS {
+			t.Fatalf("RunCommand(%v) returned result %v, want success", contents, got.GetResult())
+		}
+		if b, err := os.ReadFile(path); err != nil || string(b) != contents+"\n" {
+			t.Errorf("RunCommand(%v) output %v = %q, %v, want %q", contents, path, b, err, contents+"\n")
+		}
+	}
+	// The blob added by the first execution must not have been modified by the second one.
+	dst := filepath.Join(t.TempDir(), "out")
+	if ok, err := cas.Materialize(digest.NewFromBlob([]byte("hello\n")), false, dst, time.Time{}); !ok || err != nil {
+		t.Fatalf("Materialize() = %v, %v, want true, nil", ok, err)
+	}
+	if b, err := os.ReadFile(dst); err != nil || string(b) != "hello\n" {
+		t.Errorf("Materialized blob = %q, %v, want %q", b, err, "hello\n")
+	}
+}
+
+func TestLocalActionFetchesVirtualOutputs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test uses bash")
+	}
+	env, cleanup := fakes.NewTestEnv(t)
+	fmc := filemetadata.NewSingleFlightCache()
+	env.Client.FileMetadataCache = fmc
+	t.Cleanup(cleanup)
+	resMgr := localresources.NewDefaultManager()
+	server := &Server{
+		LocalPool:         NewLocalPool(&subprocess.SystemExecutor{}, resMgr),
+		MaxHoldoff:        time.Minute,
+		DownloadTmp:       t.TempDir(),
+		FileMetadataStore: fmc,
+	}
+	server.Init()
+	server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
+	server.SetREClient(env.Client, func() {})
+	lg, err := logger.New(logger.TextFormat, env.ExecRoot, stats.New(), nil, nil, nil)
+	if err != nil {
+		t.Errorf("error initializing logger: %v", err)
+	}
+	server.Logger = lg
+	t.Cleanup(server.DrainAndReleaseResources)
+	ctx := context.Background()
+	genPath := filepath.Join("out", "gen.h")
+	wantCmd := &command.Command{
+		Identifiers: &command.Identifiers{},
+		Args:        []string{"gen"},
+		ExecRoot:    env.ExecRoot,
+		OutputFiles: []string{genPath},
+	}
+	setPlatformOSFamily(wantCmd)
+	env.Set(wantCmd, command.DefaultExecutionOptions(), &command.Result{Status: command.SuccessResultStatus}, &fakes.OutputFile{Path: genPath, Contents: "generated"})
+	got, err := server.RunCommand(ctx, &ppb.RunRequest{
+		Command: &cpb.Command{
+			Args:     []string{"gen"},
+			ExecRoot: env.ExecRoot,
+			Output:   &cpb.OutputSpec{OutputFiles: []string{genPath}},
+		},
+		Labels: map[string]string{"type": "tool"},
+		ExecutionOptions: &ppb.ProxyExecutionOptions{
+			ExecutionStrategy:      ppb.ExecutionStrategy_REMOTE,
+			RemoteExecutionOptions: &ppb.RemoteExecutionOptions{AcceptCached: true},
+			ReclientTimeout:        3600,
+		},
+	})
+	if err != nil {
+		t.Fatalf("RunCommand(gen) returned error: %v", err)
+	}
+	if got.GetResult().GetStatus() != cpb.CommandResultStatus_SUCCESS {
+		t.Fatalf("RunCommand(gen) returned result %v, want success", got.GetResult())
+	}
+	if _, err := os.Stat(filepath.Join(env.ExecRoot, genPath)); !os.IsNotExist(err) {
+		t.Fatalf("Output %v was downloaded with download_outputs=false: %v", genPath, err)
+	}
+
+	copyPath := filepath.Join("out", "copy.h")
+	got, err = server.RunCommand(ctx, &ppb.RunRequest{
+		Command: &cpb.Command{
+			Args:     []string{"/bin/bash", "-c", fmt.Sprintf("cp %s %s", genPath, copyPath)},
+			ExecRoot: env.ExecRoot,
+			Input:    &cpb.InputSpec{Inputs: []string{genPath}},
+			Output:   &cpb.OutputSpec{OutputFiles: []string{copyPath}},
+		},
+		Labels: map[string]string{"type": "tool"},
+		ExecutionOptions: &ppb.ProxyExecutionOptions{
+			ExecutionStrategy: ppb.ExecutionStrategy_LOCAL,
+			ReclientTimeout:   3600,
+		},
+	})
+	if err != nil {
+		t.Fatalf("RunCommand(cp) returned error: %v", err)
+	}
+	if got.GetResult().GetStatus() != cpb.CommandResultStatus_SUCCESS {
+		t.Fatalf("RunCommand(cp) returned result %v, stderr %s, want success", got.GetResult(), got.GetStderr())
+	}
+	path := filepath.Join(env.ExecRoot, copyPath)
+	if contents, err := os.ReadFile(path); err != nil || string(contents) != "generated" {
+		t.Errorf("Output %v = %q, %v, want %q", path, contents, err, "generated")
+	}
+}
+
+func TestCancelCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test uses bash")
+	}
+	env, cleanup := fakes.NewTestEnv(t)
+	fmc := filemetadata.NewSingleFlightCache()
+	env.Client.FileMetadataCache = fmc
+	t.Cleanup(cleanup)
+	resMgr := localresources.NewDefaultManager()
+	server := &Server{
+		LocalPool:         NewLocalPool(&subprocess.SystemExecutor{}, resMgr),
+		MaxHoldoff:        time.Minute,
+		DownloadTmp:       t.TempDir(),
+		FileMetadataStore: fmc,
+	}
+	server.Init()
+	server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
+	server.SetREClient(env.Client, func() {})
+	lg, err := logger.New(logger.TextFormat, env.ExecRoot, stats.New(), nil, nil, nil)
+	if err != nil {
+		t.Errorf("error initializing logger: %v", err)
+	}
+	server.Logger = lg
+	ctx := context.Background()
+	req := &ppb.RunRequest{
+		Command: &cpb.Command{
+			Identifiers: &cpb.Identifiers{ExecutionId: "exec-1", InvocationId: "inv-1"},
+			Args:        []string{"/bin/bash", "-c", "sleep 60"},
+			ExecRoot:    env.ExecRoot,
+		},
+		Labels: map[string]string{"type": "tool"},
+		ExecutionOptions: &ppb.ProxyExecutionOptions{
+			ExecutionStrategy: ppb.ExecutionStrategy_LOCAL,
+			ReclientTimeout:   3600,
+		},
+	}
+	type runResult struct {
+		resp *ppb.RunResponse
+		err  error
+	}
+	done := make(chan runResult)
+	go func() {
+		resp, err := server.RunCommand(ctx, req)
+		done <- runResult{resp, err}
+	}()
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if _, ok := server.activeActions.Load("exec-1"); ok {
+			break
+		}
+		if time.Since(start) > 10*time.Second {
+			t.Fatalf("Action exec-1 did not start")
+		}
+	}
+
+	// A request with the execution ID of a running action waits for the action to finish.
+	tCtx, cancelWait := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancelWait()
+	if _, err := server.RunCommand(tCtx, req); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("RunCommand() with a running execution ID returned error %v, want code %v", err, codes.DeadlineExceeded)
+	}
+	if _, err := server.CancelCommand(ctx, &ppb.CancelCommandRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CancelCommand() with no IDs returned error %v, want code %v", err, codes.InvalidArgument)
+	}
+	cancelResp, err := server.CancelCommand(ctx, &ppb.CancelCommandRequest{InvocationId: "inv-2"})
+	if err != nil {
+		t.Fatalf("CancelCommand(inv-2) returned error: %v", err)
+	}
+	if len(cancelResp.GetExecutionIds()) != 0 {
+		t.Errorf("CancelCommand(inv-2) cancelled %v, want none", cancelResp.GetExecutionIds())
+	}
+	cancelResp, err = server.CancelCommand(ctx, &ppb.CancelCommandRequest{InvocationId: "inv-1"})
+	if err != nil {
+		t.Fatalf("CancelCommand(inv-1) returned error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"exec-1"}, cancelResp.GetExecutionIds()); diff != "" {
+		t.Errorf("CancelCommand(inv-1) returned diff in cancelled execution IDs: (-want +got)\n%s", diff)
+	}
+
+	var got runResult
+	select {
+	case got = <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatalf("RunCommand() did not return after the command was cancelled")
+	}
+	if got.err != nil {
+		t.Fatalf("RunCommand() returned error: %v", got.err)
+	}
+	wantResult := &cpb.CommandResult{
+		Status:   cpb.CommandResultStatus_INTERRUPTED,
+		ExitCode: command.InterruptedExitCode,
+		Msg:      errCommandCancelled.Error(),
+	}
+	if diff := cmp.Diff(wantResult, got.resp.GetResult(), protocmp.Transform()); diff != "" {
+		t.Errorf("RunCommand() returned diff in result: (-want +got)\n%s", diff)
+	}
+	if got.resp.GetExecutionId() != "exec-1" {
+		t.Errorf("RunCommand() returned execution ID %q, want %q", got.resp.GetExecutionId(), "exec-1")
+	}
+
+	server.DrainAndReleaseResources()
+	recs, _, err := logger.ParseFromLogDirs(logger.TextFormat, []string{env.ExecRoot})
+	if err != nil {
+		t.Fatalf("logger.ParseFromLogDirs failed: %v", err)
+	}
+	if len(recs) != 1 {
+		t.Fatalf("Got %v log records, want 1", len(recs))
+	}
+	if recs[0].GetCompletionStatus() != lpb.CompletionStatus_STATUS_INTERRUPTED {
+		t.Errorf("Log record has completion status %v, want %v", recs[0].GetCompletionStatus(), lpb.CompletionStatus_STATUS_INTERRUPTED)
+	}
+}
+
+func TestRunCommandRetrySameExecutionID(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test uses bash")
+	}
+	env, cleanup := fakes.NewTestEnv(t)
+	fmc := filemetadata.NewSingleFlightCache()
+	env.Client.FileMetadataCache = fmc
+	t.Cleanup(cleanup)
+	resMgr := localresources.NewDefaultManager()
+	server := &Server{
+		LocalPool:         NewLocalPool(&subprocess.SystemExecutor{}, resMgr),
+		MaxHoldoff:        time.Minute,
+		DownloadTmp:       t.TempDir(),
+		FileMetadataStore: fmc,
+	}
+	server.Init()
+	server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
+	server.SetREClient(env.Client, func() {})
+	lg, err := logger.New(logger.TextFormat, env.ExecRoot, stats.New(), nil, nil, nil)
+	if err != nil {
+		t.Errorf("error initializing logger: %v", err)
+	}
+	server.Logger = lg
+	t.Cleanup(server.DrainAndReleaseResources)
+	req := &ppb.RunRequest{
+		Command: &cpb.Command{
+			Identifiers: &cpb.Identifiers{ExecutionId: "exec-1", InvocationId: "inv-1"},
+			Args:        []string{"/bin/bash", "-c", "echo done"},
+			ExecRoot:    env.ExecRoot,
+		},
+		Labels: map[string]string{"type": "tool"},
+		ExecutionOptions: &ppb.ProxyExecutionOptions{
+			ExecutionStrategy: ppb.ExecutionStrategy_LOCAL,
+			ReclientTimeout:   3600,
+		},
+	}
+	// The first attempt is abandoned by its client while it is still running.
+	fCtx, cancelFirst := context.WithCancel(context.Background())
+	defer cancelFirst()
+	fCtx = context.WithValue(fCtx, testOnlyBlockLocalExecKey, func() { <-fCtx.Done() })
+	firstDone := make(chan bool)
+	go func() {
+		server.RunCommand(fCtx, req)
+		close(firstDone)
+	}()
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if _, ok := server.activeActions.Load("exec-1"); ok {
+			break
+		}
+		if time.Since(start) > 10*time.Second {
+			t.Fatalf("Action exec-1 did not start")
+		}
+	}
+
+	type runResult struct {
+		resp *ppb.RunResponse
+		err  error
+	}
+	retryDone := make(chan runResult)
+	go func() {
+		resp, err := server.RunCommand(context.Background(), req)
+		retryDone <- runResult{resp, err}
+	}()
+	select {
+	case got := <-retryDone:
+		t.Fatalf("RunCommand() retry returned %v, %v while the first attempt was running", got.resp, got.err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	cancelFirst()
+	var got runResult
+	select {
+	case got = <-retryDone:
+	case <-time.After(30 * time.Second):
+		t.Fatalf("RunCommand() retry did not return after the first attempt was abandoned")
+	}
+	<-firstDone
+	if got.err != nil {
+		t.Fatalf("RunCommand() retry returned error: %v", got.err)
+	}
+	if got.resp.GetResult().GetStatus() != cpb.CommandResultStatus_SUCCESS || string(got.resp.GetStdout()) != "done\n" {
+		t.Errorf("RunCommand() retry returned result %v and stdout %q, want success and %q", got.resp.GetResult(), got.resp.GetStdout(), "done\n")
+	}
+	if _, ok := server.activeActions.Load("exec-1"); ok {
+		t.Errorf("Action exec-1 is still active after the retry finished")
+	}
+}
+
 func TestProxyInfoUptime(t *testing.T) {
 	env, cleanup := fakes.NewTestEnv(t)
 	fmc := filemetadata.NewSingleFlightCache()
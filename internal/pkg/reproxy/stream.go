@@ -0,0 +1,146 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reproxy
+
+import (
+	"sync"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/outerr"
+
+	ppb "github.com/bazelbuild/reclient/api/proxy"
+)
+
+// maxStreamChunkSize is the maximum size of the stdout and stderr in a single streamed response,
+// which keeps responses under the gRPC message size limit.
+const maxStreamChunkSize = 1024 * 1024
+
+// outputStream sends the stdout and stderr of an action to a RunCommandStream client as they are
+// produced, followed by the result.
+type outputStream struct {
+	send func(*ppb.RunResponse) error
+
+	mu          sync.Mutex
+	executionID string
+	// sentOut and sentErr are the number of bytes of stdout and stderr sent so far.
+	sentOut int
+	sentErr int
+	// err is the first error sending a response. Output is no longer streamed after an error.
+	err error
+}
+
+func newOutputStream(send func(*ppb.RunResponse) error) *outputStream {
+	return &outputStream{send: send}
+}
+
+func (s *outputStream) setExecutionID(id string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.executionID = id
+}
+
+// wrap returns an OutErr that records to oe and streams everything recorded in oe. Output
+// written to oe other than through the returned OutErr is only sent with the next write or the
+// result.
+func (s *outputStream) wrap(oe outerr.OutErr) outerr.OutErr {
+	rec, ok := oe.(*outerr.RecordingOutErr)
+	if s == nil || !ok {
+		return oe
+	}
+	return &streamingOutErr{rec: rec, s: s}
+}
+
+// sendLocked sends stdout and stderr in chunks of at most maxStreamChunkSize, followed by the
+// result of res if it is not nil. Must be called with mu held.
+func (s *outputStream) sendLocked(stdout, stderr []byte, res *ppb.RunResponse) error {
+	for s.err == nil && (len(stdout) > 0 || len(stderr) > 0) {
+		chunk := &ppb.RunResponse{ExecutionId: s.executionID}
+		chunk.Stdout, stdout = split(stdout, maxStreamChunkSize)
+		chunk.Stderr, stderr = split(stderr, maxStreamChunkSize-len(chunk.Stdout))
+		if s.err = s.send(chunk); s.err == nil {
+			s.sentOut += len(chunk.Stdout)
+			s.sentErr += len(chunk.Stderr)
+		}
+	}
+	if s.err == nil && res != nil {
+		s.err = s.send(&ppb.RunResponse{
+			ExecutionId:        s.executionID,
+			Result:             res.Result,
+			ActionLog:          res.ActionLog,
+			RemoteFallbackInfo: res.RemoteFallbackInfo,
+		})
+	}
+	return s.err
+}
+
+// flushLocked sends the recorded stdout and stderr that were not sent yet. Must be called with mu
+// held.
+func (s *outputStream) flushLocked(stdout, stderr []byte) {
+	if s.sentOut > len(stdout) || s.sentErr > len(stderr) {
+		return
+	}
+	s.sendLocked(stdout[s.sentOut:], stderr[s.sentErr:], nil)
+}
+
+// finish sends the part of the stdout and stderr of the final response that was not sent yet,
+// followed by the result. Output is only streamed during non-racing local execution, whose output
+// is used as is, so the streamed output is a prefix of the final output.
+func (s *outputStream) finish(resp *ppb.RunResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.executionID = resp.GetExecutionId()
+	stdout, stderr := resp.GetStdout(), resp.GetStderr()
+	if s.sentOut <= len(stdout) {
+		stdout = stdout[s.sentOut:]
+	}
+	if s.sentErr <= len(stderr) {
+		stderr = stderr[s.sentErr:]
+	}
+	return s.sendLocked(stdout, stderr, resp)
+}
+
+func split(b []byte, n int) ([]byte, []byte) {
+	if n < 0 {
+		n = 0
+	}
+	if len(b) <= n {
+		return b, nil
+	}
+	return b[:n], b[n:]
+}
+
+// streamingOutErr is an OutErr recording to a RecordingOutErr that streams the recorded output.
+type streamingOutErr struct {
+	rec *outerr.RecordingOutErr
+	s   *outputStream
+}
+
+// WriteOut writes to the stdout.
+func (o *streamingOutErr) WriteOut(buf []byte) {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+	o.rec.WriteOut(buf)
+	o.s.flushLocked(o.rec.Stdout(), o.rec.Stderr())
+}
+
+// WriteErr writes to the stderr.
+func (o *streamingOutErr) WriteErr(buf []byte) {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+	o.rec.WriteErr(buf)
+	o.s.flushLocked(o.rec.Stdout(), o.rec.Stderr())
+}
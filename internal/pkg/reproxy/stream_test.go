@@ -0,0 +1,283 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reproxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bazelbuild/reclient/internal/pkg/localresources"
+	"github.com/bazelbuild/reclient/internal/pkg/logger"
+	"github.com/bazelbuild/reclient/internal/pkg/stats"
+	"github.com/bazelbuild/reclient/internal/pkg/subprocess"
+	"github.com/bazelbuild/reclient/pkg/inputprocessor"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/fakes"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/filemetadata"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/outerr"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/rexec"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	ppb "github.com/bazelbuild/reclient/api/proxy"
+	cpb "github.com/bazelbuild/remote-apis-sdks/go/api/command"
+)
+
+type runCommandStreamStub struct {
+	grpc.ServerStream
+	ctx    context.Context
+	onSend func(*ppb.RunResponse)
+	resps  []*ppb.RunResponse
+}
+
+func (s *runCommandStreamStub) Context() context.Context {
+	return s.ctx
+}
+
+func (s *runCommandStreamStub) Send(resp *ppb.RunResponse) error {
+	s.resps = append(s.resps, resp)
+	if s.onSend != nil {
+		s.onSend(resp)
+	}
+	return nil
+}
+
+func TestRunCommandStream(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test uses bash")
+	}
+	env, cleanup := fakes.NewTestEnv(t)
+	fmc := filemetadata.NewSingleFlightCache()
+	env.Client.FileMetadataCache = fmc
+	t.Cleanup(cleanup)
+	resMgr := localresources.NewDefaultManager()
+	server := &Server{
+		LocalPool:         NewLocalPool(&subprocess.SystemExecutor{}, resMgr),
+		RemoteDisabled:    true,
+		MaxHoldoff:        time.Minute,
+		DownloadTmp:       t.TempDir(),
+		FileMetadataStore: fmc,
+	}
+	server.Init()
+	server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
+	server.SetREClient(&rexec.Client{FileMetadataCache: fmc}, func() {})
+	lg, err := logger.New(logger.TextFormat, env.ExecRoot, stats.New(), nil, nil, nil)
+	if err != nil {
+		t.Errorf("error initializing logger: %v", err)
+	}
+	server.Logger = lg
+	t.Cleanup(server.DrainAndReleaseResources)
+
+	// The command only completes once its first output was streamed.
+	marker := filepath.Join(t.TempDir(), "streamed")
+	script := "echo started; for i in $(seq 500); do [ -f " + marker + " ] && break; sleep 0.01; done; [ -f " + marker + " ] || exit 1; echo warning >&2; echo done"
+	srv := &runCommandStreamStub{ctx: context.Background()}
+	srv.onSend = func(resp *ppb.RunResponse) {
+		if bytes.Contains(resp.GetStdout(), []byte("started")) {
+			os.WriteFile(marker, nil, 0644)
+		}
+	}
+	req := &ppb.RunRequest{
+		Command: &cpb.Command{
+			Args:     []string{"/bin/bash", "-c", script},
+			ExecRoot: env.ExecRoot,
+		},
+		Labels: map[string]string{"type": "tool"},
+		ExecutionOptions: &ppb.ProxyExecutionOptions{
+			ExecutionStrategy: ppb.ExecutionStrategy_LOCAL,
+			ReclientTimeout:   3600,
+		},
+	}
+	if err := server.RunCommandStream(req, srv); err != nil {
+		t.Fatalf("RunCommandStream() returned error: %v", err)
+	}
+	if len(srv.resps) < 2 {
+		t.Fatalf("RunCommandStream() sent %v responses, want at least 2", len(srv.resps))
+	}
+	var stdout, stderr []byte
+	for i, resp := range srv.resps {
+		if resp.GetExecutionId() == "" {
+			t.Errorf("Response %v has no execution ID", i)
+		}
+		if i < len(srv.resps)-1 && resp.GetResult() != nil {
+			t.Errorf("Response %v before the last one has a result: %v", i, resp.GetResult())
+		}
+		stdout = append(stdout, resp.GetStdout()...)
+		stderr = append(stderr, resp.GetStderr()...)
+	}
+	last := srv.resps[len(srv.resps)-1]
+	if diff := cmp.Diff(&cpb.CommandResult{Status: cpb.CommandResultStatus_SUCCESS}, last.GetResult(), protocmp.Transform()); diff != "" {
+		t.Errorf("RunCommandStream() returned diff in result: (-want +got)\n%s", diff)
+	}
+	if string(stdout) != "started\ndone\n" || string(stderr) != "warning\n" {
+		t.Errorf("RunCommandStream() streamed stdout %q and stderr %q, want %q and %q", stdout, stderr, "started\ndone\n", "warning\n")
+	}
+}
+
+// racingLocalExecutor writes its output, and then blocks until released before failing as if
+// canceled, so that remote wins a race after local has already produced output.
+type racingLocalExecutor struct {
+	written chan bool
+	release chan bool
+}
+
+func (e *racingLocalExecutor) ExecuteWithOutErr(ctx context.Context, cmd *command.Command, oe outerr.OutErr) error {
+	oe.WriteOut([]byte("local output\n"))
+	close(e.written)
+	<-e.release
+	return context.Canceled
+}
+
+func TestRunCommandStreamRacingRemoteWins(t *testing.T) {
+	env, cleanup := fakes.NewTestEnv(t)
+	fmc := filemetadata.NewSingleFlightCache()
+	env.Client.FileMetadataCache = fmc
+	t.Cleanup(cleanup)
+	resMgr := localresources.NewDefaultManager()
+	exec := &racingLocalExecutor{written: make(chan bool), release: make(chan bool)}
+	server := &Server{
+		LocalPool:         NewLocalPool(exec, resMgr),
+		FileMetadataStore: fmc,
+		Forecast:          &Forecast{},
+		MaxHoldoff:        time.Minute,
+		DownloadTmp:       t.TempDir(),
+	}
+	server.Init()
+	server.SetInputProcessor(inputprocessor.NewInputProcessorWithStubDependencyScanner(&stubCPPDependencyScanner{}, false, nil, resMgr), func() {})
+	server.SetREClient(env.Client, func() {})
+	lg, err := logger.New(logger.TextFormat, env.ExecRoot, stats.New(), nil, nil, nil)
+	if err != nil {
+		t.Errorf("error initializing logger: %v", err)
+	}
+	server.Logger = lg
+	t.Cleanup(server.DrainAndReleaseResources)
+
+	cmdArgs := []string{"tool", "arg"}
+	req := &ppb.RunRequest{
+		Command: &cpb.Command{
+			Args:     cmdArgs,
+			ExecRoot: env.ExecRoot,
+		},
+		Labels:           map[string]string{"type": "tool"},
+		ExecutionOptions: &ppb.ProxyExecutionOptions{ExecutionStrategy: ppb.ExecutionStrategy_RACING, ReclientTimeout: 3600},
+	}
+	cmd := &command.Command{
+		Identifiers: &command.Identifiers{},
+		Args:        cmdArgs,
+		ExecRoot:    env.ExecRoot,
+		InputSpec:   &command.InputSpec{},
+	}
+	setPlatformOSFamily(cmd)
+	env.Set(cmd, command.DefaultExecutionOptions(), &command.Result{Status: command.SuccessResultStatus}, fakes.StdOut("remote output\n"))
+	// Remote only finishes once local has written its output, and local only finishes, canceled,
+	// once remote has won.
+	ctx := context.WithValue(context.Background(), testOnlyBlockRemoteExecKey, func() { <-exec.written })
+	ctx = context.WithValue(ctx, testOnlyBlockFallbackKey, func() { close(exec.release) })
+	srv := &runCommandStreamStub{ctx: ctx}
+	if err := server.RunCommandStream(req, srv); err != nil {
+		t.Fatalf("RunCommandStream() returned error: %v", err)
+	}
+	var stdout []byte
+	for _, resp := range srv.resps {
+		stdout = append(stdout, resp.GetStdout()...)
+	}
+	last := srv.resps[len(srv.resps)-1]
+	if diff := cmp.Diff(&cpb.CommandResult{Status: cpb.CommandResultStatus_SUCCESS}, last.GetResult(), protocmp.Transform()); diff != "" {
+		t.Errorf("RunCommandStream() returned diff in result: (-want +got)\n%s", diff)
+	}
+	if string(stdout) != "remote output\n" {
+		t.Errorf("RunCommandStream() streamed stdout %q, want %q", stdout, "remote output\n")
+	}
+}
+
+func TestOutputStreamChunks(t *testing.T) {
+	var resps []*ppb.RunResponse
+	s := newOutputStream(func(resp *ppb.RunResponse) error {
+		resps = append(resps, resp)
+		return nil
+	})
+	s.setExecutionID("id")
+	oe := outerr.NewRecordingOutErr()
+	// Output recorded before the stream wraps the recording is sent with the next write.
+	oe.WriteOut([]byte("a"))
+	soe := s.wrap(oe)
+	soe.WriteErr([]byte("b"))
+	big := strings.Repeat("x", maxStreamChunkSize+1)
+	if err := s.finish(&ppb.RunResponse{
+		ExecutionId: "id",
+		Stdout:      []byte("a" + big),
+		Stderr:      []byte("b"),
+		Result:      &cpb.CommandResult{Status: cpb.CommandResultStatus_SUCCESS},
+	}); err != nil {
+		t.Fatalf("finish() returned error: %v", err)
+	}
+	type chunk struct {
+		ID        string
+		StdoutLen int
+		Stderr    string
+		HasResult bool
+	}
+	var got []chunk
+	for _, resp := range resps {
+		got = append(got, chunk{resp.GetExecutionId(), len(resp.GetStdout()), string(resp.GetStderr()), resp.GetResult() != nil})
+	}
+	want := []chunk{
+		{ID: "id", StdoutLen: 1, Stderr: "b"},
+		{ID: "id", StdoutLen: maxStreamChunkSize},
+		{ID: "id", StdoutLen: 1},
+		{ID: "id", HasResult: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("outputStream sent diff in responses: (-want +got)\n%s", diff)
+	}
+	if got := string(oe.Stdout()) + string(oe.Stderr()); got != "ab" {
+		t.Errorf("Recorded output = %q, want %q", got, "ab")
+	}
+}
+
+func TestOutputStreamSendError(t *testing.T) {
+	sendErr := errors.New("send failed")
+	n := 0
+	s := newOutputStream(func(*ppb.RunResponse) error {
+		n++
+		return sendErr
+	})
+	soe := s.wrap(outerr.NewRecordingOutErr())
+	soe.WriteOut([]byte("a"))
+	soe.WriteOut([]byte("b"))
+	if err := s.finish(&ppb.RunResponse{Stdout: []byte("ab"), Result: &cpb.CommandResult{}}); !errors.Is(err, sendErr) {
+		t.Errorf("finish() = %v, want %v", err, sendErr)
+	}
+	if n != 1 {
+		t.Errorf("outputStream attempted %v sends, want 1", n)
+	}
+}
+
+func TestNilOutputStream(t *testing.T) {
+	var s *outputStream
+	s.setExecutionID("id")
+	oe := outerr.NewRecordingOutErr()
+	if got := s.wrap(oe); got != oe {
+		t.Errorf("wrap() on nil stream = %v, want %v", got, oe)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reproxy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/client"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/digest"
+
+	log "github.com/golang/glog"
+)
+
+// virtualOutputs tracks the outputs of remote actions that were not downloaded, so that later
+// actions reading them get them by digest remotely and fetch them before executing locally.
+type virtualOutputs struct {
+	mu sync.Mutex
+	// outs are the virtual outputs keyed by absolute path.
+	outs map[string]*virtualOutput
+}
+
+type virtualOutput struct {
+	dg           digest.Digest
+	isExecutable bool
+	// recorded is when the output became virtual. An output modified after that was written
+	// outside of reproxy and is no longer virtual.
+	recorded time.Time
+}
+
+func newVirtualOutputs() *virtualOutputs {
+	return &virtualOutputs{outs: make(map[string]*virtualOutput)}
+}
+
+// update records the outputs of the completed action a that were not downloaded as virtual, and
+// forgets all its other outputs, which are now either on disk or stale.
+func (v *virtualOutputs) update(a *action) {
+	if v == nil || a.cmd == nil {
+		return
+	}
+	wd := filepath.Join(a.cmd.ExecRoot, a.cmd.WorkingDir)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, f := range a.cmd.OutputFiles {
+		delete(v.outs, filepath.Join(wd, f))
+	}
+	for _, d := range a.cmd.OutputDirs {
+		prefix := filepath.Join(wd, d) + string(filepath.Separator)
+		for path := range v.outs {
+			if strings.HasPrefix(path, prefix) {
+				delete(v.outs, path)
+			}
+		}
+	}
+	if a.res == nil || !a.res.IsOk() || a.rec.GetLocalMetadata().GetExecutedLocally() {
+		return
+	}
+	now := time.Now()
+	for _, out := range a.skippedOutputs {
+		if out.IsEmptyDirectory || out.SymlinkTarget != "" {
+			continue
+		}
+		v.outs[filepath.Join(wd, out.Path)] = &virtualOutput{dg: out.Digest, isExecutable: out.IsExecutable, recorded: now}
+	}
+	if len(a.skippedOutputs) > 0 {
+		log.V(2).Infof("%v: Tracking %v outputs that were not downloaded as virtual outputs", a.cmd.Identifiers.ExecutionID, len(a.skippedOutputs))
+	}
+}
+
+// lookupLocked returns the virtual output at the absolute path, if any. Must be called with mu
+// held.
+func (v *virtualOutputs) lookupLocked(path string) (*virtualOutput, bool) {
+	vo, ok := v.outs[path]
+	if !ok {
+		return nil, false
+	}
+	if fi, err := os.Stat(path); err == nil && fi.ModTime().After(vo.recorded) {
+		delete(v.outs, path)
+		return nil, false
+	}
+	return vo, true
+}
+
+// resolveInputs replaces the inputs of a that are virtual outputs, or virtual outputs under input
+// directories of a, by virtual inputs referring to their digests.
+func (v *virtualOutputs) resolveInputs(a *action) {
+	if v == nil || a.cmd == nil || a.cmd.InputSpec == nil {
+		return
+	}
+	execRoot := a.cmd.ExecRoot
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.outs) == 0 {
+		return
+	}
+	existing := make(map[string]bool)
+	for _, vi := range a.cmd.InputSpec.VirtualInputs {
+		existing[filepath.Clean(vi.Path)] = true
+	}
+	add := func(path string, vo *virtualOutput) {
+		rel, err := filepath.Rel(execRoot, path)
+		if err != nil || existing[rel] {
+			return
+		}
+		existing[rel] = true
+		a.cmd.InputSpec.VirtualInputs = append(a.cmd.InputSpec.VirtualInputs, &command.VirtualInput{
+			Path:         rel,
+			Digest:       vo.dg.String(),
+			IsExecutable: vo.isExecutable,
+		})
+	}
+	var inputs []string
+	n := len(a.cmd.InputSpec.VirtualInputs)
+	for _, in := range a.cmd.InputSpec.Inputs {
+		path := in
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(execRoot, in)
+		}
+		if vo, ok := v.lookupLocked(path); ok {
+			add(path, vo)
+			continue
+		}
+		inputs = append(inputs, in)
+		if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+			continue
+		}
+		prefix := path + string(filepath.Separator)
+		for p := range v.outs {
+			if !strings.HasPrefix(p, prefix) {
+				continue
+			}
+			if vo, ok := v.lookupLocked(p); ok {
+				add(p, vo)
+			}
+		}
+	}
+	a.cmd.InputSpec.Inputs = inputs
+	if added := len(a.cmd.InputSpec.VirtualInputs) - n; added > 0 {
+		log.V(2).Infof("%v: Using %v virtual outputs of previous actions as inputs", a.cmd.Identifiers.ExecutionID, added)
+	}
+}
+
+// skippedOutputs returns the outputs in outs that are not in downloaded.
+func skippedOutputs(outs, downloaded map[string]*client.TreeOutput) map[string]*client.TreeOutput {
+	if len(outs) == len(downloaded) {
+		return nil
+	}
+	res := make(map[string]*client.TreeOutput)
+	for path, out := range outs {
+		if _, ok := downloaded[path]; !ok {
+			res[path] = out
+		}
+	}
+	return res
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reproxy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	lpb "github.com/bazelbuild/reclient/api/log"
+	"github.com/bazelbuild/reclient/internal/pkg/execroot"
+	"github.com/bazelbuild/reclient/internal/pkg/logger"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/client"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/digest"
+	"github.com/google/go-cmp/cmp"
+)
+
+func remoteActionWithSkippedOutputs(er string, skipped ...*client.TreeOutput) *action {
+	a := &action{
+		cmd: &command.Command{
+			Identifiers: &command.Identifiers{},
+			ExecRoot:    er,
+			WorkingDir:  "out",
+			OutputFiles: []string{"gen.h", "gen.cc"},
+			OutputDirs:  []string{"gen"},
+		},
+		res:            &command.Result{Status: command.SuccessResultStatus},
+		rec:            logger.NewLogRecord(),
+		skippedOutputs: map[string]*client.TreeOutput{},
+	}
+	for _, out := range skipped {
+		a.skippedOutputs[out.Path] = out
+	}
+	return a
+}
+
+func TestVirtualOutputsResolveInputs(t *testing.T) {
+	er, cleanup := execroot.Setup(t, []string{"foo.cc"})
+	defer cleanup()
+	if err := os.MkdirAll(filepath.Join(er, "out", "gen"), 0755); err != nil {
+		t.Fatalf("MkdirAll() failed: %v", err)
+	}
+	genH := digest.NewFromBlob([]byte("gen.h"))
+	proto := digest.NewFromBlob([]byte("gen/a.pb.h"))
+	v := newVirtualOutputs()
+	v.update(remoteActionWithSkippedOutputs(er,
+		&client.TreeOutput{Path: "gen.h", Digest: genH},
+		&client.TreeOutput{Path: "gen/a.pb.h", Digest: proto, IsExecutable: true},
+		&client.TreeOutput{Path: "gen/empty", IsEmptyDirectory: true},
+	))
+
+	a := &action{
+		cmd: &command.Command{
+			Identifiers: &command.Identifiers{},
+			ExecRoot:    er,
+			InputSpec: &command.InputSpec{
+				Inputs: []string{"foo.cc", "out/gen.h", "out/gen"},
+			},
+		},
+	}
+	v.resolveInputs(a)
+	want := &command.InputSpec{
+		Inputs: []string{"foo.cc", "out/gen"},
+		VirtualInputs: []*command.VirtualInput{
+			{Path: "out/gen.h", Digest: genH.String()},
+			{Path: "out/gen/a.pb.h", Digest: proto.String(), IsExecutable: true},
+		},
+	}
+	if diff := cmp.Diff(want, a.cmd.InputSpec); diff != "" {
+		t.Errorf("resolveInputs() returned diff in input spec: (-want +got)\n%s", diff)
+	}
+}
+
+func TestVirtualOutputsForgotten(t *testing.T) {
+	genH := &client.TreeOutput{Path: "gen.h", Digest: digest.NewFromBlob([]byte("gen.h"))}
+	tests := []struct {
+		name   string
+		update func(t *testing.T, er string, v *virtualOutputs)
+	}{
+		{
+			name: "local execution",
+			update: func(t *testing.T, er string, v *virtualOutputs) {
+				a := remoteActionWithSkippedOutputs(er, genH)
+				a.rec.LocalMetadata = &lpb.LocalMetadata{ExecutedLocally: true}
+				v.update(a)
+			},
+		},
+		{
+			name: "downloaded",
+			update: func(t *testing.T, er string, v *virtualOutputs) {
+				v.update(remoteActionWithSkippedOutputs(er))
+			},
+		},
+		{
+			name: "failed",
+			update: func(t *testing.T, er string, v *virtualOutputs) {
+				a := remoteActionWithSkippedOutputs(er, genH)
+				a.res = &command.Result{Status: command.NonZeroExitResultStatus, ExitCode: 1}
+				v.update(a)
+			},
+		},
+		{
+			name: "written outside reproxy",
+			update: func(t *testing.T, er string, v *virtualOutputs) {
+				path := filepath.Join(er, "out", "gen.h")
+				if err := os.WriteFile(path, []byte("local"), 0644); err != nil {
+					t.Fatalf("WriteFile(%v) failed: %v", path, err)
+				}
+				later := time.Now().Add(time.Minute)
+				if err := os.Chtimes(path, later, later); err != nil {
+					t.Fatalf("Chtimes(%v) failed: %v", path, err)
+				}
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			er, cleanup := execroot.Setup(t, nil)
+			defer cleanup()
+			if err := os.MkdirAll(filepath.Join(er, "out"), 0755); err != nil {
+				t.Fatalf("MkdirAll() failed: %v", err)
+			}
+			v := newVirtualOutputs()
+			v.update(remoteActionWithSkippedOutputs(er, genH))
+			tc.update(t, er, v)
+			a := &action{
+				cmd: &command.Command{
+					ExecRoot:  er,
+					InputSpec: &command.InputSpec{Inputs: []string{"out/gen.h"}},
+				},
+			}
+			v.resolveInputs(a)
+			want := &command.InputSpec{Inputs: []string{"out/gen.h"}}
+			if diff := cmp.Diff(want, a.cmd.InputSpec); diff != "" {
+				t.Errorf("resolveInputs() returned diff in input spec: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -41,19 +41,34 @@ var (
 // File represents a file that stores the pid of a running reproxy process.
 type File struct {
 	Pid int
-	fp  string
+	// Version is the version of a daemon reproxy process, empty otherwise.
+	Version string
+	// FlagsDigest is the digest of the flags a daemon reproxy process was started with, empty
+	// otherwise.
+	FlagsDigest string
+	fp          string
 }
 
 // WriteFile writes the pid file for the reproxy process running at serverAddr.
 func WriteFile(serverAddr string, pid int) error {
+	return writeFile(serverAddr, strconv.Itoa(pid))
+}
+
+// WriteDaemonFile writes the pid file for the daemon reproxy process running at serverAddr, which
+// later builds attach to if its version and flags digest match theirs.
+func WriteDaemonFile(serverAddr string, pid int, version, flagsDigest string) error {
+	return writeFile(serverAddr, strings.Join([]string{strconv.Itoa(pid), version, flagsDigest}, "\n"))
+}
+
+func writeFile(serverAddr, contents string) error {
 	fp, err := pathForServerAddr(serverAddr)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(fp, []byte(strconv.Itoa(pid)), 0644); err != nil {
+	if err := os.WriteFile(fp, []byte(contents), 0644); err != nil {
 		return fmt.Errorf("failed to persist the pid file %v: %w", fp, err)
 	}
-	log.Infof("Wrote PID file %v with contents: %v", fp, pid)
+	log.Infof("Wrote PID file %v with contents: %q", fp, contents)
 	return nil
 }
 
@@ -64,14 +79,20 @@ func ReadFile(serverAddr string) (*File, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read pid file: %w", err)
 	}
-	pid, err := strconv.Atoi(string(contents))
+	lines := strings.Split(string(contents), "\n")
+	pid, err := strconv.Atoi(lines[0])
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse pid from contents of %v (%v): %w", fp, string(contents), err)
 	}
-	return &File{
+	f := &File{
 		Pid: pid,
 		fp:  fp,
-	}, nil
+	}
+	if len(lines) == 3 {
+		f.Version = lines[1]
+		f.FlagsDigest = lines[2]
+	}
+	return f, nil
 }
 
 // Delete this pid file.
@@ -70,6 +70,35 @@ func TestFile(t *testing.T) {
 	}
 }
 
+func TestDaemonFile(t *testing.T) {
+	td := t.TempDir()
+	tmpDir = td
+	t.Cleanup(func() {
+		tmpDir = os.TempDir()
+	})
+	serverAddr := fmt.Sprintf("unix://%s/somesocket.sock", td)
+	if err := WriteDaemonFile(serverAddr, 1234, "0.1.2.abc", "digest"); err != nil {
+		t.Fatalf("WriteDaemonFile() returned unexpected error: %v", err)
+	}
+	pf, err := ReadFile(serverAddr)
+	if err != nil {
+		t.Fatalf("ReadFile(%v) returned unexpected error: %v", serverAddr, err)
+	}
+	if pf.Pid != 1234 || pf.Version != "0.1.2.abc" || pf.FlagsDigest != "digest" {
+		t.Errorf("ReadFile(%v) returned pid=%v, version=%q, flags digest=%q, want pid=1234, version=%q, flags digest=%q", serverAddr, pf.Pid, pf.Version, pf.FlagsDigest, "0.1.2.abc", "digest")
+	}
+	if err := WriteFile(serverAddr, 5678); err != nil {
+		t.Fatalf("WriteFile() returned unexpected error: %v", err)
+	}
+	pf, err = ReadFile(serverAddr)
+	if err != nil {
+		t.Fatalf("ReadFile(%v) returned unexpected error: %v", serverAddr, err)
+	}
+	if pf.Pid != 5678 || pf.Version != "" || pf.FlagsDigest != "" {
+		t.Errorf("ReadFile(%v) of a non-daemon pid file returned pid=%v, version=%q, flags digest=%q, want pid=5678 and no version or flags digest", serverAddr, pf.Pid, pf.Version, pf.FlagsDigest)
+	}
+}
+
 func TestPollForDeath(t *testing.T) {
 	td := t.TempDir()
 	tmpDir = td
@@ -19,6 +19,7 @@ package rewrapper
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -67,9 +68,21 @@ type Proxy interface {
 	RunCommand(context.Context, *ppb.RunRequest, ...grpc.CallOption) (*ppb.RunResponse, error)
 }
 
+// StreamProxy is the interface of the RE Proxy API including streaming of command output.
+type StreamProxy interface {
+	Proxy
+	RunCommandStream(context.Context, *ppb.RunRequest, ...grpc.CallOption) (ppb.Commands_RunCommandStreamClient, error)
+}
+
+// Canceler is the interface of the RE Proxy API for cancelling in-flight commands.
+type Canceler interface {
+	CancelCommand(context.Context, *ppb.CancelCommandRequest, ...grpc.CallOption) (*ppb.CancelCommandResponse, error)
+}
+
 // CommandOptions contains command execution options passed to the rewrapper.
 type CommandOptions struct {
 	CommandID                    string
+	ExecutionID                  string
 	InvocationID                 string
 	ToolName                     string
 	Labels                       map[string]string
@@ -99,11 +112,15 @@ type CommandOptions struct {
 	NumLocalReruns               int
 	NumRemoteReruns              int
 	LocalWrapper                 string
+	LocalPlatform                string
+	LocalPriority                int
 	RemoteWrapper                string
 	PreserveSymlink              bool
 	CanonicalizeWorkingDir       bool
 	ActionLog                    string
 	PreserveUnchangedOutputMtime bool
+	TraceLocalInputs             bool
+	RemoteVerify                 bool
 }
 
 // RunCommand runs a command through the RE proxy.
@@ -124,6 +141,62 @@ func RunCommand(ctx context.Context, dialTimeout time.Duration, proxy Proxy, cmd
 	return resp, err
 }
 
+// RunCommandStream runs a command through the RE proxy, writing its stdout and stderr to stdout
+// and stderr as they are produced. The returned response contains the result of the command but
+// not its output. The command is only retried until its first output is received. Falls back to
+// RunCommand if the RE proxy does not support streaming.
+func RunCommandStream(ctx context.Context, dialTimeout time.Duration, proxy StreamProxy, cmd []string, opts *CommandOptions, stdout, stderr io.Writer) (*ppb.RunResponse, error) {
+	req, err := createRequest(cmd, opts)
+	if err != nil {
+		return nil, err
+	}
+	var resp *ppb.RunResponse
+	received := false
+	st := time.Now()
+	err = retry.WithPolicy(ctx, func(err error) bool { return !received && shouldRetry(err) }, backoff, func() error {
+		if time.Since(st) > dialTimeout {
+			return fmt.Errorf("dial_timeout of %v expired before being able to connect to reproxy", dialTimeout)
+		}
+		stream, err := proxy.RunCommandStream(ctx, req)
+		if err != nil {
+			return err
+		}
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				return fmt.Errorf("reproxy ended the output stream without a result")
+			}
+			if err != nil {
+				return err
+			}
+			received = true
+			stdout.Write(msg.GetStdout())
+			stderr.Write(msg.GetStderr())
+			if msg.GetResult() != nil {
+				msg.Stdout, msg.Stderr = nil, nil
+				resp = msg
+				return nil
+			}
+		}
+	})
+	if status.Code(err) != codes.Unimplemented {
+		return resp, err
+	}
+	if resp, err = RunCommand(ctx, dialTimeout, proxy, cmd, opts); err != nil {
+		return nil, err
+	}
+	stdout.Write(resp.GetStdout())
+	stderr.Write(resp.GetStderr())
+	resp.Stdout, resp.Stderr = nil, nil
+	return resp, nil
+}
+
+// CancelCommand cancels the command with the given execution ID in the RE proxy.
+func CancelCommand(ctx context.Context, proxy Canceler, executionID string) error {
+	_, err := proxy.CancelCommand(ctx, &ppb.CancelCommandRequest{ExecutionId: executionID})
+	return err
+}
+
 func createRequest(cmd []string, opts *CommandOptions) (*ppb.RunRequest, error) {
 	inputs := opts.Inputs
 	for _, p := range opts.InputListPaths {
@@ -149,6 +222,7 @@ func createRequest(cmd []string, opts *CommandOptions) (*ppb.RunRequest, error)
 		Identifiers: &cpb.Identifiers{
 			CommandId:    opts.CommandID,
 			InvocationId: opts.InvocationID,
+			ExecutionId:  opts.ExecutionID,
 			ToolName:     opts.ToolName,
 		},
 		ExecRoot: opts.ExecRoot,
@@ -170,6 +244,10 @@ func createRequest(cmd []string, opts *CommandOptions) (*ppb.RunRequest, error)
 	if res, ok := ppb.ExecutionStrategy_Value_value[strings.ToUpper(opts.ExecStrategy)]; ok {
 		strategy = ppb.ExecutionStrategy_Value(res)
 	}
+	platform := ppb.LocalExecutionOptions_UNSPECIFIED
+	if res, ok := ppb.LocalExecutionOptions_LocalExecutionPlatform_value[strings.ToUpper(opts.LocalPlatform)]; ok {
+		platform = ppb.LocalExecutionOptions_LocalExecutionPlatform(res)
+	}
 	md := &ppb.Metadata{EventTimes: map[string]*cpb.TimeInterval{
 		WrapperOverheadKey: &cpb.TimeInterval{From: command.TimeToProto(opts.StartTime)},
 	}}
@@ -189,6 +267,8 @@ func createRequest(cmd []string, opts *CommandOptions) (*ppb.RunRequest, error)
 			ReclientTimeout:        int32(opts.ReclientTimeout.Seconds()),
 			EnableAtomicDownloads:  opts.EnableAtomicDownloads,
 			DownloadRegex:          opts.DownloadRegex,
+			TraceLocalInputs:       opts.TraceLocalInputs,
+			RemoteVerify:           opts.RemoteVerify,
 			RemoteExecutionOptions: &ppb.RemoteExecutionOptions{
 				AcceptCached:                 opts.RemoteAcceptCache,
 				DoNotCache:                   !opts.RemoteUpdateCache,
@@ -201,6 +281,8 @@ func createRequest(cmd []string, opts *CommandOptions) (*ppb.RunRequest, error)
 				AcceptCached: opts.RemoteAcceptCache,
 				DoNotCache:   !opts.RemoteUpdateCache,
 				Wrapper:      opts.LocalWrapper,
+				Platform:     platform,
+				Priority:     int32(opts.LocalPriority),
 			},
 			LogEnvironment:   opts.LogEnvironment,
 			IncludeActionLog: opts.ActionLog != "",
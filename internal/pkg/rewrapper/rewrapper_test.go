@@ -15,7 +15,9 @@
 package rewrapper
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"os"
 	"strings"
 	"testing"
@@ -36,13 +38,145 @@ import (
 )
 
 type proxyStub struct {
-	req *ppb.RunRequest
-	err error
+	req  *ppb.RunRequest
+	resp *ppb.RunResponse
+	err  error
 }
 
 func (s *proxyStub) RunCommand(_ context.Context, req *ppb.RunRequest, _ ...grpc.CallOption) (*ppb.RunResponse, error) {
 	s.req = req
-	return nil, s.err
+	return s.resp, s.err
+}
+
+type cancelStub struct {
+	req *ppb.CancelCommandRequest
+}
+
+func (s *cancelStub) CancelCommand(_ context.Context, req *ppb.CancelCommandRequest, _ ...grpc.CallOption) (*ppb.CancelCommandResponse, error) {
+	s.req = req
+	return &ppb.CancelCommandResponse{ExecutionIds: []string{req.GetExecutionId()}}, nil
+}
+
+type streamProxyStub struct {
+	proxyStub
+	// attempts are the responses and final error of each call to RunCommandStream.
+	attempts []streamAttempt
+	calls    int
+}
+
+type streamAttempt struct {
+	resps []*ppb.RunResponse
+	err   error
+}
+
+func (s *streamProxyStub) RunCommandStream(_ context.Context, req *ppb.RunRequest, _ ...grpc.CallOption) (ppb.Commands_RunCommandStreamClient, error) {
+	s.req = req
+	a := s.attempts[s.calls]
+	s.calls++
+	return &streamClientStub{attempt: a}, nil
+}
+
+type streamClientStub struct {
+	grpc.ClientStream
+	attempt streamAttempt
+}
+
+func (c *streamClientStub) Recv() (*ppb.RunResponse, error) {
+	if len(c.attempt.resps) == 0 {
+		if c.attempt.err != nil {
+			return nil, c.attempt.err
+		}
+		return nil, io.EOF
+	}
+	resp := c.attempt.resps[0]
+	c.attempt.resps = c.attempt.resps[1:]
+	return resp, nil
+}
+
+func TestRunCommandStream(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "error")
+	result := &cpb.CommandResult{Status: cpb.CommandResultStatus_SUCCESS}
+	tests := []struct {
+		name       string
+		p          *streamProxyStub
+		wantStdout string
+		wantStderr string
+		wantCalls  int
+		wantErr    bool
+	}{
+		{
+			name: "streamed",
+			p: &streamProxyStub{attempts: []streamAttempt{{resps: []*ppb.RunResponse{
+				{ExecutionId: "1", Stdout: []byte("a")},
+				{ExecutionId: "1", Stderr: []byte("b")},
+				{ExecutionId: "1", Stdout: []byte("c"), Result: result},
+			}}}},
+			wantStdout: "ac",
+			wantStderr: "b",
+			wantCalls:  1,
+		},
+		{
+			name: "retried before output",
+			p: &streamProxyStub{attempts: []streamAttempt{
+				{err: unavailable},
+				{resps: []*ppb.RunResponse{{ExecutionId: "1", Stdout: []byte("a"), Result: result}}},
+			}},
+			wantStdout: "a",
+			wantCalls:  2,
+		},
+		{
+			name: "not retried after output",
+			p: &streamProxyStub{attempts: []streamAttempt{
+				{resps: []*ppb.RunResponse{{ExecutionId: "1", Stdout: []byte("a")}}, err: unavailable},
+				{resps: []*ppb.RunResponse{{ExecutionId: "1", Stdout: []byte("a"), Result: result}}},
+			}},
+			wantStdout: "a",
+			wantCalls:  1,
+			wantErr:    true,
+		},
+		{
+			name:      "no result",
+			p:         &streamProxyStub{attempts: []streamAttempt{{}}},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			resp, err := RunCommandStream(context.Background(), time.Hour, tc.p, []string{"echo"}, &CommandOptions{}, &stdout, &stderr)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("RunCommandStream() returned error %v, want error: %v", err, tc.wantErr)
+			}
+			if tc.p.calls != tc.wantCalls {
+				t.Errorf("RunCommandStream() called the proxy %v times, want %v", tc.p.calls, tc.wantCalls)
+			}
+			if stdout.String() != tc.wantStdout || stderr.String() != tc.wantStderr {
+				t.Errorf("RunCommandStream() wrote stdout %q and stderr %q, want %q and %q", stdout.String(), stderr.String(), tc.wantStdout, tc.wantStderr)
+			}
+			if err != nil {
+				return
+			}
+			want := &ppb.RunResponse{ExecutionId: "1", Result: result}
+			if diff := cmp.Diff(want, resp, protocmp.Transform()); diff != "" {
+				t.Errorf("RunCommandStream() returned diff in response: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunCommandStreamUnimplemented(t *testing.T) {
+	p := &streamProxyStub{attempts: []streamAttempt{{err: status.Error(codes.Unimplemented, "unknown method")}}}
+	p.resp = &ppb.RunResponse{ExecutionId: "1", Stdout: []byte("out"), Result: &cpb.CommandResult{}}
+	var stdout, stderr bytes.Buffer
+	resp, err := RunCommandStream(context.Background(), time.Hour, p, []string{"echo"}, &CommandOptions{}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("RunCommandStream() returned error: %v", err)
+	}
+	if stdout.String() != "out" || len(resp.GetStdout()) != 0 {
+		t.Errorf("RunCommandStream() wrote stdout %q and returned stdout %q, want %q and none", stdout.String(), resp.GetStdout(), "out")
+	}
 }
 
 func TestRunCommand(t *testing.T) {
@@ -69,6 +203,7 @@ func TestRunCommand(t *testing.T) {
 	st := time.Now()
 	opts := &CommandOptions{
 		CommandID:              "1234",
+		ExecutionID:            "exec001",
 		InvocationID:           "inv001",
 		ToolName:               "ninja",
 		Labels:                 map[string]string{"type": "compile", "lang": "cpp"},
@@ -94,6 +229,7 @@ func TestRunCommand(t *testing.T) {
 			Identifiers: &cpb.Identifiers{
 				CommandId:    "1234",
 				InvocationId: "inv001",
+				ExecutionId:  "exec001",
 				ToolName:     "ninja",
 			},
 			ExecRoot: "/home/user/code",
@@ -146,6 +282,40 @@ func TestRunCommandTimeout(t *testing.T) {
 	}
 }
 
+func TestCancelCommand(t *testing.T) {
+	c := &cancelStub{}
+	if err := CancelCommand(context.Background(), c, "exec001"); err != nil {
+		t.Fatalf("CancelCommand() returned error: %v", err)
+	}
+	want := &ppb.CancelCommandRequest{ExecutionId: "exec001"}
+	if diff := cmp.Diff(want, c.req, protocmp.Transform()); diff != "" {
+		t.Errorf("CancelCommand() sent bad CancelCommandRequest. (-want +got): %s", diff)
+	}
+}
+
+func TestRunCommandLocalPlatform(t *testing.T) {
+	tests := []struct {
+		platform string
+		want     ppb.LocalExecutionOptions_LocalExecutionPlatform
+	}{
+		{platform: "", want: ppb.LocalExecutionOptions_UNSPECIFIED},
+		{platform: "docker", want: ppb.LocalExecutionOptions_DOCKER},
+		{platform: "sandbox", want: ppb.LocalExecutionOptions_SANDBOX},
+	}
+	for _, test := range tests {
+		t.Run(test.platform, func(t *testing.T) {
+			p := &proxyStub{}
+			opts := &CommandOptions{ExecStrategy: "local", LocalPlatform: test.platform}
+			if _, err := RunCommand(context.Background(), time.Hour, p, []string{"echo"}, opts); err != nil {
+				t.Fatalf("RunCommand() returned error: %v", err)
+			}
+			if got := p.req.GetExecutionOptions().GetLocalExecutionOptions().GetPlatform(); got != test.want {
+				t.Errorf("RunCommand() sent local platform %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
 func TestParseVirtualInputs(t *testing.T) {
 	cmd := []string{"cat", "foo.txt"}
 	st := time.Now()
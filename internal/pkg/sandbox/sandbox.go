@@ -0,0 +1,399 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sandbox runs commands locally in a private exec root that only contains the declared
+// inputs of the command, similar to how they would run on a remote execution worker.
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bazelbuild/reclient/internal/pkg/filetrace"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/outerr"
+
+	log "github.com/golang/glog"
+)
+
+// DefaultBaseDir is the directory, relative to the exec root, under which sandboxes are created
+// if no base directory is configured.
+const DefaultBaseDir = ".reproxy_sandbox"
+
+// Runner runs commands on the host.
+type Runner interface {
+	// ExecuteWithOutErr runs the given command inside the working directory.
+	ExecuteWithOutErr(ctx context.Context, cmd *command.Command, oe outerr.OutErr) error
+}
+
+// Executor runs commands in a sandbox exec root containing only the declared inputs of the
+// command, and copies the declared outputs back into the real exec root once the command
+// finishes.
+type Executor struct {
+	// Runner runs the command inside the sandbox.
+	Runner Runner
+	// BaseDir is the directory under which sandboxes are created. It should be on the same
+	// device as the exec root so that inputs can be hardlinked. Defaults to DefaultBaseDir
+	// inside the exec root.
+	BaseDir string
+	// DisableTrace disables tracing of undeclared input accesses.
+	DisableTrace bool
+}
+
+// ExecuteWithOutErr runs the given command in a sandbox and returns stdout and stderr in an
+// OutErr object.
+func (e *Executor) ExecuteWithOutErr(ctx context.Context, cmd *command.Command, oe outerr.OutErr) error {
+	_, err := e.ExecuteInSandbox(ctx, cmd, oe)
+	return err
+}
+
+// ExecuteInSandbox runs the given command in a sandbox and returns the paths, relative to the
+// exec root, of the files that exist in the exec root but that the command failed to open
+// because they were not declared as inputs.
+// Returns *exec.ExitError if the command ran with a non-zero exit code.
+func (e *Executor) ExecuteInSandbox(ctx context.Context, cmd *command.Command, oe outerr.OutErr) ([]string, error) {
+	if cmd.ExecRoot == "" {
+		return nil, fmt.Errorf("no exec root set, cannot execute in sandbox")
+	}
+	base := e.BaseDir
+	if base == "" {
+		base = filepath.Join(cmd.ExecRoot, DefaultBaseDir)
+	}
+	if err := os.MkdirAll(base, os.ModePerm); err != nil {
+		return nil, err
+	}
+	root, err := os.MkdirTemp(base, "sandbox-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(root); err != nil {
+			log.Warningf("%v: Failed to remove sandbox %v: %v", executionID(cmd), root, err)
+		}
+	}()
+	if err := stage(cmd, root, base); err != nil {
+		return nil, fmt.Errorf("failed to stage sandbox: %w", err)
+	}
+	scmd := &command.Command{
+		Args:        cmd.Args,
+		ExecRoot:    root,
+		WorkingDir:  cmd.WorkingDir,
+		InputSpec:   cmd.InputSpec,
+		OutputFiles: cmd.OutputFiles,
+		OutputDirs:  cmd.OutputDirs,
+		Identifiers: cmd.Identifiers,
+		Platform:    cmd.Platform,
+	}
+	var t *filetrace.Tracer
+	if !e.DisableTrace {
+		t = filetrace.New()
+	}
+	if t != nil {
+		scmd.Args = t.Wrap(cmd.Args)
+		defer t.Close()
+	}
+	err = e.Runner.ExecuteWithOutErr(ctx, scmd, oe)
+	var undeclared []string
+	if t != nil {
+		if acc, terr := t.Accesses(); terr != nil {
+			log.Warningf("%v: Failed to read sandbox trace: %v", executionID(cmd), terr)
+		} else {
+			undeclared = undeclaredPaths(filetrace.Missing(acc), root, cmd.ExecRoot, cmd.WorkingDir)
+		}
+	}
+	if err != nil {
+		return undeclared, err
+	}
+	if err := collectOutputs(cmd, root); err != nil {
+		return undeclared, fmt.Errorf("failed to collect outputs from sandbox: %w", err)
+	}
+	return undeclared, nil
+}
+
+// stage populates the sandbox root with the declared inputs of cmd. Anything under skip, which
+// holds the sandboxes themselves, is never staged.
+func stage(cmd *command.Command, root, skip string) error {
+	if err := os.MkdirAll(filepath.Join(root, cmd.WorkingDir), os.ModePerm); err != nil {
+		return err
+	}
+	for _, o := range cmd.OutputFiles {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, cmd.WorkingDir, o)), os.ModePerm); err != nil {
+			return err
+		}
+	}
+	for _, o := range cmd.OutputDirs {
+		if err := os.MkdirAll(filepath.Join(root, cmd.WorkingDir, o), os.ModePerm); err != nil {
+			return err
+		}
+	}
+	if cmd.InputSpec == nil {
+		return nil
+	}
+	excl, err := exclusions(cmd.InputSpec.InputExclusions)
+	if err != nil {
+		return err
+	}
+	for _, in := range cmd.InputSpec.Inputs {
+		if filepath.IsAbs(in) {
+			// Absolute inputs are not relocatable and are used from their original location.
+			continue
+		}
+		src := filepath.Join(cmd.ExecRoot, in)
+		err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if path == skip {
+				return filepath.SkipDir
+			}
+			if excluded(excl, path, d.IsDir()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			rel, err := filepath.Rel(cmd.ExecRoot, path)
+			if err != nil {
+				return err
+			}
+			return stageFile(path, filepath.Join(root, rel), d)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, vi := range cmd.InputSpec.VirtualInputs {
+		if err := stageVirtual(cmd.ExecRoot, root, vi); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stageFile links a single file, directory or symlink from the exec root into the sandbox.
+func stageFile(src, dst string, d fs.DirEntry) error {
+	if d.IsDir() {
+		return os.MkdirAll(dst, os.ModePerm)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		// Already staged as part of another input.
+		return nil
+	}
+	if d.Type()&fs.ModeSymlink != 0 {
+		// Symlinks are recreated as-is so that they only resolve if their target is staged too.
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+	}
+	return linkOrCopy(src, dst)
+}
+
+// linkOrCopy hardlinks the file at src to dst, or copies it if hardlinking fails, e.g. across
+// devices. A symlink to src is never created, since the command could follow it out of the
+// sandbox.
+func linkOrCopy(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+	// Hardlinks share the modification time of the input, which copies keep as well.
+	st, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(dst, st.ModTime(), st.ModTime())
+}
+
+// stageVirtual creates a virtual input in the sandbox.
+func stageVirtual(execRoot, root string, vi *command.VirtualInput) error {
+	dst := filepath.Join(root, vi.Path)
+	if vi.IsEmptyDirectory {
+		return os.MkdirAll(dst, os.ModePerm)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	if vi.Contents == nil && vi.Digest != "" {
+		// Virtual inputs given by digest are expected to be present in the exec root.
+		src := filepath.Join(execRoot, vi.Path)
+		if _, err := os.Lstat(src); err != nil {
+			return nil
+		}
+		os.Remove(dst)
+		return linkOrCopy(src, dst)
+	}
+	mode := os.FileMode(0644)
+	if vi.IsExecutable {
+		mode = 0755
+	}
+	os.Remove(dst)
+	return os.WriteFile(dst, vi.Contents, mode)
+}
+
+// collectOutputs moves the declared outputs of cmd from the sandbox into the exec root.
+func collectOutputs(cmd *command.Command, root string) error {
+	for _, o := range append(append([]string{}, cmd.OutputFiles...), cmd.OutputDirs...) {
+		src := filepath.Join(root, cmd.WorkingDir, o)
+		dst := filepath.Join(cmd.ExecRoot, cmd.WorkingDir, o)
+		if _, err := os.Lstat(src); err != nil {
+			if os.IsNotExist(err) {
+				// Missing outputs are reported by the caller the same way as for plain local runs.
+				continue
+			}
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(dst); err != nil {
+			return err
+		}
+		if err := os.Rename(src, dst); err == nil {
+			continue
+		}
+		if err := copyTree(src, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyTree copies the file or directory at src to dst.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, os.ModePerm)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target)
+		}
+	})
+}
+
+func copyFile(src, dst string) error {
+	st, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, st.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+type exclusion struct {
+	re  *regexp.Regexp
+	typ command.InputType
+}
+
+func exclusions(excl []*command.InputExclusion) ([]exclusion, error) {
+	var res []exclusion
+	for _, e := range excl {
+		re, err := regexp.Compile(e.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input exclusion %q: %w", e.Regex, err)
+		}
+		res = append(res, exclusion{re: re, typ: e.Type})
+	}
+	return res, nil
+}
+
+func excluded(excl []exclusion, path string, isDir bool) bool {
+	for _, e := range excl {
+		if e.typ == command.DirectoryInputType && !isDir || e.typ == command.FileInputType && isDir {
+			continue
+		}
+		if e.re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// undeclaredPaths returns the sorted, deduplicated exec root relative paths of the given
+// sandbox paths which exist in the exec root but not in the sandbox.
+func undeclaredPaths(paths []string, root, execRoot, workingDir string) []string {
+	seen := make(map[string]bool)
+	var res []string
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(root, workingDir, p)
+		}
+		rel, err := filepath.Rel(root, filepath.Clean(p))
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if seen[rel] {
+			continue
+		}
+		seen[rel] = true
+		if _, err := os.Lstat(filepath.Join(root, rel)); err == nil {
+			continue
+		}
+		if _, err := os.Lstat(filepath.Join(execRoot, rel)); err != nil {
+			continue
+		}
+		res = append(res, rel)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func executionID(cmd *command.Command) string {
+	if cmd.Identifiers == nil {
+		return ""
+	}
+	return cmd.Identifiers.ExecutionID
+}
@@ -0,0 +1,205 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/outerr"
+	"github.com/google/go-cmp/cmp"
+)
+
+// funcRunner runs a go function in place of the command.
+type funcRunner struct {
+	cmds []*command.Command
+	f    func(cmd *command.Command) error
+}
+
+func (r *funcRunner) ExecuteWithOutErr(ctx context.Context, cmd *command.Command, oe outerr.OutErr) error {
+	r.cmds = append(r.cmds, cmd)
+	return r.f(cmd)
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for p, c := range files {
+		abs := filepath.Join(root, p)
+		if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
+			t.Fatalf("MkdirAll(%v) failed: %v", filepath.Dir(abs), err)
+		}
+		if err := os.WriteFile(abs, []byte(c), 0644); err != nil {
+			t.Fatalf("WriteFile(%v) failed: %v", abs, err)
+		}
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func TestExecuteInSandbox(t *testing.T) {
+	execRoot := t.TempDir()
+	writeFiles(t, execRoot, map[string]string{
+		"foo.c":           "foo",
+		"include/foo.h":   "foo.h",
+		"include/bar.h":   "bar.h",
+		"undeclared.h":    "undeclared",
+		"out/stale/x.txt": "stale",
+	})
+	cmd := &command.Command{
+		Args:        []string{"cc"},
+		ExecRoot:    execRoot,
+		WorkingDir:  "out",
+		Identifiers: &command.Identifiers{ExecutionID: "abc"},
+		InputSpec: &command.InputSpec{
+			Inputs: []string{"foo.c", "include", "missing.h"},
+			VirtualInputs: []*command.VirtualInput{
+				{Path: "gen/version.h", Contents: []byte("v1")},
+				{Path: "out/empty", IsEmptyDirectory: true},
+			},
+			InputExclusions: []*command.InputExclusion{{Regex: `bar\.h$`, Type: command.FileInputType}},
+		},
+		OutputFiles: []string{"obj/foo.o"},
+		OutputDirs:  []string{"stale"},
+	}
+	var seen map[string]bool
+	r := &funcRunner{f: func(c *command.Command) error {
+		seen = map[string]bool{}
+		for _, p := range []string{"foo.c", "include/foo.h", "include/bar.h", "undeclared.h", "gen/version.h", "out/empty", "out/stale/x.txt"} {
+			seen[p] = exists(filepath.Join(c.ExecRoot, p))
+		}
+		if err := os.WriteFile(filepath.Join(c.ExecRoot, c.WorkingDir, "obj/foo.o"), []byte("obj"), 0644); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(c.ExecRoot, c.WorkingDir, "stale/y.txt"), []byte("new"), 0644)
+	}}
+	e := &Executor{Runner: r, DisableTrace: true}
+	if _, err := e.ExecuteInSandbox(context.Background(), cmd, outerr.NewRecordingOutErr()); err != nil {
+		t.Fatalf("ExecuteInSandbox() returned error: %v", err)
+	}
+	if len(r.cmds) != 1 {
+		t.Fatalf("ExecuteInSandbox() ran %v commands, want 1", len(r.cmds))
+	}
+	if r.cmds[0].ExecRoot == execRoot || r.cmds[0].WorkingDir != "out" {
+		t.Errorf("ExecuteInSandbox() ran command in %v/%v, want sandbox exec root", r.cmds[0].ExecRoot, r.cmds[0].WorkingDir)
+	}
+	wantSeen := map[string]bool{
+		"foo.c":           true,
+		"include/foo.h":   true,
+		"include/bar.h":   false,
+		"undeclared.h":    false,
+		"gen/version.h":   true,
+		"out/empty":       true,
+		"out/stale/x.txt": false,
+	}
+	if diff := cmp.Diff(wantSeen, seen); diff != "" {
+		t.Errorf("ExecuteInSandbox() staged wrong inputs, diff (-want +got): %v", diff)
+	}
+	for p, want := range map[string]string{"out/obj/foo.o": "obj", "out/stale/y.txt": "new"} {
+		got, err := os.ReadFile(filepath.Join(execRoot, p))
+		if err != nil || string(got) != want {
+			t.Errorf("ReadFile(%v) = %q, %v, want %q", p, got, err, want)
+		}
+	}
+	if exists(filepath.Join(execRoot, "out/stale/x.txt")) {
+		t.Errorf("ExecuteInSandbox() kept stale file in output directory")
+	}
+	if exists(r.cmds[0].ExecRoot) {
+		t.Errorf("ExecuteInSandbox() did not remove sandbox %v", r.cmds[0].ExecRoot)
+	}
+}
+
+func TestExecuteInSandboxFailure(t *testing.T) {
+	execRoot := t.TempDir()
+	wantErr := errors.New("failed")
+	r := &funcRunner{f: func(c *command.Command) error {
+		if err := os.WriteFile(filepath.Join(c.ExecRoot, "foo.o"), []byte("obj"), 0644); err != nil {
+			return err
+		}
+		return wantErr
+	}}
+	cmd := &command.Command{Args: []string{"cc"}, ExecRoot: execRoot, OutputFiles: []string{"foo.o"}}
+	e := &Executor{Runner: r, BaseDir: t.TempDir(), DisableTrace: true}
+	if _, err := e.ExecuteInSandbox(context.Background(), cmd, outerr.NewRecordingOutErr()); !errors.Is(err, wantErr) {
+		t.Errorf("ExecuteInSandbox() returned error %v, want %v", err, wantErr)
+	}
+	if exists(filepath.Join(execRoot, "foo.o")) {
+		t.Errorf("ExecuteInSandbox() collected outputs of failed command")
+	}
+}
+
+func TestUndeclaredPaths(t *testing.T) {
+	execRoot := t.TempDir()
+	root := t.TempDir()
+	writeFiles(t, execRoot, map[string]string{
+		"a.h":       "a",
+		"inc/b.h":   "b",
+		"staged.h":  "staged",
+		"out/gen.h": "gen",
+	})
+	writeFiles(t, root, map[string]string{"staged.h": "staged"})
+	paths := []string{
+		filepath.Join(root, "inc/b.h"),
+		filepath.Join(root, "a.h"),
+		"../a.h",
+		"gen.h",
+		filepath.Join(root, "staged.h"),
+		filepath.Join(root, "nonexistent.h"),
+		"/usr/include/stdio.h",
+	}
+	got := undeclaredPaths(paths, root, execRoot, "out")
+	want := []string{"a.h", "inc/b.h", "out/gen.h"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("undeclaredPaths() returned wrong paths, diff (-want +got): %v", diff)
+	}
+}
+
+func TestLinkOrCopyFallsBackToCopy(t *testing.T) {
+	execRoot := t.TempDir()
+	root := t.TempDir()
+	writeFiles(t, execRoot, map[string]string{"a.h": "a"})
+	// Hardlinking fails since the destination exists.
+	writeFiles(t, root, map[string]string{"a.h": "stale"})
+	src, dst := filepath.Join(execRoot, "a.h"), filepath.Join(root, "a.h")
+	if err := linkOrCopy(src, dst); err != nil {
+		t.Fatalf("linkOrCopy(%v, %v) returned error: %v", src, dst, err)
+	}
+	fi, err := os.Lstat(dst)
+	if err != nil {
+		t.Fatalf("Lstat(%v) failed: %v", dst, err)
+	}
+	if !fi.Mode().IsRegular() {
+		t.Errorf("linkOrCopy(%v, %v) created %v, want a regular file", src, dst, fi.Mode())
+	}
+	if b, err := os.ReadFile(dst); err != nil || string(b) != "a" {
+		t.Errorf("linkOrCopy(%v, %v) created file with contents %q, %v, want %q", src, dst, b, err, "a")
+	}
+	sfi, err := os.Stat(src)
+	if err != nil {
+		t.Fatalf("Stat(%v) failed: %v", src, err)
+	}
+	if os.SameFile(sfi, fi) {
+		t.Errorf("linkOrCopy(%v, %v) hardlinked over an existing file, want a copy", src, dst)
+	}
+	if !fi.ModTime().Equal(sfi.ModTime()) {
+		t.Errorf("linkOrCopy(%v, %v) created file modified at %v, want %v", src, dst, fi.ModTime(), sfi.ModTime())
+	}
+}
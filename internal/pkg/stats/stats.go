@@ -203,8 +203,14 @@ func (stt *statTree) addLocalMetadata(lm *lpb.LocalMetadata, name string, cmdID
 	lmStt.addBool(lm.ExecutedLocally, "ExecutedLocally", cmdID)
 	lmStt.addBool(lm.ValidCacheHit, "ValidCacheHit", cmdID)
 	lmStt.addBool(lm.UpdatedCache, "UpdatedCache", cmdID)
+	lmStt.addBool(lm.ActionCacheHit, "ActionCacheHit", cmdID)
 	lmStt.addVerification(lm.Verification, "Verification", cmdID)
 	lmStt.addEventTimes(lm.EventTimes, "EventTimes", cmdID)
+	// Time queued for local execution is also aggregated per priority class, since higher
+	// priority actions are granted local resources first.
+	if qt, ok := lm.EventTimes[event.LocalCommandQueued]; ok {
+		lmStt.child("LocalPriority").child(fmt.Sprint(lm.LocalPriority)).addTimeInterval(qt, event.LocalCommandQueued, cmdID)
+	}
 	lmStt.addRerunMetadatas(lm.RerunMetadata, "RerunMetadata", cmdID)
 }
 
@@ -243,6 +249,7 @@ func (stt *statTree) addVerification(vf *lpb.Verification, name string, cmdID st
 	vfStt.addNum(int64(vf.TotalMismatches), "TotalMismatches", cmdID, false)
 	vfStt.addNum(int64(vf.TotalIgnoredMismatches), "TotalIgnoredMismatches", cmdID, false)
 	vfStt.addNum(vf.TotalVerified, "TotalVerified", cmdID, false)
+	vfStt.addNum(int64(len(vf.UndeclaredInputs)), "TotalUndeclaredInputs", cmdID, false)
 }
 
 func (stt *statTree) addMismatches(mismatches []*lpb.Verification_Mismatch, name string, cmdID string) {
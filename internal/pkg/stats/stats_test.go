@@ -34,6 +34,7 @@ import (
 	lpb "github.com/bazelbuild/reclient/api/log"
 	stpb "github.com/bazelbuild/reclient/api/stat"
 	spb "github.com/bazelbuild/reclient/api/stats"
+	"github.com/bazelbuild/reclient/internal/pkg/event"
 
 	cpb "github.com/bazelbuild/remote-apis-sdks/go/api/command"
 )
@@ -568,6 +569,48 @@ func TestLabelsAggregation(t *testing.T) {
 	}
 }
 
+func TestVerificationUndeclaredInputsByLabel(t *testing.T) {
+	compile := map[string]string{"type": "compile", "lang": "cpp"}
+	link := map[string]string{"type": "link"}
+	recs := []*lpb.LogRecord{
+		{
+			LocalMetadata: &lpb.LocalMetadata{
+				Labels:       compile,
+				Verification: &lpb.Verification{UndeclaredInputs: []string{"a.h", "b.h"}},
+			},
+		},
+		{
+			LocalMetadata: &lpb.LocalMetadata{
+				Labels:       compile,
+				Verification: &lpb.Verification{UndeclaredInputs: []string{"c.h"}},
+			},
+		},
+		{
+			LocalMetadata: &lpb.LocalMetadata{
+				Labels:       link,
+				Verification: &lpb.Verification{},
+			},
+		},
+	}
+	s := NewFromRecords(recs, nil)
+	for name, want := range map[string]int64{
+		"LocalMetadata.Verification.TotalUndeclaredInputs":                         3,
+		"[lang=cpp,type=compile].LocalMetadata.Verification.TotalUndeclaredInputs": 3,
+	} {
+		st, ok := s.Stats[name]
+		if !ok {
+			t.Errorf("Stats[%q] missing", name)
+			continue
+		}
+		if st.Count != want {
+			t.Errorf("Stats[%q].Count = %v, want %v", name, st.Count, want)
+		}
+	}
+	if st, ok := s.Stats["[type=link].LocalMetadata.Verification.TotalUndeclaredInputs"]; ok && !st.IsEmpty() {
+		t.Errorf("Stats for link actions without undeclared inputs = %+v, want empty", st)
+	}
+}
+
 func TestVerification(t *testing.T) {
 	recs := []*lpb.LogRecord{
 		&lpb.LogRecord{
@@ -839,6 +882,58 @@ func TestTimeStatsDoNotAggregatePartial(t *testing.T) {
 	}
 }
 
+func TestLocalCommandQueuedByPriority(t *testing.T) {
+	from, _ := time.Parse(time.RFC3339, "2006-01-02T15:04:05Z")
+	queued := func(id string, priority int32, d time.Duration) *lpb.LogRecord {
+		return &lpb.LogRecord{
+			Command: &cpb.Command{Identifiers: &cpb.Identifiers{CommandId: id}},
+			LocalMetadata: &lpb.LocalMetadata{
+				LocalPriority: priority,
+				EventTimes: map[string]*cpb.TimeInterval{
+					event.LocalCommandQueued: &cpb.TimeInterval{
+						From: command.TimeToProto(from),
+						To:   command.TimeToProto(from.Add(d)),
+					},
+				},
+			},
+		}
+	}
+	recs := []*lpb.LogRecord{
+		queued("a", 1, 10*time.Millisecond),
+		queued("b", 1, 30*time.Millisecond),
+		queued("c", 5, 2*time.Millisecond),
+		{Command: &cpb.Command{Identifiers: &cpb.Identifiers{CommandId: "d"}}, LocalMetadata: &lpb.LocalMetadata{LocalPriority: 5}},
+	}
+	s := NewFromRecords(recs, nil)
+
+	got := make(map[string]*stpb.Stat)
+	for _, st := range s.ToProto().Stats {
+		got[st.Name] = st
+	}
+	tests := []struct {
+		name        string
+		wantCount   int64
+		wantAverage float64
+		wantOutlier string
+	}{
+		{name: "LocalMetadata.LocalPriority.1.LocalCommandQueuedMillis", wantCount: 2, wantAverage: 20, wantOutlier: "b"},
+		{name: "LocalMetadata.LocalPriority.5.LocalCommandQueuedMillis", wantCount: 1, wantAverage: 2, wantOutlier: "c"},
+	}
+	for _, tc := range tests {
+		st, ok := got[tc.name]
+		if !ok {
+			t.Errorf("Stat %v not found", tc.name)
+			continue
+		}
+		if st.Count != tc.wantCount || st.Average != tc.wantAverage {
+			t.Errorf("Stat %v has count %v and average %v, want %v and %v", tc.name, st.Count, st.Average, tc.wantCount, tc.wantAverage)
+		}
+		if len(st.Outliers) == 0 || st.Outliers[0].CommandId != tc.wantOutlier {
+			t.Errorf("Stat %v has outliers %v, want %v first", tc.name, st.Outliers, tc.wantOutlier)
+		}
+	}
+}
+
 func TestBandwidthStats(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -0,0 +1,148 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subprocess
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/outerr"
+
+	log "github.com/golang/glog"
+)
+
+const (
+	// ContainerImageKey is the platform property holding the container image of an action.
+	ContainerImageKey = "container-image"
+	// DockerNetworkKey is the platform property controlling network access of the container.
+	DockerNetworkKey = "dockerNetwork"
+	// DockerRunAsRootKey is the platform property controlling whether the container runs as root.
+	DockerRunAsRootKey = "dockerRunAsRoot"
+
+	dockerImagePrefix   = "docker://"
+	defaultDockerBinary = "docker"
+)
+
+// ContainerRuntime runs container runtime command lines, such as `docker run ...`, on the host.
+type ContainerRuntime interface {
+	// ExecuteWithOutErr runs the given command inside the working directory.
+	ExecuteWithOutErr(ctx context.Context, cmd *command.Command, oe outerr.OutErr) error
+}
+
+// DockerExecutor executes commands inside the container image given by the container-image
+// platform property of the command. The exec root is bind-mounted into the container at the
+// same path so that all paths in the command resolve the same way as on the host.
+type DockerExecutor struct {
+	// Runtime runs the container runtime binary. Defaults to SystemExecutor.
+	Runtime ContainerRuntime
+	// Binary is the container runtime binary. Defaults to "docker".
+	Binary string
+}
+
+// ExecuteWithOutErr runs the given command in its container image and returns stdout and stderr
+// in an OutErr object.
+// Returns *exec.ExitError if the command ran with a non-zero exit code.
+func (d *DockerExecutor) ExecuteWithOutErr(ctx context.Context, cmd *command.Command, oe outerr.OutErr) error {
+	dcmd, err := d.runCommand(cmd)
+	if err != nil {
+		return err
+	}
+	err = d.runtime().ExecuteWithOutErr(ctx, dcmd, oe)
+	if err != nil && ctx.Err() != nil && cmd.Identifiers != nil && cmd.Identifiers.ExecutionID != "" {
+		// The runtime client was killed, but the container may still be running.
+		rm := &command.Command{
+			Args:       []string{d.binary(), "rm", "-f", containerName(cmd)},
+			ExecRoot:   cmd.ExecRoot,
+			WorkingDir: cmd.WorkingDir,
+		}
+		if rmErr := d.runtime().ExecuteWithOutErr(context.Background(), rm, outerr.NewRecordingOutErr()); rmErr != nil {
+			log.Warningf("%v: Failed to remove container %v: %v", cmd.Identifiers.ExecutionID, containerName(cmd), rmErr)
+		}
+	}
+	return err
+}
+
+// runCommand returns the container runtime command line that runs cmd in its container image.
+func (d *DockerExecutor) runCommand(cmd *command.Command) (*command.Command, error) {
+	if len(cmd.Args) < 1 {
+		return nil, fmt.Errorf("command must have more than 1 argument")
+	}
+	image := strings.TrimPrefix(cmd.Platform[ContainerImageKey], dockerImagePrefix)
+	if image == "" {
+		return nil, fmt.Errorf("no %q platform property set, cannot execute in docker", ContainerImageKey)
+	}
+	if cmd.ExecRoot == "" {
+		return nil, fmt.Errorf("no exec root set, cannot execute in docker")
+	}
+	args := []string{d.binary(), "run", "--rm"}
+	if cmd.Identifiers != nil && cmd.Identifiers.ExecutionID != "" {
+		args = append(args, "--name", containerName(cmd))
+	}
+	args = append(args,
+		"--volume", fmt.Sprintf("%s:%s", cmd.ExecRoot, cmd.ExecRoot),
+		"--workdir", filepath.Join(cmd.ExecRoot, cmd.WorkingDir),
+	)
+	if cmd.Platform[DockerNetworkKey] != "standard" {
+		args = append(args, "--network", "none")
+	}
+	if cmd.Platform[DockerRunAsRootKey] != "true" {
+		// Run as the current user so that outputs written to the exec root are owned by them.
+		if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 && gid >= 0 {
+			args = append(args, "--user", fmt.Sprintf("%d:%d", uid, gid))
+		}
+	}
+	if cmd.InputSpec != nil {
+		var env []string
+		for k, v := range cmd.InputSpec.EnvironmentVariables {
+			env = append(env, fmt.Sprintf("%s=%s", k, v))
+		}
+		sort.Strings(env)
+		for _, e := range env {
+			args = append(args, "--env", e)
+		}
+	}
+	args = append(args, image)
+	args = append(args, cmd.Args...)
+	// The runtime itself runs on the host with the host environment.
+	return &command.Command{
+		Args:        args,
+		ExecRoot:    cmd.ExecRoot,
+		WorkingDir:  cmd.WorkingDir,
+		Identifiers: cmd.Identifiers,
+	}, nil
+}
+
+func (d *DockerExecutor) runtime() ContainerRuntime {
+	if d.Runtime == nil {
+		return SystemExecutor{}
+	}
+	return d.Runtime
+}
+
+func (d *DockerExecutor) binary() string {
+	if d.Binary == "" {
+		return defaultDockerBinary
+	}
+	return d.Binary
+}
+
+func containerName(cmd *command.Command) string {
+	return "reproxy-" + cmd.Identifiers.ExecutionID
+}
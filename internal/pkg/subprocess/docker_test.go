@@ -0,0 +1,132 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
+	"github.com/bazelbuild/remote-apis-sdks/go/pkg/outerr"
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeRuntime struct {
+	cmds   []*command.Command
+	stdout string
+	err    error
+}
+
+func (f *fakeRuntime) ExecuteWithOutErr(ctx context.Context, cmd *command.Command, oe outerr.OutErr) error {
+	f.cmds = append(f.cmds, cmd)
+	oe.WriteOut([]byte(f.stdout))
+	return f.err
+}
+
+func TestDockerExecutor(t *testing.T) {
+	rt := &fakeRuntime{stdout: "hello"}
+	d := &DockerExecutor{Runtime: rt}
+	cmd := &command.Command{
+		Args:        []string{"clang", "-c", "foo.c"},
+		ExecRoot:    "/src",
+		WorkingDir:  "out",
+		Identifiers: &command.Identifiers{ExecutionID: "abc"},
+		Platform:    map[string]string{ContainerImageKey: "docker://gcr.io/img@sha256:1234"},
+		InputSpec: &command.InputSpec{
+			EnvironmentVariables: map[string]string{"B": "2", "A": "1"},
+		},
+	}
+	oe := outerr.NewRecordingOutErr()
+	if err := d.ExecuteWithOutErr(context.Background(), cmd, oe); err != nil {
+		t.Fatalf("ExecuteWithOutErr() returned error: %v", err)
+	}
+	if got := string(oe.Stdout()); got != "hello" {
+		t.Errorf("ExecuteWithOutErr() stdout = %q, want %q", got, "hello")
+	}
+	if len(rt.cmds) != 1 {
+		t.Fatalf("ExecuteWithOutErr() made %v runtime calls, want 1", len(rt.cmds))
+	}
+	want := []string{"docker", "run", "--rm", "--name", "reproxy-abc",
+		"--volume", "/src:/src", "--workdir", "/src/out", "--network", "none"}
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 && gid >= 0 {
+		want = append(want, "--user", fmt.Sprintf("%d:%d", uid, gid))
+	}
+	want = append(want, "--env", "A=1", "--env", "B=2", "gcr.io/img@sha256:1234", "clang", "-c", "foo.c")
+	if diff := cmp.Diff(want, rt.cmds[0].Args); diff != "" {
+		t.Errorf("ExecuteWithOutErr() ran wrong runtime command, diff (-want +got): %v", diff)
+	}
+	if rt.cmds[0].ExecRoot != "/src" || rt.cmds[0].WorkingDir != "out" {
+		t.Errorf("ExecuteWithOutErr() ran runtime in %v/%v, want /src/out", rt.cmds[0].ExecRoot, rt.cmds[0].WorkingDir)
+	}
+}
+
+func TestDockerExecutorPlatformOptions(t *testing.T) {
+	rt := &fakeRuntime{}
+	d := &DockerExecutor{Runtime: rt, Binary: "podman"}
+	cmd := &command.Command{
+		Args:     []string{"true"},
+		ExecRoot: "/src",
+		Platform: map[string]string{
+			ContainerImageKey:  "img",
+			DockerNetworkKey:   "standard",
+			DockerRunAsRootKey: "true",
+		},
+	}
+	if err := d.ExecuteWithOutErr(context.Background(), cmd, outerr.NewRecordingOutErr()); err != nil {
+		t.Fatalf("ExecuteWithOutErr() returned error: %v", err)
+	}
+	want := []string{"podman", "run", "--rm", "--volume", "/src:/src", "--workdir", "/src", "img", "true"}
+	if diff := cmp.Diff(want, rt.cmds[0].Args); diff != "" {
+		t.Errorf("ExecuteWithOutErr() ran wrong runtime command, diff (-want +got): %v", diff)
+	}
+}
+
+func TestDockerExecutorNoImage(t *testing.T) {
+	rt := &fakeRuntime{}
+	d := &DockerExecutor{Runtime: rt}
+	cmd := &command.Command{Args: []string{"true"}, ExecRoot: "/src"}
+	if err := d.ExecuteWithOutErr(context.Background(), cmd, outerr.NewRecordingOutErr()); err == nil {
+		t.Errorf("ExecuteWithOutErr() returned no error for a command without container image")
+	}
+	if len(rt.cmds) != 0 {
+		t.Errorf("ExecuteWithOutErr() made %v runtime calls, want 0", len(rt.cmds))
+	}
+}
+
+func TestDockerExecutorCancelRemovesContainer(t *testing.T) {
+	rt := &fakeRuntime{err: errors.New("killed")}
+	d := &DockerExecutor{Runtime: rt}
+	cmd := &command.Command{
+		Args:        []string{"sleep", "100"},
+		ExecRoot:    "/src",
+		Identifiers: &command.Identifiers{ExecutionID: "abc"},
+		Platform:    map[string]string{ContainerImageKey: "img"},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.ExecuteWithOutErr(ctx, cmd, outerr.NewRecordingOutErr()); err == nil {
+		t.Errorf("ExecuteWithOutErr() returned no error for a canceled command")
+	}
+	if len(rt.cmds) != 2 {
+		t.Fatalf("ExecuteWithOutErr() made %v runtime calls, want 2", len(rt.cmds))
+	}
+	want := []string{"docker", "rm", "-f", "reproxy-abc"}
+	if diff := cmp.Diff(want, rt.cmds[1].Args); diff != "" {
+		t.Errorf("ExecuteWithOutErr() ran wrong cleanup command, diff (-want +got): %v", diff)
+	}
+}
@@ -18,11 +18,15 @@ package subprocess
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/bazelbuild/reclient/internal/pkg/cgroups"
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/outerr"
 
@@ -46,16 +50,86 @@ func (SystemExecutor) Execute(ctx context.Context, cmd *command.Command) (string
 	return stdout.String(), stderr.String(), err
 }
 
+// ResourceUsage is the resource usage of a finished subprocess.
+type ResourceUsage struct {
+	// CPUTime is the total user and system CPU time of the subprocess and its waited-for
+	// children.
+	CPUTime time.Duration
+	// PeakRSSBytes is the maximum resident set size of the subprocess and its waited-for
+	// children, or 0 if it is not available on this platform.
+	PeakRSSBytes int64
+}
+
 // ExecuteWithOutErr runs the given command and returns stdout and stderr in an OutErr object.
 // Returns *exec.ExitError if the command ran with a non-zero exit code.
 func (SystemExecutor) ExecuteWithOutErr(ctx context.Context, cmd *command.Command, oe outerr.OutErr) error {
+	_, err := executeWithOutErr(ctx, cmd, oe, nil)
+	return err
+}
+
+// ExecuteWithUsage runs the given command, returns stdout and stderr in an OutErr object and
+// reports the resources used by the command. The usage is nil if the command failed to start.
+// Returns *exec.ExitError if the command ran with a non-zero exit code.
+func (SystemExecutor) ExecuteWithUsage(ctx context.Context, cmd *command.Command, oe outerr.OutErr) (*ResourceUsage, error) {
+	ps, err := executeWithOutErr(ctx, cmd, oe, nil)
+	return usageFromState(ps), err
+}
+
+// ExecuteInCgroup is like ExecuteWithUsage, but runs the command in the given cgroup. The command
+// is held once started until it has been moved to the cgroup, so that none of its processes
+// escape it. The command runs outside of the cgroup if it cannot be moved.
+func (SystemExecutor) ExecuteInCgroup(ctx context.Context, cmd *command.Command, oe outerr.OutErr, cg *cgroups.Group) (*ResourceUsage, error) {
+	if cg == nil {
+		ps, err := executeWithOutErr(ctx, cmd, oe, nil)
+		return usageFromState(ps), err
+	}
+	ps, err := executeWithOutErr(ctx, cmd, oe, func(pid int) {
+		if err := cg.Add(pid); err != nil {
+			log.Warningf("Failed to move command %v to cgroup, it will not be confined: %v", cmd.Args, err)
+		}
+	})
+	return usageFromState(ps), err
+}
+
+func usageFromState(ps *os.ProcessState) *ResourceUsage {
+	if ps == nil {
+		return nil
+	}
+	return &ResourceUsage{
+		CPUTime:      ps.UserTime() + ps.SystemTime(),
+		PeakRSSBytes: peakRSSBytes(ps),
+	}
+}
+
+// executeWithOutErr runs the given command, calling onStart, if not nil, with the pid of the
+// command once it has started. The command doesn't execute until onStart returns. The output of
+// the command is written to oe as it is produced. Returns the state of the finished process, or
+// nil if it failed to start.
+func executeWithOutErr(ctx context.Context, cmd *command.Command, oe outerr.OutErr, onStart func(pid int)) (*os.ProcessState, error) {
 	cmdCtx, stdout, stderr, err := setupCommand(ctx, cmd)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err = cmdCtx.Start(); err != nil {
+	cmdCtx.Stdout = io.MultiWriter(stdout, outerr.NewOutWriter(oe))
+	cmdCtx.Stderr = io.MultiWriter(stderr, outerr.NewErrWriter(oe))
+	var held, release *os.File
+	if onStart != nil {
+		if held, release, err = holdCommand(cmdCtx); err != nil {
+			return nil, err
+		}
+		defer release.Close()
+	}
+	err = cmdCtx.Start()
+	if held != nil {
+		held.Close()
+	}
+	if err != nil {
 		log.V(2).Infof("Starting command %v >> err=%v", cmd.Args, err)
-		return err
+		return nil, err
+	}
+	if onStart != nil {
+		onStart(cmdCtx.Process.Pid)
+		release.Close()
 	}
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
@@ -65,15 +139,29 @@ func (SystemExecutor) ExecuteWithOutErr(ctx context.Context, cmd *command.Comman
 		if err != nil {
 			log.V(2).Infof("Executed command %v\n >> err=%v", cmd.Args, err)
 		}
-		oe.WriteOut([]byte(stdout.String()))
-		oe.WriteErr([]byte(stderr.String()))
 		wg.Done()
 	}()
 	wg.Wait()
 	if err != nil {
 		log.V(2).Infof("Executed command %v\n >> stdout=%v\n >> stderr=%v\n >> err=%v", cmd.Args, stdout, stderr, err)
 	}
-	return err
+	return cmdCtx.ProcessState, err
+}
+
+// holdCommand makes cmd wait once started until the returned release file is closed. The
+// command is run through a shell that blocks reading the held end of a pipe, and then executes
+// the command in its place, keeping its pid. The held file must be closed once cmd has started.
+func holdCommand(cmd *exec.Cmd) (held, release *os.File, err error) {
+	held, release, err = os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	cmd.ExtraFiles = append(cmd.ExtraFiles, held)
+	fd := 2 + len(cmd.ExtraFiles)
+	script := fmt.Sprintf(`read -r _ <&%d; exec %d<&-; exec "$0" "$@"`, fd, fd)
+	cmd.Args = append([]string{"/bin/sh", "-c", script, cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = "/bin/sh"
+	return held, release, nil
 }
 
 // ExecuteInBackground executes the command in the background. Command result is written to the
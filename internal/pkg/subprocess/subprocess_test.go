@@ -16,9 +16,15 @@ package subprocess
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/bazelbuild/reclient/internal/pkg/cgroups"
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/command"
 	"github.com/bazelbuild/remote-apis-sdks/go/pkg/outerr"
 )
@@ -51,6 +57,91 @@ func TestExecuteWithOutErr(t *testing.T) {
 	}
 }
 
+func TestExecuteWithUsage(t *testing.T) {
+	oe := outerr.NewRecordingOutErr()
+	want := "Hello"
+	cmd := &command.Command{Args: []string{"bash", "-c", "for i in $(seq 100000); do :; done; echo " + want}}
+	usage, err := SystemExecutor{}.ExecuteWithUsage(context.Background(), cmd, oe)
+	if err != nil {
+		t.Errorf("ExecuteWithUsage(%v) failed with error: %v", cmd, err)
+	}
+	got := strings.TrimSpace(string(oe.Stdout()))
+	if got != want {
+		t.Errorf("ExecuteWithUsage(%v) stdout = %v, want %q", cmd, got, want)
+	}
+	if usage == nil {
+		t.Fatalf("ExecuteWithUsage(%v) returned nil usage", cmd)
+	}
+	if usage.CPUTime <= 0 {
+		t.Errorf("ExecuteWithUsage(%v) CPUTime = %v, want > 0", cmd, usage.CPUTime)
+	}
+	if runtime.GOOS != "windows" && usage.PeakRSSBytes <= 0 {
+		t.Errorf("ExecuteWithUsage(%v) PeakRSSBytes = %v, want > 0", cmd, usage.PeakRSSBytes)
+	}
+}
+
+func TestExecuteWithUsageStartFailure(t *testing.T) {
+	cmd := &command.Command{Args: []string{"non-existent-binary-for-test"}}
+	usage, err := SystemExecutor{}.ExecuteWithUsage(context.Background(), cmd, outerr.NewRecordingOutErr())
+	if err == nil {
+		t.Errorf("ExecuteWithUsage(%v) succeeded, want error", cmd)
+	}
+	if usage != nil {
+		t.Errorf("ExecuteWithUsage(%v) usage = %+v, want nil", cmd, usage)
+	}
+}
+
+func TestExecuteInCgroup(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("cgroups are only supported on linux")
+	}
+	// A fake cgroup hierarchy, which records the pid of the command without confining it.
+	cg, err := cgroups.New(t.TempDir()).NewGroup(1, 512)
+	if err != nil {
+		t.Fatalf("NewGroup() returned error: %v", err)
+	}
+	oe := outerr.NewRecordingOutErr()
+	cmd := &command.Command{Args: []string{"bash", "-c", "echo $$"}}
+	usage, err := SystemExecutor{}.ExecuteInCgroup(context.Background(), cmd, oe, cg)
+	if err != nil {
+		t.Errorf("ExecuteInCgroup(%v) failed with error: %v", cmd, err)
+	}
+	if usage == nil {
+		t.Errorf("ExecuteInCgroup(%v) returned nil usage", cmd)
+	}
+	procs, err := os.ReadFile(filepath.Join(cg.Path(), "cgroup.procs"))
+	if err != nil {
+		t.Fatalf("Failed to read cgroup.procs: %v", err)
+	}
+	if got, want := string(procs), strings.TrimSpace(string(oe.Stdout())); got != want {
+		t.Errorf("ExecuteInCgroup(%v) added pid %v to the cgroup, want %v", cmd, got, want)
+	}
+}
+
+func TestExecuteHeldUntilStarted(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("commands are only held on unix")
+	}
+	marker := filepath.Join(t.TempDir(), "marker")
+	oe := outerr.NewRecordingOutErr()
+	cmd := &command.Command{Args: []string{"bash", "-c", "cat " + marker + "; echo $$"}}
+	var gotPid int
+	_, err := executeWithOutErr(context.Background(), cmd, oe, func(pid int) {
+		gotPid = pid
+		// The command must not run before the pid is handled.
+		time.Sleep(100 * time.Millisecond)
+		if err := os.WriteFile(marker, []byte("started\n"), 0644); err != nil {
+			t.Errorf("Failed to write marker: %v", err)
+		}
+	})
+	if err != nil {
+		t.Fatalf("executeWithOutErr(%v) failed with error: %v, stderr: %s", cmd, err, oe.Stderr())
+	}
+	if got, want := string(oe.Stdout()), fmt.Sprintf("started\n%d\n", gotPid); got != want {
+		t.Errorf("executeWithOutErr(%v) stdout = %q, want %q", cmd, got, want)
+	}
+}
+
 func TestExecuteInBackground(t *testing.T) {
 	oe := outerr.NewRecordingOutErr()
 	want := "Hello"
@@ -35,11 +35,16 @@ import (
 	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/clanglint"
 	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/cppcompile"
 	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/d8"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/gcc"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/golang"
 	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/headerabi"
 	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/javac"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/kotlinc"
 	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/metalava"
 	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/nacl"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/protoc"
 	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/r8"
+	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/rustc"
 	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/tool"
 	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/action/typescript"
 	"github.com/bazelbuild/reclient/internal/pkg/inputprocessor/depscache"
@@ -71,16 +76,24 @@ var (
 	// primary input processor fails.
 	// The default behaviour is to fallback to shallow mode if a set of labels are NOT present
 	// in the following config.
-	// If a CPP compile and remote execution strategy is specified, shallow fallback will be disabled.
+	// If a CPP compile and remote or remote hedged execution strategy is specified, shallow
+	// fallback will be disabled.
 	shallowFallbackConfig = map[labels.Labels]map[ppb.ExecutionStrategy_Value]bool{
 		labels.HeaderAbiDumpLabels(): {ppb.ExecutionStrategy_UNSPECIFIED: false},
 		labels.ClangLintLabels():     {ppb.ExecutionStrategy_UNSPECIFIED: false},
 		labels.ClangCppLabels(): {ppb.ExecutionStrategy_REMOTE: false,
+			ppb.ExecutionStrategy_REMOTE_HEDGED:         false,
 			ppb.ExecutionStrategy_REMOTE_LOCAL_FALLBACK: false},
 		labels.ClangCLCppLabels(): {ppb.ExecutionStrategy_REMOTE: false,
+			ppb.ExecutionStrategy_REMOTE_HEDGED:         false,
 			ppb.ExecutionStrategy_REMOTE_LOCAL_FALLBACK: false},
 		labels.NaClLabels(): {ppb.ExecutionStrategy_REMOTE: false,
+			ppb.ExecutionStrategy_REMOTE_HEDGED:         false,
 			ppb.ExecutionStrategy_REMOTE_LOCAL_FALLBACK: false},
+		// GCC compiles with flags the dependency scanner doesn't understand still use shallow
+		// mode unless they must execute remotely.
+		labels.GccCppLabels(): {ppb.ExecutionStrategy_REMOTE: false,
+			ppb.ExecutionStrategy_REMOTE_HEDGED: false},
 	}
 )
 
@@ -315,6 +328,10 @@ func (p *InputProcessor) ProcessInputs(ctx context.Context, opts *ProcessInputsO
 		pp = &nacl.Preprocessor{
 			Preprocessor: cp,
 		}
+	case labels.GccCppLabels():
+		pp = &gcc.Preprocessor{
+			Preprocessor: cp,
+		}
 	case labels.ClangLinkLabels():
 		pp = &clanglink.Preprocessor{
 			BasePreprocessor: bp,
@@ -329,6 +346,10 @@ func (p *InputProcessor) ProcessInputs(ctx context.Context, opts *ProcessInputsO
 		pp = &javac.Preprocessor{
 			BasePreprocessor: bp,
 		}
+	case labels.KotlincLabels():
+		pp = &kotlinc.Preprocessor{
+			BasePreprocessor: bp,
+		}
 	case labels.LLVMArLabels():
 		pp = &archive.Preprocessor{
 			BasePreprocessor: bp,
@@ -337,6 +358,18 @@ func (p *InputProcessor) ProcessInputs(ctx context.Context, opts *ProcessInputsO
 		pp = &typescript.Preprocessor{
 			BasePreprocessor: bp,
 		}
+	case labels.RustcLabels():
+		pp = &rustc.Preprocessor{
+			BasePreprocessor: bp,
+		}
+	case labels.GoCompileLabels(), labels.GoLinkLabels():
+		pp = &golang.Preprocessor{
+			BasePreprocessor: bp,
+		}
+	case labels.ProtocLabels():
+		pp = &protoc.Preprocessor{
+			BasePreprocessor: bp,
+		}
 	}
 	if pp != nil {
 		ch := make(chan bool)
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -324,6 +325,49 @@ func TestNoCppShallowFallbackWithRemoteLocalFallback(t *testing.T) {
 	}
 }
 
+func TestNoCppShallowFallbackWithRemoteHedged(t *testing.T) {
+	tests := []struct {
+		name string
+		lbls labels.Labels
+	}{
+		{name: "clang", lbls: labels.ClangCppLabels()},
+		{name: "clang-cl", lbls: labels.ClangCLCppLabels()},
+		{name: "nacl", lbls: labels.NaClLabels()},
+		{name: "gcc", lbls: labels.GccCppLabels()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			ds := &stubCPPDependencyScanner{
+				processInputsError: errors.New("failed to call clang-scan-deps"),
+			}
+			resMgr := localresources.NewDefaultManager()
+			ip := newInputProcessor(ds, dsTimeout, false, nil, resMgr, nil, nil)
+			existingFiles := []string{
+				filepath.Clean("wd/libc++.so.1"),
+				filepath.Clean("wd/test.cpp"),
+			}
+			er, cleanup := execroot.Setup(t, append(existingFiles, filepath.Join(wd, "remote_toolchain_inputs")))
+			defer cleanup()
+
+			cmd := []string{"clang++", "-c", "-o", "test.o", "-MF", "test.d", "test.cpp"}
+			i := &command.InputSpec{Inputs: []string{filepath.Clean("wd/libc++.so.1")}}
+			opts := &ProcessInputsOptions{
+				ExecutionID:  fakeExecutionID,
+				Cmd:          cmd,
+				WorkingDir:   wd,
+				ExecRoot:     er,
+				Inputs:       i,
+				Labels:       labels.ToMap(test.lbls),
+				ExecStrategy: ppb.ExecutionStrategy_REMOTE_HEDGED,
+			}
+			if _, err := ip.ProcessInputs(ctx, opts, &logger.LogRecord{LogRecord: &lpb.LogRecord{}}); err == nil {
+				t.Errorf("ProcessInputs(%+v) did shallow fall back when remote hedged CPP compile specified", opts)
+			}
+		})
+	}
+}
+
 func TestCppShallowFallbackWithLocal(t *testing.T) {
 	ctx := context.Background()
 	ds := &stubCPPDependencyScanner{
@@ -369,6 +413,54 @@ func TestCppShallowFallbackWithLocal(t *testing.T) {
 	}
 }
 
+func TestGccUnsupportedFlagsShallowFallback(t *testing.T) {
+	ctx := context.Background()
+	ds := &stubCPPDependencyScanner{}
+	resMgr := localresources.NewDefaultManager()
+	ip := newInputProcessor(ds, dsTimeout, false, nil, resMgr, nil, nil)
+	existingFiles := []string{
+		filepath.Clean("wd/libc++.so.1"),
+		filepath.Clean("wd/test.cpp"),
+	}
+	er, cleanup := execroot.Setup(t, append(existingFiles, filepath.Join(wd, "remote_toolchain_inputs")))
+	defer cleanup()
+
+	cmd := []string{"g++", "-imultiarch", "x86_64-linux-gnu", "-c", "-o", "test.o", "-MF", "test.d", "test.cpp"}
+	lbls := map[string]string{
+		"type":     "compile",
+		"compiler": "gcc",
+		"lang":     "cpp",
+	}
+	i := &command.InputSpec{Inputs: []string{filepath.Clean("wd/libc++.so.1")}}
+	opts := &ProcessInputsOptions{
+		ExecutionID:  fakeExecutionID,
+		Cmd:          cmd,
+		WorkingDir:   wd,
+		ExecRoot:     er,
+		Inputs:       i,
+		Labels:       lbls,
+		ExecStrategy: ppb.ExecutionStrategy_REMOTE_LOCAL_FALLBACK,
+	}
+	gotIO, err := ip.ProcessInputs(ctx, opts, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
+	if err != nil {
+		t.Errorf("ProcessInputs(%+v).err = %v, want no error.", opts, err)
+	}
+	wantIO := &CommandIO{
+		InputSpec:             &command.InputSpec{Inputs: existingFiles},
+		OutputFiles:           []string{filepath.Clean("wd/test.d"), filepath.Clean("wd/test.o")},
+		EmittedDependencyFile: filepath.Clean("wd/test.d"),
+		UsedShallowMode:       true,
+	}
+	if diff := cmp.Diff(wantIO, gotIO, strSliceCmp); diff != "" {
+		t.Errorf("ProcessInputs(%v) returned diff in CommandIO, (-want +got): %s", opts, diff)
+	}
+
+	opts.ExecStrategy = ppb.ExecutionStrategy_REMOTE
+	if _, err := ip.ProcessInputs(ctx, opts, &logger.LogRecord{LogRecord: &lpb.LogRecord{}}); err == nil {
+		t.Errorf("ProcessInputs(%+v) did shallow fall back when remote GCC compile specified", opts)
+	}
+}
+
 func TestHeaderABIDumper(t *testing.T) {
 	ctx := context.Background()
 	ds := &stubCPPDependencyScanner{
@@ -557,6 +649,176 @@ func TestMetalavaInputsUnderSourcePath(t *testing.T) {
 	}
 }
 
+func TestKotlinc(t *testing.T) {
+	ctx := context.Background()
+	resMgr := localresources.NewDefaultManager()
+	ip := newInputProcessor(nil, dsTimeout, false, nil, resMgr, nil, nil)
+	existingFiles := []string{
+		filepath.Clean("wd/Foo.kt"),
+		filepath.Clean("wd/lib/a.jar"),
+		filepath.Clean("wd/friend.jar"),
+		filepath.Clean("wd/jdk/bin/java"),
+	}
+	er, cleanup := execroot.Setup(t, append(existingFiles, filepath.Join(wd, "remote_toolchain_inputs")))
+	defer cleanup()
+
+	cmd := []string{"kotlinc", "-cp", "lib/a.jar", "-Xfriend-paths=friend.jar", "-jdk-home", "jdk", "-d", "out/classes", "Foo.kt"}
+	opts := &ProcessInputsOptions{
+		ExecutionID: fakeExecutionID,
+		Cmd:         cmd,
+		WorkingDir:  wd,
+		ExecRoot:    er,
+		Labels:      labels.ToMap(labels.KotlincLabels()),
+	}
+	gotIO, err := ip.ProcessInputs(ctx, opts, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
+	if err != nil {
+		t.Errorf("ProcessInputs(%+v).err = %v, want no error.", opts, err)
+	}
+	wantIO := &CommandIO{
+		InputSpec: &command.InputSpec{
+			Inputs: []string{
+				filepath.Clean("wd/Foo.kt"),
+				filepath.Clean("wd/lib/a.jar"),
+				filepath.Clean("wd/friend.jar"),
+				filepath.Clean("wd/jdk"),
+			},
+		},
+		OutputDirectories: []string{filepath.Clean("wd/out/classes")},
+	}
+	if diff := cmp.Diff(wantIO, gotIO, strSliceCmp); diff != "" {
+		t.Errorf("ProcessInputs(%v) returned diff in CommandIO, (-want +got): %s", opts, diff)
+	}
+}
+
+func TestRustc(t *testing.T) {
+	ctx := context.Background()
+	resMgr := localresources.NewDefaultManager()
+	ip := newInputProcessor(nil, dsTimeout, false, nil, resMgr, nil, nil)
+	existingFiles := []string{
+		filepath.Clean("wd/src/lib.rs"),
+		filepath.Clean("wd/src/foo.rs"),
+		filepath.Clean("wd/deps/libbar.rlib"),
+	}
+	er, cleanup := execroot.Setup(t, append(existingFiles, filepath.Join(wd, "remote_toolchain_inputs")))
+	defer cleanup()
+	past := time.Now().Add(-time.Hour)
+	for _, f := range existingFiles {
+		if err := os.Chtimes(filepath.Join(er, f), past, past); err != nil {
+			t.Fatalf("Chtimes(%v) failed: %v", f, err)
+		}
+	}
+	execroot.AddFileWithContent(t, filepath.Join(er, wd, "out", "foo.d"), []byte("out/foo.d: src/lib.rs src/foo.rs\n\nsrc/lib.rs:\nsrc/foo.rs:\n"))
+
+	cmd := []string{"rustc", "--crate-name=foo", "--crate-type=rlib", "--emit=dep-info,link", "--out-dir=out", "--extern", "bar=deps/libbar.rlib", "src/lib.rs"}
+	opts := &ProcessInputsOptions{
+		ExecutionID: fakeExecutionID,
+		Cmd:         cmd,
+		WorkingDir:  wd,
+		ExecRoot:    er,
+		Labels:      labels.ToMap(labels.RustcLabels()),
+	}
+	gotIO, err := ip.ProcessInputs(ctx, opts, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
+	if err != nil {
+		t.Errorf("ProcessInputs(%+v).err = %v, want no error.", opts, err)
+	}
+	wantIO := &CommandIO{
+		InputSpec:             &command.InputSpec{Inputs: []string{filepath.Clean("wd/src/lib.rs"), filepath.Clean("wd/src/foo.rs"), filepath.Clean("wd/deps/libbar.rlib")}},
+		OutputFiles:           []string{filepath.Clean("wd/out/foo.d"), filepath.Clean("wd/out/libfoo.rlib")},
+		EmittedDependencyFile: filepath.Clean("wd/out/foo.d"),
+	}
+	if diff := cmp.Diff(wantIO, gotIO, strSliceCmp); diff != "" {
+		t.Errorf("ProcessInputs(%v) returned diff in CommandIO, (-want +got): %s", opts, diff)
+	}
+}
+
+func TestProtoc(t *testing.T) {
+	ctx := context.Background()
+	resMgr := localresources.NewDefaultManager()
+	ip := newInputProcessor(nil, dsTimeout, false, nil, resMgr, nil, nil)
+	existingFiles := []string{
+		filepath.Clean("wd/protos/bar.proto"),
+		filepath.Clean("wd/plugins/protoc-gen-go"),
+	}
+	er, cleanup := execroot.Setup(t, append(existingFiles, filepath.Join(wd, "remote_toolchain_inputs")))
+	defer cleanup()
+	execroot.AddFileWithContent(t, filepath.Join(er, wd, "protos", "foo.proto"), []byte(`import "bar.proto";`))
+
+	cmd := []string{"protoc", "-Iprotos", "--plugin=protoc-gen-go=plugins/protoc-gen-go", "--go_out=gen", "foo.proto"}
+	opts := &ProcessInputsOptions{
+		ExecutionID: fakeExecutionID,
+		Cmd:         cmd,
+		WorkingDir:  wd,
+		ExecRoot:    er,
+		Labels:      labels.ToMap(labels.ProtocLabels()),
+	}
+	gotIO, err := ip.ProcessInputs(ctx, opts, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
+	if err != nil {
+		t.Errorf("ProcessInputs(%+v).err = %v, want no error.", opts, err)
+	}
+	wantIO := &CommandIO{
+		InputSpec: &command.InputSpec{
+			Inputs: []string{
+				filepath.Clean("wd/protos/foo.proto"),
+				filepath.Clean("wd/protos/bar.proto"),
+				filepath.Clean("wd/plugins/protoc-gen-go"),
+			},
+			VirtualInputs: []*command.VirtualInput{{Path: filepath.Clean("wd/protos"), IsEmptyDirectory: true}},
+		},
+		OutputDirectories: []string{filepath.Clean("wd/gen")},
+	}
+	if diff := cmp.Diff(wantIO, gotIO, strSliceCmp); diff != "" {
+		t.Errorf("ProcessInputs(%v) returned diff in CommandIO, (-want +got): %s", opts, diff)
+	}
+}
+
+func TestGoCompile(t *testing.T) {
+	ctx := context.Background()
+	resMgr := localresources.NewDefaultManager()
+	ip := newInputProcessor(nil, dsTimeout, false, nil, resMgr, nil, nil)
+	exe := ""
+	if runtime.GOOS == "windows" {
+		exe = ".exe"
+	}
+	tool := filepath.Join("goroot", "pkg", "tool", runtime.GOOS+"_"+runtime.GOARCH, "compile"+exe)
+	existingFiles := []string{
+		filepath.Clean("goroot/bin/go"),
+		tool,
+		filepath.Clean("wd/foo.go"),
+		filepath.Clean("wd/pkg/fmt.a"),
+	}
+	er, cleanup := execroot.Setup(t, append(existingFiles, filepath.Join(wd, "remote_toolchain_inputs")))
+	defer cleanup()
+	execroot.AddFileWithContent(t, filepath.Join(er, wd, "foo.importcfg"), []byte("packagefile fmt=pkg/fmt.a\n"))
+
+	cmd := []string{"../goroot/bin/go", "tool", "compile", "-o", "foo.a", "-p", "main", "-importcfg", "foo.importcfg", "foo.go"}
+	opts := &ProcessInputsOptions{
+		ExecutionID: fakeExecutionID,
+		Cmd:         cmd,
+		WorkingDir:  wd,
+		ExecRoot:    er,
+		Labels:      labels.ToMap(labels.GoCompileLabels()),
+	}
+	gotIO, err := ip.ProcessInputs(ctx, opts, &logger.LogRecord{LogRecord: &lpb.LogRecord{}})
+	if err != nil {
+		t.Errorf("ProcessInputs(%+v).err = %v, want no error.", opts, err)
+	}
+	wantIO := &CommandIO{
+		InputSpec: &command.InputSpec{
+			Inputs: []string{
+				filepath.Clean("goroot/bin/go"),
+				tool,
+				filepath.Clean("wd/foo.go"),
+				filepath.Clean("wd/foo.importcfg"),
+				filepath.Clean("wd/pkg/fmt.a"),
+			},
+		},
+		OutputFiles: []string{filepath.Clean("wd/foo.a")},
+	}
+	if diff := cmp.Diff(wantIO, gotIO, strSliceCmp); diff != "" {
+		t.Errorf("ProcessInputs(%v) returned diff in CommandIO, (-want +got): %s", opts, diff)
+	}
+}
+
 func TestIncludeDirectoriesWithNoInputs_VirtualInputsAdded(t *testing.T) {
 	tests := []struct {
 		cmd   []string